@@ -0,0 +1,120 @@
+package mpris
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// ErrRemoteArt is returned by GetCoverPath when the cover art is not a local
+// file, e.g. an http(s) URL or a data: URI.
+var ErrRemoteArt = errors.New("mpris: cover art is not a local file")
+
+// ErrArtNotFound is returned by GetCoverPath and FetchCoverArt when the cover
+// art is a local file that doesn't exist. The error names the path tried.
+var ErrArtNotFound = errors.New("mpris: cover art file not found")
+
+// parseURL parses a URL reported in the metadata. Some players report plain
+// absolute paths instead of file:// URLs; those are returned as file URLs.
+// The whitespace some players wrap data: URIs with is dropped.
+func parseURL(s string) (*url.URL, error) {
+	if filepath.IsAbs(s) {
+		return &url.URL{Scheme: "file", Path: s}, nil
+	}
+	if isDataURI(s) {
+		s = stripSpace(s)
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("%s.Metadata: invalid URL %q: %w", PlayerInterface, s, err)
+	}
+	return u, nil
+}
+
+// GetURLParsed returns the URL of the current track, parsed.
+func (i *Player) GetURLParsed() (*url.URL, error) {
+	s, err := i.GetURL()
+	if err != nil {
+		return nil, err
+	}
+	return parseURL(s)
+}
+
+// GetCoverURLParsed returns the cover art URL of the current track, parsed.
+// Art embedded as a data: URI, as browsers and some Electron apps report it,
+// has the scheme "data" and is decoded by DecodeDataURL.
+func (i *Player) GetCoverURLParsed() (*url.URL, error) {
+	s, err := i.GetCoverURL()
+	if err != nil {
+		return nil, err
+	}
+	return parseURL(s)
+}
+
+// localPath returns the filesystem path of a file URL. Percent-encoded
+// characters are decoded, so the path can be opened directly. File URLs have
+// no use for a query or fragment, so an unescaped '?' or '#', which some
+// players leave in, is taken as part of the path.
+func localPath(u *url.URL) (string, error) {
+	switch u.Scheme {
+	case "file":
+		if u.Host != "" && u.Host != "localhost" {
+			return "", fmt.Errorf("%w: %s", ErrRemoteArt, u)
+		}
+		path := u.Path
+		if u.ForceQuery || u.RawQuery != "" {
+			query, err := url.PathUnescape(u.RawQuery)
+			if err != nil {
+				query = u.RawQuery
+			}
+			path += "?" + query
+		}
+		if u.Fragment != "" {
+			path += "#" + u.Fragment
+		}
+		return filepath.FromSlash(path), nil
+	case "http", "https":
+		return "", fmt.Errorf("%w: %s", ErrRemoteArt, u)
+	case "data":
+		return "", fmt.Errorf("%w: embedded in a data: URI", ErrRemoteArt)
+	default:
+		return "", fmt.Errorf("unsupported art URL scheme %q", u.Scheme)
+	}
+}
+
+// artNotFound returns the error for art at path that can't be found, wrapping
+// ErrArtNotFound and, when there is one, the error of the filesystem.
+func artNotFound(path string, err error) error {
+	if err == nil {
+		return fmt.Errorf("%w: %s", ErrArtNotFound, path)
+	}
+	return fmt.Errorf("%w: %s: %w", ErrArtNotFound, path, err)
+}
+
+// GetCoverPath returns the filesystem path of the cover art of the current
+// track. file:// URLs and plain absolute paths are accepted. It returns an
+// error wrapping ErrRemoteArt when the art is an http(s) URL, and one wrapping
+// ErrArtNotFound, naming the path, when the file doesn't exist.
+func (i *Player) GetCoverPath() (string, error) {
+	u, err := i.GetCoverURLParsed()
+	if err != nil {
+		return "", err
+	}
+	path, err := localPath(u)
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return "", artNotFound(path, err)
+	case err != nil:
+		return "", err
+	case info.IsDir():
+		return "", artNotFound(path, nil)
+	}
+	return path, nil
+}
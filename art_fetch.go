@@ -0,0 +1,217 @@
+package mpris
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// ErrArtTooLarge is returned by FetchCoverArt when the cover art is larger than
+// the size cap.
+var ErrArtTooLarge = errors.New("mpris: cover art exceeds size limit")
+
+// DefaultMaxArtSize is the default size cap of FetchCoverArt.
+const DefaultMaxArtSize = 16 << 20
+
+// fetchArtOptions are the options of FetchCoverArt.
+type fetchArtOptions struct {
+	maxSize int64
+	client  *http.Client
+}
+
+// FetchArtOption configures FetchCoverArt.
+type FetchArtOption func(*fetchArtOptions)
+
+// WithMaxArtSize limits the size of the cover art FetchCoverArt reads to n
+// bytes. Larger art fails with ErrArtTooLarge.
+func WithMaxArtSize(n int64) FetchArtOption {
+	return func(o *fetchArtOptions) { o.maxSize = n }
+}
+
+// WithHTTPClient makes FetchCoverArt download http(s) art with client instead
+// of http.DefaultClient.
+func WithHTTPClient(client *http.Client) FetchArtOption {
+	return func(o *fetchArtOptions) { o.client = client }
+}
+
+// FetchCoverArt reads the cover art of the current track and returns its bytes
+// and content type. file:// URLs (and plain paths), http(s) URLs and data: URIs
+// are supported; ctx controls http(s) requests. At most DefaultMaxArtSize bytes
+// are read unless WithMaxArtSize says otherwise.
+func (i *Player) FetchCoverArt(
+	ctx context.Context,
+	opts ...FetchArtOption,
+) ([]byte, string, error) {
+	o := fetchArtOptions{maxSize: DefaultMaxArtSize, client: http.DefaultClient}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	s, err := i.GetCoverURL()
+	if err != nil {
+		return nil, "", err
+	}
+	if isDataURI(s) {
+		return decodeDataURI(s, o.maxSize)
+	}
+
+	u, err := parseURL(s)
+	if err != nil {
+		return nil, "", err
+	}
+	if u.Scheme == "http" || u.Scheme == "https" {
+		return fetchHTTPArt(ctx, o, u)
+	}
+	path, err := localPath(u)
+	if err != nil {
+		return nil, "", err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, "", artNotFound(path, err)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	defer f.Close()
+	data, err := readArt(f, o.maxSize)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, http.DetectContentType(data), nil
+}
+
+// readArt reads r, failing with ErrArtTooLarge when it holds more than limit
+// bytes.
+func readArt(r io.Reader, limit int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, ErrArtTooLarge
+	}
+	return data, nil
+}
+
+// fetchHTTPArt downloads u. The content type is taken from the response and
+// detected from the data when the server doesn't send one.
+func fetchHTTPArt(
+	ctx context.Context,
+	o fetchArtOptions,
+	u *url.URL,
+) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, "", err
+	}
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, "", fmt.Errorf("fetching cover art %s: %s", u, resp.Status)
+	}
+	if resp.ContentLength > o.maxSize {
+		return nil, "", ErrArtTooLarge
+	}
+
+	data, err := readArt(resp.Body, o.maxSize)
+	if err != nil {
+		return nil, "", err
+	}
+	contentType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		contentType = http.DetectContentType(data)
+	}
+	return data, contentType, nil
+}
+
+// isDataURI reports whether s is a data: URI, whose scheme, like any other,
+// is case-insensitive.
+func isDataURI(s string) bool {
+	return len(s) >= len("data:") && strings.EqualFold(s[:len("data:")], "data:")
+}
+
+// stripSpace removes the whitespace from s, which players wrapping long
+// base64 payloads leave in their data: URIs.
+func stripSpace(s string) string {
+	if !strings.ContainsFunc(s, unicode.IsSpace) {
+		return s
+	}
+	return strings.Join(strings.Fields(s), "")
+}
+
+// DecodeDataURL decodes the data: URI u, as returned by GetCoverURLParsed for
+// players that embed the cover art in mpris:artUrl, and returns its payload
+// and media type. Payloads larger than DefaultMaxArtSize fail with
+// ErrArtTooLarge.
+func DecodeDataURL(u *url.URL) ([]byte, string, error) {
+	if !strings.EqualFold(u.Scheme, "data") {
+		return nil, "", fmt.Errorf("not a data URI: %s", u.Redacted())
+	}
+	return decodeDataURI(u.String(), DefaultMaxArtSize)
+}
+
+// decodeDataURI decodes a data: URI (RFC 2397). A missing media type defaults
+// to text/plain, as the RFC says, unless the data is recognizable. Base64
+// payloads may be wrapped and percent-encoded.
+func decodeDataURI(s string, limit int64) ([]byte, string, error) {
+	header, payload, ok := strings.Cut(s[len("data:"):], ",")
+	if !ok {
+		return nil, "", errors.New("invalid data URI: missing comma")
+	}
+
+	var data []byte
+	var err error
+	contentType, isBase64 := cutSuffixFold(header, ";base64")
+	if isBase64 {
+		payload = stripSpace(payload)
+		if strings.Contains(payload, "%") {
+			if unescaped, err := url.PathUnescape(payload); err == nil {
+				payload = unescaped
+			}
+		}
+		if int64(base64.StdEncoding.DecodedLen(len(payload))) > limit+2 {
+			return nil, "", ErrArtTooLarge
+		}
+		data, err = base64.StdEncoding.DecodeString(payload)
+		if err != nil {
+			// Some players drop the padding.
+			data, err = base64.RawStdEncoding.DecodeString(payload)
+		}
+	} else {
+		var text string
+		text, err = url.PathUnescape(payload)
+		data = []byte(text)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid data URI: %w", err)
+	}
+	if int64(len(data)) > limit {
+		return nil, "", ErrArtTooLarge
+	}
+
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return data, mediaType, nil
+	}
+	return data, http.DetectContentType(data), nil
+}
+
+// cutSuffixFold is like strings.CutSuffix, but ignores case.
+func cutSuffixFold(s, suffix string) (string, bool) {
+	if len(s) < len(suffix) || !strings.EqualFold(s[len(s)-len(suffix):], suffix) {
+		return s, false
+	}
+	return s[:len(s)-len(suffix)], true
+}
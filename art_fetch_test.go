@@ -0,0 +1,141 @@
+package mpris
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// pngHeader is enough of a PNG for content type detection.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestFetchCoverArtFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cover art.png")
+	if err := os.WriteFile(path, pngHeader, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	player := testMetadataPlayer(t, map[string]any{
+		"mpris:artUrl": "file://" + filepath.ToSlash(filepath.Dir(path)) + "/cover%20art.png",
+	})
+
+	data, contentType, err := player.FetchCoverArt(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, pngHeader) || contentType != "image/png" {
+		t.Errorf("FetchCoverArt() = %q, %q", data, contentType)
+	}
+
+	_, _, err = player.FetchCoverArt(context.Background(), WithMaxArtSize(4))
+	if !errors.Is(err, ErrArtTooLarge) {
+		t.Errorf("FetchCoverArt with a small cap error = %v, want ErrArtTooLarge", err)
+	}
+}
+
+func TestFetchCoverArtHTTP(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/art" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "image/jpeg; charset=binary")
+		w.Write([]byte("jpeg bytes"))
+	}))
+	defer srv.Close()
+
+	player := testMetadataPlayer(t, map[string]any{"mpris:artUrl": srv.URL + "/art"})
+	data, contentType, err := player.FetchCoverArt(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "jpeg bytes" || contentType != "image/jpeg" {
+		t.Errorf("FetchCoverArt() = %q, %q", data, contentType)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := player.FetchCoverArt(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("FetchCoverArt with canceled context error = %v", err)
+	}
+}
+
+func TestFetchCoverArtHTTPStatus(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	player := testMetadataPlayer(t, map[string]any{"mpris:artUrl": srv.URL + "/missing"})
+	if _, _, err := player.FetchCoverArt(context.Background()); err == nil {
+		t.Error("FetchCoverArt returned no error for a 404")
+	}
+}
+
+func TestDecodeDataURI(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString(pngHeader)
+	tests := []struct {
+		name        string
+		uri         string
+		want        []byte
+		contentType string
+	}{
+		{"base64", "data:image/png;base64," + encoded, pngHeader, "image/png"},
+		{"unpadded", "data:image/png;base64," + base64.RawStdEncoding.EncodeToString(pngHeader), pngHeader, "image/png"},
+		{"no media type", "data:;base64," + encoded, pngHeader, "image/png"},
+		{"escaped", "data:image/svg+xml,%3Csvg%2F%3E", []byte("<svg/>"), "image/svg+xml"},
+		{"wrapped", "data:image/png;base64," + encoded[:8] + "\n " + encoded[8:], pngHeader, "image/png"},
+		{"percent-encoded", "data:image/png;base64," + url.PathEscape(encoded), pngHeader, "image/png"},
+		{"uppercase", "DATA:image/jpeg;BASE64," + encoded, pngHeader, "image/jpeg"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, contentType, err := decodeDataURI(tt.uri, DefaultMaxArtSize)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(data, tt.want) || contentType != tt.contentType {
+				t.Errorf("decodeDataURI() = %q, %q", data, contentType)
+			}
+		})
+	}
+
+	if _, _, err := decodeDataURI("data:image/png;base64,"+encoded, 4); !errors.Is(err, ErrArtTooLarge) {
+		t.Errorf("decodeDataURI with a small cap error = %v, want ErrArtTooLarge", err)
+	}
+	if _, _, err := decodeDataURI("data:image/png", DefaultMaxArtSize); err == nil {
+		t.Error("decodeDataURI accepted a URI without data")
+	}
+}
+
+func TestCoverArtDataURI(t *testing.T) {
+	art := "data:image/jpeg;base64,\n" + base64.StdEncoding.EncodeToString(pngHeader)
+	player := testMetadataPlayer(t, map[string]any{"mpris:artUrl": art})
+
+	u, err := player.GetCoverURLParsed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, contentType, err := DecodeDataURL(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, pngHeader) || contentType != "image/jpeg" {
+		t.Errorf("DecodeDataURL() = %q, %q", data, contentType)
+	}
+
+	data, contentType, err = player.FetchCoverArt(context.Background())
+	if err != nil || !bytes.Equal(data, pngHeader) || contentType != "image/jpeg" {
+		t.Errorf("FetchCoverArt() = %q, %q, %v", data, contentType, err)
+	}
+	if _, err := player.GetCoverPath(); !errors.Is(err, ErrRemoteArt) {
+		t.Errorf("GetCoverPath() error = %v, want ErrRemoteArt", err)
+	}
+	if _, _, err := DecodeDataURL(&url.URL{Scheme: "https", Host: "example.com"}); err == nil {
+		t.Error("DecodeDataURL accepted an https URL")
+	}
+}
@@ -0,0 +1,108 @@
+package mpris
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGetCoverPath(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name string
+		art  string
+		want string
+	}{
+		{"file", "file://" + dir + "/cover.png", "cover.png"},
+		{"escaped spaces", "file://" + dir + "/My%20Music/cover%20art.jpg", "My Music/cover art.jpg"},
+		{"rhythmbox unicode", "file://" + dir + "/album-art/%E3%81%82%E3%82%8B.jpg", "album-art/ある.jpg"},
+		{"lollypop raw unicode", "file://" + dir + "/lollypop/Björk_Homogenic.jpg", "lollypop/Björk_Homogenic.jpg"},
+		{"localhost", "file://localhost" + dir + "/a.png", "a.png"},
+		{"unescaped hash", "file://" + dir + "/art #1?.png", "art #1?.png"},
+		{"bare path", dir + "/a b.png", "a b.png"},
+		{"bare path with percent", dir + "/100%25.png", "100%25.png"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := filepath.Join(dir, tt.want)
+			if err := os.MkdirAll(filepath.Dir(want), 0o700); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(want, nil, 0o600); err != nil {
+				t.Fatal(err)
+			}
+			player := testMetadataPlayer(t, map[string]any{"mpris:artUrl": tt.art})
+			got, err := player.GetCoverPath()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Errorf("GetCoverPath() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestGetCoverPathNotFound(t *testing.T) {
+	dir := t.TempDir()
+	for _, art := range []string{
+		"file://" + dir + "/missing%20art.png",
+		dir + "/missing art.png",
+		dir,
+	} {
+		player := testMetadataPlayer(t, map[string]any{"mpris:artUrl": art})
+		_, err := player.GetCoverPath()
+		if !errors.Is(err, ErrArtNotFound) {
+			t.Errorf("GetCoverPath() for %q error = %v, want ErrArtNotFound", art, err)
+			continue
+		}
+		if !strings.Contains(err.Error(), dir) {
+			t.Errorf("GetCoverPath() error = %v, want the path tried", err)
+		}
+	}
+
+	player := testMetadataPlayer(t, map[string]any{"mpris:artUrl": dir + "/missing.png"})
+	_, _, err := player.FetchCoverArt(context.Background())
+	if !errors.Is(err, ErrArtNotFound) || !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("FetchCoverArt() error = %v, want ErrArtNotFound", err)
+	}
+}
+
+func TestGetCoverPathRemote(t *testing.T) {
+	for _, art := range []string{"https://i.scdn.co/image/ab67", "http://example.com/a.png", "file://nas/share/a.png"} {
+		u, err := parseURL(art)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := localPath(u); !errors.Is(err, ErrRemoteArt) {
+			t.Errorf("localPath(%q) error = %v, want ErrRemoteArt", art, err)
+		}
+	}
+}
+
+func TestGetURLParsed(t *testing.T) {
+	player := testMetadataPlayer(t, map[string]any{
+		"xesam:url":    "https://www.youtube.com/watch?v=abc",
+		"mpris:artUrl": "file:///tmp/art%231.png",
+	})
+
+	u, err := player.GetURLParsed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Scheme != "https" || u.Query().Get("v") != "abc" {
+		t.Errorf("GetURLParsed() = %v", u)
+	}
+
+	art, err := player.GetCoverURLParsed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if art.Scheme != "file" || art.Path != "/tmp/art#1.png" {
+		t.Errorf("GetCoverURLParsed() = %#v", art)
+	}
+}
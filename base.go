@@ -1,71 +1,263 @@
 package mpris
 
-import "github.com/spf13/cast"
+import (
+	"context"
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/spf13/cast"
+)
+
+// serviceUnknownError is the D-Bus error name of a call to a name nobody owns
+// or can activate.
+const serviceUnknownError = "org.freedesktop.DBus.Error.ServiceUnknown"
 
 // Methods
 
 // Raise raises player priority.
 func (i *Player) Raise() error {
-	return i.obj.Call(BaseInterface+".Raise", 0).Err
+	return i.RaiseContext(context.Background())
+}
+
+// RaiseContext is like Raise but takes a context.
+func (i *Player) RaiseContext(ctx context.Context) error {
+	return i.callContext(ctx, BaseInterface+".Raise")
 }
 
 // Quit closes the player.
 func (i *Player) Quit() error {
-	return i.obj.Call(BaseInterface+".Quit", 0).Err
+	return i.QuitContext(context.Background())
+}
+
+// QuitContext is like Quit but takes a context.
+func (i *Player) QuitContext(ctx context.Context) error {
+	return i.callContext(ctx, BaseInterface+".Quit")
+}
+
+// RaiseOrActivate brings the player to the front. A running player is raised,
+// which fails with an error wrapping ErrNotAllowed when CanRaise is false.
+// A player that isn't running is launched through D-Bus activation of its
+// name and expected to show itself, failing with an error wrapping
+// ErrNotSupported when the name isn't activatable. With WithNoAutoStart, the
+// player is never launched and the error wraps ErrServiceUnknown instead.
+func (i *Player) RaiseOrActivate() error {
+	return i.RaiseOrActivateContext(context.Background())
+}
+
+// RaiseOrActivateContext is like RaiseOrActivate but takes a context.
+func (i *Player) RaiseOrActivateContext(ctx context.Context) error {
+	running, err := i.ExistsContext(ctx)
+	if err != nil {
+		return err
+	}
+	if running {
+		can, err := i.CanRaiseContext(ctx)
+		if err != nil {
+			return missingAsNotSupported(err)
+		}
+		if !can {
+			return fmt.Errorf(
+				"%w: %s.CanRaise is false",
+				ErrNotAllowed,
+				BaseInterface,
+			)
+		}
+		return i.RaiseContext(ctx)
+	}
+
+	if i.flags&dbus.FlagNoAutoStart != 0 {
+		return fmt.Errorf("%w: %s isn't running", ErrServiceUnknown, i.name)
+	}
+	ctx, cancel := i.callCtx(ctx)
+	defer cancel()
+	method := "org.freedesktop.DBus.StartServiceByName"
+	var reply uint32
+	err = busCall(ctx, i.tr, method, i.name, uint32(0)).Store(&reply)
+	if name, ok := dbusErrorName(err); ok && name == serviceUnknownError {
+		return fmt.Errorf("%w: %s isn't activatable: %w", ErrNotSupported, i.name, err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to activate %s: %w", i.name, translateError(err))
+	}
+	return nil
+}
+
+// ToggleFullscreen flips the fullscreen state of the player and returns the
+// new state. It fails with an error wrapping ErrNotAllowed, without changing
+// anything, when CanSetFullscreen is false.
+func (i *Player) ToggleFullscreen() (bool, error) {
+	return i.ToggleFullscreenContext(context.Background())
+}
+
+// ToggleFullscreenContext is like ToggleFullscreen but takes a context.
+func (i *Player) ToggleFullscreenContext(ctx context.Context) (bool, error) {
+	can, err := i.CanSetFullscreenContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	if !can {
+		return false, fmt.Errorf(
+			"%w: %s.CanSetFullscreen is false, can't toggle fullscreen",
+			ErrNotAllowed,
+			BaseInterface,
+		)
+	}
+	fullscreen, err := i.GetFullscreenContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	if err := i.SetFullscreenContext(ctx, !fullscreen); err != nil {
+		return fullscreen, err
+	}
+	return !fullscreen, nil
+}
+
+// Signals
+
+// WatchFullscreenChanged sends the new fullscreen state to ch whenever it
+// changes, until the returned Subscription is closed.
+func (i *Player) WatchFullscreenChanged(ch chan<- bool) (*Subscription, error) {
+	return i.watchProperties(
+		BaseInterface,
+		[]string{"Fullscreen"},
+		func(ctx context.Context, pc propertiesChanged) {
+			v, ok := pc.changed["Fullscreen"]
+			if !ok {
+				return
+			}
+			fullscreen, err := cast.ToBoolE(v.Value())
+			if err != nil {
+				return
+			}
+			select {
+			case ch <- fullscreen:
+			case <-ctx.Done():
+			}
+		},
+	)
+}
+
+// OnFullscreenChanged sends the new fullscreen state to ch whenever it changes,
+// until ctx is canceled. The state is part of the base org.mpris.MediaPlayer2
+// interface, so changes on the player interface are ignored.
+func (i *Player) OnFullscreenChanged(ctx context.Context, ch chan<- bool) error {
+	sub, err := i.WatchFullscreenChanged(ch)
+	if err != nil {
+		return err
+	}
+	return sub.wait(ctx)
 }
 
 // Properties
 
 // CanQuit returns whether the player can be quit.
 func (i *Player) CanQuit() (bool, error) {
-	return getBasePropertyCast(i, "CanQuit", cast.ToBoolE)
+	return i.CanQuitContext(context.Background())
+}
+
+// CanQuitContext is like CanQuit but takes a context.
+func (i *Player) CanQuitContext(ctx context.Context) (bool, error) {
+	return getBasePropertyCast(ctx, i, "CanQuit", cast.ToBoolE)
 }
 
 // GetFullscreen returns whether the player is in fullscreen mode.
 func (i *Player) GetFullscreen() (bool, error) {
-	return getBasePropertyCast(i, "Fullscreen", cast.ToBoolE)
+	return i.GetFullscreenContext(context.Background())
+}
+
+// GetFullscreenContext is like GetFullscreen but takes a context.
+func (i *Player) GetFullscreenContext(ctx context.Context) (bool, error) {
+	return getBasePropertyCast(ctx, i, "Fullscreen", cast.ToBoolE)
 }
 
 // SetFullscreen sets the fullscreen state of the player.
 func (i *Player) SetFullscreen(fullscreen bool) error {
-	return i.SetBaseProperty("Fullscreen", fullscreen)
+	return i.SetFullscreenContext(context.Background(), fullscreen)
+}
+
+// SetFullscreenContext is like SetFullscreen but takes a context.
+func (i *Player) SetFullscreenContext(
+	ctx context.Context,
+	fullscreen bool,
+) error {
+	return i.SetPropertyContext(ctx, BaseInterface, "Fullscreen", fullscreen)
 }
 
 // CanSetFullscreen returns whether the player allows changing fullscreen state.
 func (i *Player) CanSetFullscreen() (bool, error) {
-	return getBasePropertyCast(i, "CanSetFullscreen", cast.ToBoolE)
+	return i.CanSetFullscreenContext(context.Background())
+}
+
+// CanSetFullscreenContext is like CanSetFullscreen but takes a context.
+func (i *Player) CanSetFullscreenContext(ctx context.Context) (bool, error) {
+	return getBasePropertyCast(ctx, i, "CanSetFullscreen", cast.ToBoolE)
 }
 
 // CanRaise returns whether the player can be raised.
 func (i *Player) CanRaise() (bool, error) {
-	return getBasePropertyCast(i, "CanRaise", cast.ToBoolE)
+	return i.CanRaiseContext(context.Background())
+}
+
+// CanRaiseContext is like CanRaise but takes a context.
+func (i *Player) CanRaiseContext(ctx context.Context) (bool, error) {
+	return getBasePropertyCast(ctx, i, "CanRaise", cast.ToBoolE)
 }
 
 // HasTrackList returns whether the player has a track list.
 func (i *Player) HasTrackList() (bool, error) {
-	return getBasePropertyCast(i, "HasTrackList", cast.ToBoolE)
+	return i.HasTrackListContext(context.Background())
+}
+
+// HasTrackListContext is like HasTrackList but takes a context.
+func (i *Player) HasTrackListContext(ctx context.Context) (bool, error) {
+	return getBasePropertyCast(ctx, i, "HasTrackList", cast.ToBoolE)
 }
 
 // GetIdentity returns the player identity.
 func (i *Player) GetIdentity() (string, error) {
-	return getBasePropertyCast(i, "Identity", cast.ToStringE)
+	return i.GetIdentityContext(context.Background())
+}
+
+// GetIdentityContext is like GetIdentity but takes a context.
+func (i *Player) GetIdentityContext(ctx context.Context) (string, error) {
+	return getBasePropertyCast(ctx, i, "Identity", cast.ToStringE)
 }
 
 // GetDesktopEntry returns the desktop entry name of the player.
 func (i *Player) GetDesktopEntry() (string, error) {
-	return getBasePropertyCast(i, "DesktopEntry", cast.ToStringE)
+	return i.GetDesktopEntryContext(context.Background())
+}
+
+// GetDesktopEntryContext is like GetDesktopEntry but takes a context.
+func (i *Player) GetDesktopEntryContext(ctx context.Context) (string, error) {
+	return getBasePropertyCast(ctx, i, "DesktopEntry", cast.ToStringE)
 }
 
 //revive:disable:var-naming
 
 // GetSupportedUriSchemes returns the supported URI schemes of the player.
 func (i *Player) GetSupportedUriSchemes() ([]string, error) {
-	return getBasePropertyCast(i, "SupportedUriSchemes", cast.ToStringSliceE)
+	return i.GetSupportedUriSchemesContext(context.Background())
+}
+
+// GetSupportedUriSchemesContext is like GetSupportedUriSchemes but takes a
+// context.
+func (i *Player) GetSupportedUriSchemesContext(
+	ctx context.Context,
+) ([]string, error) {
+	return getBasePropertyCast(ctx, i, "SupportedUriSchemes", cast.ToStringSliceE)
 }
 
 //revive:enable:var-naming
 
 // SupportedMimeTypes returns the supported MIME types of the player.
 func (i *Player) SupportedMimeTypes() ([]string, error) {
-	return getBasePropertyCast(i, "SupportedMimeTypes", cast.ToStringSliceE)
+	return i.SupportedMimeTypesContext(context.Background())
+}
+
+// SupportedMimeTypesContext is like SupportedMimeTypes but takes a context.
+func (i *Player) SupportedMimeTypesContext(
+	ctx context.Context,
+) ([]string, error) {
+	return getBasePropertyCast(ctx, i, "SupportedMimeTypes", cast.ToStringSliceE)
 }
@@ -0,0 +1,141 @@
+package mpris
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestOnFullscreenChanged(t *testing.T) {
+	server, player := testBus(t)
+	props := exportTestProperties(t, server, map[string]map[string]any{
+		BaseInterface: {
+			"Fullscreen":       false,
+			"CanSetFullscreen": true,
+		},
+	})
+
+	ch := make(chan bool, 2)
+	sub, err := player.WatchFullscreenChanged(ch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	// A property with the same name on another interface is not fullscreen.
+	emitPropertiesChanged(t, server, PlayerInterface, map[string]dbus.Variant{
+		"Fullscreen": dbus.MakeVariant(true),
+	})
+	expectNothing(t, ch)
+
+	got, err := player.ToggleFullscreen()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got {
+		t.Error("ToggleFullscreen() = false, want true")
+	}
+	if !receive(t, ch) {
+		t.Error("fullscreen event = false, want true")
+	}
+
+	fullscreen := make(chan bool, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if _, err := player.Subscribe(ctx, FullscreenChanged(func(b bool) {
+		fullscreen <- b
+	})); err != nil {
+		t.Fatal(err)
+	}
+	props.set(BaseInterface, "Fullscreen", false)
+	if receive(t, fullscreen) {
+		t.Error("fullscreen callback = true, want false")
+	}
+}
+
+func TestToggleFullscreenNotAllowed(t *testing.T) {
+	server, player := testBus(t)
+	props := exportTestProperties(t, server, map[string]map[string]any{
+		BaseInterface: {
+			"Fullscreen":       false,
+			"CanSetFullscreen": false,
+		},
+	})
+
+	if _, err := player.ToggleFullscreen(); !errors.Is(err, ErrNotAllowed) {
+		t.Fatalf("ToggleFullscreen() error = %v, want ErrNotAllowed", err)
+	}
+	if v, _ := props.Get(BaseInterface, "Fullscreen"); v.Value() != false {
+		t.Error("Fullscreen changed although it isn't allowed")
+	}
+}
+
+// raisingPlayer implements Raise, counting the calls.
+type raisingPlayer struct {
+	raised atomic.Int32
+}
+
+func (p *raisingPlayer) Raise() *dbus.Error {
+	p.raised.Add(1)
+	return nil
+}
+
+func TestRaiseOrActivateRunning(t *testing.T) {
+	server, player := testBus(t)
+	props := exportTestProperties(t, server, map[string]map[string]any{
+		BaseInterface: {"CanRaise": true},
+	})
+	fake := &raisingPlayer{}
+	if err := server.Export(fake, DBusObjectPath, BaseInterface); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := player.RaiseOrActivate(); err != nil {
+		t.Fatal(err)
+	}
+	if fake.raised.Load() != 1 {
+		t.Error("RaiseOrActivate() didn't raise the running player")
+	}
+
+	props.set(BaseInterface, "CanRaise", false)
+	if err := player.RaiseOrActivate(); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("RaiseOrActivate() error = %v, want ErrNotAllowed", err)
+	}
+	if fake.raised.Load() != 1 {
+		t.Error("RaiseOrActivate() raised although CanRaise is false")
+	}
+}
+
+func TestRaiseOrActivateNotRunning(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "activated")
+	name := BaseInterface + ".activatable"
+	writeService(t, dir, name, marker)
+	conn := testConn(t, testBusAddressWithServices(t, dir))
+
+	err := New(conn, name, WithNoAutoStart()).RaiseOrActivate()
+	if !errors.Is(err, ErrServiceUnknown) {
+		t.Errorf("RaiseOrActivate() error = %v, want ErrServiceUnknown", err)
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Fatal("the player was activated despite WithNoAutoStart")
+	}
+
+	err = New(conn, BaseInterface+".missing").RaiseOrActivate()
+	if !errors.Is(err, ErrNotSupported) {
+		t.Errorf("RaiseOrActivate() error = %v, want ErrNotSupported", err)
+	}
+
+	// The test service exits without claiming the name, so activation fails
+	// after launching it.
+	New(conn, name, WithCallTimeout(time.Second)).RaiseOrActivate()
+	if _, err := os.Stat(marker); err != nil {
+		t.Error("RaiseOrActivate() didn't launch the service")
+	}
+}
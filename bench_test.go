@@ -0,0 +1,114 @@
+package mpris_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Nadim147c/go-mpris"
+	"github.com/Nadim147c/go-mpris/mpristest"
+	"github.com/godbus/dbus/v5"
+)
+
+// benchLatency is the delay the fake answers every property read with,
+// standing in for the round trip to a slow player.
+const benchLatency = 20 * time.Millisecond
+
+// slowPlayer returns a fake player answering the reads of the base and player
+// interfaces after latency.
+func slowPlayer(
+	tb testing.TB,
+	latency time.Duration,
+) (*mpristest.FakePlayer, *mpris.Player) {
+	tb.Helper()
+	fake, player := mpristest.NewFakePlayer(tb)
+	fake.Delay("Identity", latency)
+	fake.Delay("PlaybackStatus", latency)
+	return fake, player
+}
+
+// TestStatusConcurrent checks that Status reads both interfaces at once,
+// taking about one round trip instead of two.
+func TestStatusConcurrent(t *testing.T) {
+	const latency = 300 * time.Millisecond
+	_, player := slowPlayer(t, latency)
+	start := time.Now()
+	if _, err := player.Status(); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed >= 2*latency {
+		t.Errorf("Status took %v, want less than %v", elapsed, 2*latency)
+	}
+}
+
+func BenchmarkStatus(b *testing.B) {
+	_, player := slowPlayer(b, benchLatency)
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := player.Status(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkListPlayerInfo(b *testing.B) {
+	fake, _ := slowPlayer(b, benchLatency)
+	conn := fake.Connect()
+	for b.Loop() {
+		infos, err := mpris.ListPlayerInfo(conn)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(infos) != 1 || infos[0].Err != nil {
+			b.Fatalf("ListPlayerInfo() = %+v", infos)
+		}
+	}
+}
+
+// metadataPlayer returns a fake player with the metadata of a typical track.
+func metadataPlayer(tb testing.TB) *mpris.Player {
+	tb.Helper()
+	fake, player := mpristest.NewFakePlayer(tb)
+	fake.SetMetadata(mpris.Metadata{
+		"mpris:trackid":     dbus.MakeVariant(dbus.ObjectPath("/track/1")),
+		"mpris:length":      dbus.MakeVariant(int64(180_000_000)),
+		"mpris:artUrl":      dbus.MakeVariant("file:///tmp/cover.jpg"),
+		"xesam:title":       dbus.MakeVariant("Title"),
+		"xesam:album":       dbus.MakeVariant("Album"),
+		"xesam:artist":      dbus.MakeVariant([]string{"Artist"}),
+		"xesam:albumArtist": dbus.MakeVariant([]string{"Artist"}),
+		"xesam:trackNumber": dbus.MakeVariant(int32(3)),
+		"xesam:url":         dbus.MakeVariant("file:///tmp/track.flac"),
+	})
+	return player
+}
+
+func BenchmarkGetMetadata(b *testing.B) {
+	player := metadataPlayer(b)
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := player.GetMetadata(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGetTrackMetadata(b *testing.B) {
+	player := metadataPlayer(b)
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := player.GetTrackMetadata(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGetMetadataInto(b *testing.B) {
+	player := metadataPlayer(b)
+	m := mpris.Metadata{}
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := player.GetMetadataInto(m); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
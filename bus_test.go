@@ -0,0 +1,262 @@
+package mpris
+
+import (
+	"bufio"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// testPlayerName is the bus name claimed by the player side of testBus.
+const testPlayerName = BaseInterface + ".test"
+
+// realBusAddressEnv names the environment variable holding the address of the
+// bus that tests needing real players run against, e.g. one started in CI
+// with "dbus-daemon --session --print-address". The session bus is used when
+// it is unset.
+const realBusAddressEnv = "MPRIS_TEST_BUS_ADDRESS"
+
+// realBusConn returns a connection to the bus with real players. The test is
+// skipped when the session bus is unavailable and no address was given.
+func realBusConn(t *testing.T) *dbus.Conn {
+	t.Helper()
+	if addr := os.Getenv(realBusAddressEnv); addr != "" {
+		s, err := ConnectAddress(addr)
+		if err != nil {
+			t.Fatalf("Could not connect to %s: %v", realBusAddressEnv, err)
+		}
+		t.Cleanup(func() { s.Close() })
+		return s.Conn()
+	}
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		t.Skipf("Could not connect to session bus: %v", err)
+	}
+	return conn
+}
+
+// testBusAddress starts a private dbus-daemon for the duration of the test and
+// returns its address. The test is skipped when dbus-daemon is unavailable.
+func testBusAddress(t *testing.T) string {
+	t.Helper()
+	return startTestBus(t, "--session")
+}
+
+// testBusConfig is the configuration of a private session bus that activates
+// services from the directory %s.
+const testBusConfig = `<!DOCTYPE busconfig PUBLIC
+ "-//freedesktop//DTD D-Bus Bus Configuration 1.0//EN"
+ "http://www.freedesktop.org/standards/dbus/1.0/busconfig.dtd">
+<busconfig>
+  <type>session</type>
+  <listen>unix:tmpdir=%s</listen>
+  <servicedir>%s</servicedir>
+  <policy context="default">
+    <allow send_destination="*" eavesdrop="true"/>
+    <allow eavesdrop="true"/>
+    <allow own="*"/>
+  </policy>
+</busconfig>
+`
+
+// testBusAddressWithServices is like testBusAddress, but the bus activates
+// the services described by the .service files in dir.
+func testBusAddressWithServices(t *testing.T, dir string) string {
+	t.Helper()
+	config := filepath.Join(t.TempDir(), "bus.conf")
+	data := fmt.Sprintf(testBusConfig, os.TempDir(), dir)
+	if err := os.WriteFile(config, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return startTestBus(t, "--config-file="+config)
+}
+
+// startTestBus starts dbus-daemon with args and returns its address.
+func startTestBus(t *testing.T, args ...string) string {
+	t.Helper()
+	bin, err := exec.LookPath("dbus-daemon")
+	if err != nil {
+		t.Skip("dbus-daemon not found")
+	}
+
+	args = append(args, "--nofork", "--print-address=1")
+	cmd := exec.Command(bin, args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Skipf("Could not start dbus-daemon: %v", err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+
+	addr, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		t.Fatalf("Could not read dbus-daemon address: %v", err)
+	}
+	return strings.TrimSpace(addr)
+}
+
+// testConn opens a connection to the bus at addr that is closed when the test
+// ends.
+func testConn(t *testing.T, addr string) *dbus.Conn {
+	t.Helper()
+	conn, err := dbus.Connect(addr)
+	if err != nil {
+		t.Fatalf("Could not connect to test bus: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// testBus starts a private bus and returns a connection owning testPlayerName,
+// which plays the role of the media player, and a Player connected to it from
+// a separate client connection.
+func testBus(t *testing.T) (*dbus.Conn, *Player) {
+	t.Helper()
+	addr := testBusAddress(t)
+
+	server := testConn(t, addr)
+	reply, err := server.RequestName(testPlayerName, dbus.NameFlagDoNotQueue)
+	if err != nil || reply != dbus.RequestNameReplyPrimaryOwner {
+		t.Fatalf("Could not claim %s: %v", testPlayerName, err)
+	}
+
+	client := testConn(t, addr)
+	return server, New(client, testPlayerName)
+}
+
+// emitPropertiesChanged emits a PropertiesChanged signal for iface from the
+// player connection.
+func emitPropertiesChanged(
+	t *testing.T,
+	server *dbus.Conn,
+	iface string,
+	changed map[string]dbus.Variant,
+	invalidated ...string,
+) {
+	t.Helper()
+	if invalidated == nil {
+		invalidated = []string{}
+	}
+	err := server.Emit(
+		DBusObjectPath,
+		PropertiesChangedSignal,
+		iface,
+		changed,
+		invalidated,
+	)
+	if err != nil {
+		t.Fatalf("Could not emit PropertiesChanged: %v", err)
+	}
+}
+
+// emitMode controls how testProperties announces a change.
+type emitMode int
+
+const (
+	emitValue emitMode = iota
+	emitInvalidates
+	emitNothing
+)
+
+// testProperties is a minimal org.freedesktop.DBus.Properties implementation
+// for the player side of testBus. Unlike godbus' prop package it replaces
+// values instead of storing into them, which matters for maps like Metadata.
+type testProperties struct {
+	conn *dbus.Conn
+
+	mu    sync.Mutex
+	props map[string]map[string]dbus.Variant
+	emit  map[string]emitMode
+}
+
+// exportTestProperties exports props on the player connection, so the Player
+// can read and write them through org.freedesktop.DBus.Properties.
+func exportTestProperties(
+	t *testing.T,
+	server *dbus.Conn,
+	props map[string]map[string]any,
+) *testProperties {
+	t.Helper()
+	p := &testProperties{
+		conn:  server,
+		props: map[string]map[string]dbus.Variant{},
+		emit:  map[string]emitMode{},
+	}
+	for iface, values := range props {
+		p.props[iface] = map[string]dbus.Variant{}
+		for name, v := range values {
+			p.props[iface][name] = dbus.MakeVariant(v)
+		}
+	}
+	err := server.Export(p, DBusObjectPath, "org.freedesktop.DBus.Properties")
+	if err != nil {
+		t.Fatalf("Could not export properties: %v", err)
+	}
+	return p
+}
+
+// Get implements org.freedesktop.DBus.Properties.Get.
+func (p *testProperties) Get(iface, name string) (dbus.Variant, *dbus.Error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	v, ok := p.props[iface][name]
+	if !ok {
+		return dbus.Variant{}, dbus.NewError(
+			"org.freedesktop.DBus.Error.UnknownProperty",
+			[]any{"Unknown property " + name},
+		)
+	}
+	return v, nil
+}
+
+// GetAll implements org.freedesktop.DBus.Properties.GetAll.
+func (p *testProperties) GetAll(iface string) (map[string]dbus.Variant, *dbus.Error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return maps.Clone(p.props[iface]), nil
+}
+
+// Set implements org.freedesktop.DBus.Properties.Set.
+func (p *testProperties) Set(iface, name string, v dbus.Variant) *dbus.Error {
+	p.set(iface, name, v.Value())
+	return nil
+}
+
+// setEmit changes how changes of name are announced.
+func (p *testProperties) setEmit(name string, mode emitMode) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.emit[name] = mode
+}
+
+// set replaces the value of iface.name and announces the change.
+func (p *testProperties) set(iface, name string, v any) {
+	p.mu.Lock()
+	if p.props[iface] == nil {
+		p.props[iface] = map[string]dbus.Variant{}
+	}
+	p.props[iface][name] = dbus.MakeVariant(v)
+	mode := p.emit[name]
+	p.mu.Unlock()
+
+	switch mode {
+	case emitValue:
+		p.conn.Emit(DBusObjectPath, PropertiesChangedSignal, iface,
+			map[string]dbus.Variant{name: dbus.MakeVariant(v)}, []string{})
+	case emitInvalidates:
+		p.conn.Emit(DBusObjectPath, PropertiesChangedSignal, iface,
+			map[string]dbus.Variant{}, []string{name})
+	}
+}
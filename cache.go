@@ -0,0 +1,240 @@
+package mpris
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// WithPropertyCache makes the player cache the properties it reads, so
+// polling them doesn't go to the bus every time. The player subscribes to
+// PropertiesChanged once and drops the cached value of every property a
+// signal names as changed or invalidated, and the whole cache when the
+// owner of the name changes. The cache follows the name across restarts of
+// the application, with or without WithOwnerTracking. Position, which players never announce, is
+// always read live. The player must be closed with Close to stop listening.
+func WithPropertyCache() Option {
+	return func(p *Player) {
+		p.cacheProperties = true
+	}
+}
+
+// propertyCache holds the property values read from the player by interface
+// and name.
+type propertyCache struct {
+	sub *Subscription
+
+	mu     sync.Mutex
+	values map[string]map[string]dbus.Variant
+	// owner is the unique name whose PropertiesChanged signals invalidate
+	// the cache, followed across restarts. ownerSeen is set once a
+	// NameOwnerChanged signal was received, after which the owner read on
+	// startup is outdated.
+	owner     string
+	ownerSeen bool
+	// generation increases with every invalidation, so a value read from
+	// the bus while the property changed isn't stored.
+	generation uint64
+}
+
+// startPropertyCache starts listening for the changes that invalidate the
+// property cache. PropertiesChanged is matched by the well-known name, which
+// the bus resolves to the current owner, so the owner the cache accepts
+// signals from is followed like ownerTracker does.
+func (i *Player) startPropertyCache() error {
+	c := &propertyCache{values: map[string]map[string]dbus.Variant{}}
+	changedRule := []dbus.MatchOption{
+		dbus.WithMatchSender(i.name),
+		dbus.WithMatchObjectPath(i.path),
+		dbus.WithMatchInterface("org.freedesktop.DBus.Properties"),
+		dbus.WithMatchMember("PropertiesChanged"),
+	}
+	ownerRule := []dbus.MatchOption{
+		dbus.WithMatchSender(busName),
+		dbus.WithMatchInterface(busName),
+		dbus.WithMatchMember("NameOwnerChanged"),
+		dbus.WithMatchArg(0, i.name),
+	}
+	// The cache is updated by the filter, which runs before the signal is
+	// delivered to any subscription, so their handlers never read values
+	// the signal made stale.
+	filter := func(sig *dbus.Signal) bool {
+		switch {
+		case sig.Name == nameOwnerChangedSignal && sig.Sender == busName:
+			var name, oldOwner, newOwner string
+			err := dbus.Store(sig.Body, &name, &oldOwner, &newOwner)
+			if err == nil && name == i.name {
+				c.setOwner(newOwner)
+				i.forgetOwnerState()
+			}
+		case sig.Name == PropertiesChangedSignal && sig.Path == i.path &&
+			c.from(sig.Sender):
+			c.invalidateSignal(sig)
+		}
+		return false
+	}
+	sub, err := subscribe(
+		i.tr,
+		[][]dbus.MatchOption{changedRule, ownerRule},
+		filter,
+		func(context.Context, *dbus.Signal) {},
+	)
+	if err != nil {
+		return err
+	}
+	c.sub = sub
+
+	// Read the owner after subscribing, so a change in between is not lost.
+	owner, err := i.getOwner()
+	if err != nil && !errors.Is(err, ErrServiceUnknown) {
+		sub.Close()
+		return err
+	}
+	c.mu.Lock()
+	if !c.ownerSeen {
+		c.owner = owner
+	}
+	c.mu.Unlock()
+	i.mu.Lock()
+	i.cache = c
+	i.mu.Unlock()
+	return nil
+}
+
+// setOwner records the new owner of the name and drops every cached value,
+// which the previous owner reported.
+func (c *propertyCache) setOwner(owner string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.owner = owner
+	c.ownerSeen = true
+	c.values = map[string]map[string]dbus.Variant{}
+	c.generation++
+}
+
+// from reports whether sender is the owner the cache follows.
+func (c *propertyCache) from(sender string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return sender != "" && sender == c.owner
+}
+
+// propertyCache returns the property cache of the player, or nil when it
+// has none.
+func (i *Player) propertyCache() *propertyCache {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.cache
+}
+
+// Invalidate drops the property values cached by WithPropertyCache, so the
+// next reads go to the bus. It does nothing for players without a cache.
+func (i *Player) Invalidate() {
+	if c := i.propertyCache(); c != nil {
+		c.invalidate()
+	}
+}
+
+// cacheable reports whether iface.name may be cached.
+func cacheable(iface, name string) bool {
+	return iface != PlayerInterface || name != "Position"
+}
+
+// get returns a copy of the cached value of iface.name, so callers may
+// modify it, and the generation to store a value read from the bus with when
+// there is none.
+func (c *propertyCache) get(iface, name string) (dbus.Variant, bool, uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.values[iface][name]
+	if ok {
+		v = cloneVariant(v)
+	}
+	return v, ok, c.generation
+}
+
+// currentGeneration returns the generation to store values read from the
+// bus with.
+func (c *propertyCache) currentGeneration() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation
+}
+
+// store caches copies of values of iface read at generation, unless the
+// cache was invalidated since.
+func (c *propertyCache) store(
+	generation uint64,
+	iface string,
+	values map[string]dbus.Variant,
+) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if generation != c.generation {
+		return
+	}
+	if c.values[iface] == nil {
+		c.values[iface] = map[string]dbus.Variant{}
+	}
+	for name, v := range values {
+		if cacheable(iface, name) {
+			c.values[iface][name] = cloneVariant(v)
+		}
+	}
+}
+
+// invalidate drops every cached value.
+func (c *propertyCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values = map[string]map[string]dbus.Variant{}
+	c.generation++
+}
+
+// invalidateProperty drops the cached value of iface.name.
+func (c *propertyCache) invalidateProperty(iface, name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.values[iface], name)
+	c.generation++
+}
+
+// invalidateSignal drops the cached values of the properties named by a
+// PropertiesChanged signal. The whole cache is dropped when the signal can't
+// be decoded.
+func (c *propertyCache) invalidateSignal(sig *dbus.Signal) {
+	var (
+		iface       string
+		changed     map[string]dbus.Variant
+		invalidated []string
+	)
+	if err := dbus.Store(sig.Body, &iface, &changed, &invalidated); err != nil {
+		c.invalidate()
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for name := range changed {
+		delete(c.values[iface], name)
+	}
+	for _, name := range invalidated {
+		delete(c.values[iface], name)
+	}
+	c.generation++
+}
+
+// closeCache stops the property cache of the player, if it has one. The
+// player reads every property from the bus afterwards, since nothing would
+// invalidate the cached values anymore.
+func (i *Player) closeCache() error {
+	i.mu.Lock()
+	c := i.cache
+	i.cache = nil
+	i.mu.Unlock()
+	if c == nil {
+		return nil
+	}
+	return c.sub.Close()
+}
@@ -0,0 +1,194 @@
+package mpris
+
+import (
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// cachedPlayer returns a player with a property cache for the properties
+// exported by the returned testProperties.
+func cachedPlayer(t *testing.T) (*Player, *testProperties) {
+	t.Helper()
+	addr := testBusAddress(t)
+	server := claimName(t, addr, testPlayerName)
+	props := exportTestProperties(t, server, map[string]map[string]any{
+		BaseInterface: {
+			"Identity":   "first",
+			"Fullscreen": false,
+		},
+		PlayerInterface: {
+			"Position": int64(1),
+		},
+	})
+	player, err := NewChecked(
+		testConn(t, addr),
+		testPlayerName,
+		WithPropertyCache(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { player.Close() })
+	return player, props
+}
+
+// expectProperty fails t unless iface.name of player reads as want.
+func expectProperty(t *testing.T, player *Player, iface, name string, want any) {
+	t.Helper()
+	v, err := player.GetProperty(iface, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Value() != want {
+		t.Errorf("%s = %v, want %v", name, v.Value(), want)
+	}
+}
+
+func TestPropertyCache(t *testing.T) {
+	player, props := cachedPlayer(t)
+
+	expectProperty(t, player, BaseInterface, "Identity", "first")
+	props.setEmit("Identity", emitNothing)
+	props.set(BaseInterface, "Identity", "second")
+	expectProperty(t, player, BaseInterface, "Identity", "first")
+	player.Invalidate()
+	expectProperty(t, player, BaseInterface, "Identity", "second")
+
+	props.setEmit("Position", emitNothing)
+	expectProperty(t, player, PlayerInterface, "Position", int64(1))
+	props.set(PlayerInterface, "Position", int64(2))
+	expectProperty(t, player, PlayerInterface, "Position", int64(2))
+}
+
+func TestPropertyCacheSignals(t *testing.T) {
+	player, props := cachedPlayer(t)
+	fullscreen := make(chan bool, 1)
+	sub, err := player.WatchFullscreenChanged(fullscreen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	expectProperty(t, player, BaseInterface, "Fullscreen", false)
+	props.set(BaseInterface, "Fullscreen", true)
+	receive(t, fullscreen)
+	expectProperty(t, player, BaseInterface, "Fullscreen", true)
+
+	// The cache is invalidated before signals reach subscriptions, so once
+	// the Fullscreen change arrives the earlier Identity one was handled.
+	expectProperty(t, player, BaseInterface, "Identity", "first")
+	props.setEmit("Identity", emitInvalidates)
+	props.set(BaseInterface, "Identity", "second")
+	props.set(BaseInterface, "Fullscreen", false)
+	receive(t, fullscreen)
+	expectProperty(t, player, BaseInterface, "Identity", "second")
+}
+
+func TestPropertyCacheWrites(t *testing.T) {
+	player, props := cachedPlayer(t)
+	props.setEmit("Identity", emitNothing)
+
+	expectProperty(t, player, BaseInterface, "Identity", "first")
+	if err := player.SetBaseProperty("Identity", "second"); err != nil {
+		t.Fatal(err)
+	}
+	expectProperty(t, player, BaseInterface, "Identity", "second")
+
+	player.Invalidate()
+	if _, err := player.GetAllProperties(BaseInterface); err != nil {
+		t.Fatal(err)
+	}
+	props.set(BaseInterface, "Identity", "third")
+	expectProperty(t, player, BaseInterface, "Identity", "second")
+}
+
+func TestPropertyCacheClose(t *testing.T) {
+	player, props := cachedPlayer(t)
+	sub := player.propertyCache().sub
+	props.setEmit("Identity", emitNothing)
+	expectProperty(t, player, BaseInterface, "Identity", "first")
+
+	if err := player.Close(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-sub.Done():
+	default:
+		t.Error("cache subscription still running after Close")
+	}
+	props.set(BaseInterface, "Identity", "second")
+	expectProperty(t, player, BaseInterface, "Identity", "second")
+}
+
+func TestWithoutPropertyCache(t *testing.T) {
+	server, player := testBus(t)
+	props := exportTestProperties(t, server, map[string]map[string]any{
+		BaseInterface: {"Identity": "first"},
+	})
+	props.setEmit("Identity", emitNothing)
+	expectProperty(t, player, BaseInterface, "Identity", "first")
+	props.set(BaseInterface, "Identity", "second")
+	expectProperty(t, player, BaseInterface, "Identity", "second")
+	player.Invalidate()
+	if err := player.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPropertyCacheCopies(t *testing.T) {
+	player, props := cachedPlayer(t)
+	props.setEmit("Metadata", emitNothing)
+	props.set(PlayerInterface, "Metadata", map[string]dbus.Variant{
+		"xesam:title": dbus.MakeVariant("title"),
+	})
+
+	for range 2 {
+		m, err := player.GetMetadata()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if title, _ := m.GetString("xesam:title"); title != "title" {
+			t.Fatalf("title = %q, want the cached one", title)
+		}
+		m["xesam:title"] = dbus.MakeVariant("changed by the caller")
+	}
+}
+
+func TestPropertyCacheRestart(t *testing.T) {
+	addr := testBusAddress(t)
+	server := claimName(t, addr, testPlayerName)
+	exportTestProperties(t, server, map[string]map[string]any{
+		BaseInterface: {"Identity": "first"},
+	})
+	player, err := NewChecked(
+		testConn(t, addr),
+		testPlayerName,
+		WithPropertyCache(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer player.Close()
+	expectProperty(t, player, BaseInterface, "Identity", "first")
+
+	// The application restarts and takes the name back.
+	if _, err := server.ReleaseName(testPlayerName); err != nil {
+		t.Fatal(err)
+	}
+	restarted := claimName(t, addr, testPlayerName)
+	props := exportTestProperties(t, restarted, map[string]map[string]any{
+		BaseInterface: {"Identity": "second"},
+	})
+	identity := func(want string) func() bool {
+		return func() bool {
+			v, err := player.GetProperty(BaseInterface, "Identity")
+			return err == nil && v.Value() == want
+		}
+	}
+	waitFor(t, identity("second"))
+
+	// The changes of the new owner invalidate the cache too.
+	props.set(BaseInterface, "Identity", "third")
+	waitFor(t, identity("third"))
+}
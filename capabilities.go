@@ -0,0 +1,132 @@
+package mpris
+
+import (
+	"context"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/spf13/cast"
+)
+
+// Capabilities holds the Can* properties of the player interface, which tell
+// which controls currently have an effect.
+type Capabilities struct {
+	CanGoNext     bool
+	CanGoPrevious bool
+	CanPlay       bool
+	CanPause      bool
+	CanSeek       bool
+	CanControl    bool
+}
+
+// capabilityFields maps the property names to the fields of c.
+func (c *Capabilities) capabilityFields() map[string]*bool {
+	return map[string]*bool{
+		"CanGoNext":     &c.CanGoNext,
+		"CanGoPrevious": &c.CanGoPrevious,
+		"CanPlay":       &c.CanPlay,
+		"CanPause":      &c.CanPause,
+		"CanSeek":       &c.CanSeek,
+		"CanControl":    &c.CanControl,
+	}
+}
+
+// capabilityProperties lists the property names backing Capabilities.
+var capabilityProperties = []string{
+	"CanGoNext",
+	"CanGoPrevious",
+	"CanPlay",
+	"CanPause",
+	"CanSeek",
+	"CanControl",
+}
+
+// update sets the fields found in props and reports whether any of them
+// changed. Properties that are missing or fail to cast are left untouched.
+func (c *Capabilities) update(props map[string]dbus.Variant) bool {
+	changed := false
+	for name, field := range c.capabilityFields() {
+		v, ok := props[name]
+		if !ok {
+			continue
+		}
+		b, err := cast.ToBoolE(v.Value())
+		if err != nil {
+			continue
+		}
+		if *field != b {
+			*field = b
+			changed = true
+		}
+	}
+	return changed
+}
+
+// GetCapabilities returns the Can* properties of the player with a single
+// GetAll call. Properties the player doesn't report are false. When GetAll
+// fails, the properties are read one by one and the ones failing are false
+// too; it only fails when none can be read. Status tells which are missing.
+func (i *Player) GetCapabilities() (Capabilities, error) {
+	return i.GetCapabilitiesContext(context.Background())
+}
+
+// GetCapabilitiesContext is like GetCapabilities but takes a context.
+func (i *Player) GetCapabilitiesContext(
+	ctx context.Context,
+) (Capabilities, error) {
+	props, err := i.GetAllPropertiesContext(ctx, PlayerInterface)
+	if err != nil {
+		if !readableOneByOne(ctx, err) {
+			return Capabilities{}, err
+		}
+		props, _ = i.readEach(ctx, PlayerInterface, capabilityProperties)
+		if len(props) == 0 {
+			return Capabilities{}, err
+		}
+	}
+	var c Capabilities
+	c.update(props)
+	return c, nil
+}
+
+// capabilitiesHandler sends the capabilities to ch whenever one of them
+// changes, filling the unchanged ones from last.
+func capabilitiesHandler(
+	last Capabilities,
+	ch chan<- Capabilities,
+) func(context.Context, propertiesChanged) {
+	return func(ctx context.Context, pc propertiesChanged) {
+		if !last.update(pc.changed) {
+			return
+		}
+		select {
+		case ch <- last:
+		case <-ctx.Done():
+		}
+	}
+}
+
+// WatchCapabilitiesChanged sends the player's capabilities to ch whenever any
+// of them changes, until the returned Subscription is closed. Every value sent
+// is complete: fields that didn't change are filled from a snapshot taken when
+// the subscription started and kept up to date since.
+func (i *Player) WatchCapabilitiesChanged(ch chan<- Capabilities) (*Subscription, error) {
+	last, err := i.GetCapabilities()
+	if err != nil {
+		return nil, err
+	}
+	return i.watchProperties(
+		PlayerInterface,
+		capabilityProperties,
+		capabilitiesHandler(last, ch),
+	)
+}
+
+// OnCapabilitiesChanged sends the player's capabilities to ch whenever any of
+// them changes, until ctx is canceled. See WatchCapabilitiesChanged.
+func (i *Player) OnCapabilitiesChanged(ctx context.Context, ch chan<- Capabilities) error {
+	sub, err := i.WatchCapabilitiesChanged(ch)
+	if err != nil {
+		return err
+	}
+	return sub.wait(ctx)
+}
@@ -0,0 +1,58 @@
+package mpris
+
+import "testing"
+
+func TestCapabilities(t *testing.T) {
+	server, player := testBus(t)
+	props := exportTestProperties(t, server, map[string]map[string]any{
+		PlayerInterface: {
+			"CanGoNext":     true,
+			"CanGoPrevious": true,
+			"CanPlay":       true,
+			"CanPause":      true,
+			"CanSeek":       false,
+			"CanControl":    true,
+			"Volume":        1.0,
+		},
+	})
+
+	want := Capabilities{
+		CanGoNext:     true,
+		CanGoPrevious: true,
+		CanPlay:       true,
+		CanPause:      true,
+		CanControl:    true,
+	}
+	got, err := player.GetCapabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("GetCapabilities() = %+v, want %+v", got, want)
+	}
+
+	ch := make(chan Capabilities, 2)
+	sub, err := player.WatchCapabilitiesChanged(ch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	// Unrelated properties and unchanged values produce no event.
+	props.set(PlayerInterface, "Volume", 0.5)
+	props.set(PlayerInterface, "CanPlay", true)
+	expectNothing(t, ch)
+
+	props.set(PlayerInterface, "CanGoNext", false)
+	want.CanGoNext = false
+	if got := receive(t, ch); got != want {
+		t.Errorf("capabilities = %+v, want %+v", got, want)
+	}
+
+	props.setEmit("CanSeek", emitInvalidates)
+	props.set(PlayerInterface, "CanSeek", true)
+	want.CanSeek = true
+	if got := receive(t, ch); got != want {
+		t.Errorf("capabilities = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,56 @@
+// Command mpris-verify checks a running MPRIS player for compliance with the
+// spec and prints the result of every check. It verifies the player named by
+// its argument, e.g. "vlc" or "org.mpris.MediaPlayer2.vlc", or the first
+// player found. It exits with status 1 when a check fails.
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/Nadim147c/go-mpris"
+	"github.com/godbus/dbus/v5"
+)
+
+func main() {
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var name string
+	if len(os.Args) > 1 {
+		name = os.Args[1]
+		if !strings.HasPrefix(name, mpris.BaseInterface+".") {
+			name = mpris.BaseInterface + "." + name
+		}
+	} else {
+		names, err := mpris.List(conn)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(names) == 0 {
+			log.Fatal("No player found")
+		}
+		name = names[0]
+	}
+
+	report, err := mpris.Verify(mpris.New(conn, name))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(report.Player)
+	for _, c := range report.Checks {
+		line := fmt.Sprintf("%-4s  %s", c.Result, c.Name)
+		if c.Detail != "" {
+			line += ": " + c.Detail
+		}
+		fmt.Println(line)
+	}
+	if !report.OK() {
+		os.Exit(1)
+	}
+}
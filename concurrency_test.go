@@ -0,0 +1,95 @@
+package mpris
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// countingPlayer implements the playback methods of a player, counting the
+// calls it receives.
+type countingPlayer struct {
+	mu    sync.Mutex
+	calls int
+}
+
+func (p *countingPlayer) count() *dbus.Error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls++
+	return nil
+}
+
+func (p *countingPlayer) Play() *dbus.Error      { return p.count() }
+func (p *countingPlayer) Pause() *dbus.Error     { return p.count() }
+func (p *countingPlayer) PlayPause() *dbus.Error { return p.count() }
+
+// TestPlayerConcurrentUse shares one Player between goroutines calling
+// methods, reading and writing properties and subscribing at the same time.
+// Run with -race to check the concurrency guarantee of Player.
+func TestPlayerConcurrentUse(t *testing.T) {
+	server, client := testBus(t)
+	props := exportTestProperties(t, server, map[string]map[string]any{
+		BaseInterface: {"Identity": "Test"},
+		PlayerInterface: {
+			"PlaybackStatus": "Playing",
+			"Position":       int64(1_000_000),
+			"Volume":         0.5,
+			"Metadata":       metadataWithTitle("title"),
+		},
+	})
+	fake := &countingPlayer{}
+	if err := server.Export(fake, DBusObjectPath, PlayerInterface); err != nil {
+		t.Fatal(err)
+	}
+	player, err := NewChecked(client.conn, testPlayerName, WithOwnerTracking())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer player.Close()
+
+	const goroutines, rounds = 8, 20
+	ops := []func() error{
+		player.PlayPause,
+		player.Play,
+		func() error { _, err := player.GetPosition(); return err },
+		func() error { _, err := player.GetMetadata(); return err },
+		func() error { _, err := player.GetIdentity(); return err },
+		func() error { return player.SetVolume(0.25) },
+		func() error { _, err := player.Status(); return err },
+		func() error { _ = player.Owner(); _ = player.OwnerChanged(); return nil },
+		func() error {
+			sub, err := player.WatchMetadataChanged(make(chan Metadata, 1))
+			if err != nil {
+				return err
+			}
+			props.set(PlayerInterface, "Metadata", metadataWithTitle("next"))
+			return sub.Close()
+		},
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, goroutines*rounds)
+	for g := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := range rounds {
+				if err := ops[(g+r)%len(ops)](); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if fake.calls == 0 {
+		t.Error("no playback method reached the player")
+	}
+}
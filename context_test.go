@@ -0,0 +1,96 @@
+package mpris
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// hungPlayer implements a player whose every call blocks until release is
+// closed, like mpv stuck on a dead network mount.
+type hungPlayer struct {
+	release chan struct{}
+}
+
+func (p *hungPlayer) Play() *dbus.Error {
+	<-p.release
+	return nil
+}
+
+func (p *hungPlayer) Get(string, string) (dbus.Variant, *dbus.Error) {
+	<-p.release
+	return dbus.MakeVariant(""), nil
+}
+
+func (p *hungPlayer) Set(string, string, dbus.Variant) *dbus.Error {
+	<-p.release
+	return nil
+}
+
+// exportHungPlayer exports a hungPlayer on the player connection.
+func exportHungPlayer(t *testing.T, server *dbus.Conn) {
+	t.Helper()
+	p := &hungPlayer{release: make(chan struct{})}
+	t.Cleanup(func() { close(p.release) })
+	for _, iface := range []string{PlayerInterface, "org.freedesktop.DBus.Properties"} {
+		if err := server.Export(p, DBusObjectPath, iface); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestContextVariantsTimeOut(t *testing.T) {
+	server, player := testBus(t)
+	exportHungPlayer(t, server)
+
+	calls := map[string]func(context.Context) error{
+		"PlayContext": player.PlayContext,
+		"GetMetadataContext": func(ctx context.Context) error {
+			_, err := player.GetMetadataContext(ctx)
+			return err
+		},
+		"GetPositionContext": func(ctx context.Context) error {
+			_, err := player.GetPositionContext(ctx)
+			return err
+		},
+		"SetVolumeContext": func(ctx context.Context) error {
+			return player.SetVolumeContext(ctx, 0.5)
+		},
+	}
+	for name, call := range calls {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		start := time.Now()
+		err := call(ctx)
+		cancel()
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("%s() error = %v, want context.DeadlineExceeded", name, err)
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("%s() returned after %v", name, elapsed)
+		}
+	}
+}
+
+func TestContextVariantsSucceed(t *testing.T) {
+	server, player := testBus(t)
+	exportTestProperties(t, server, map[string]map[string]any{
+		PlayerInterface: {
+			"Position": int64(2_000_000),
+			"Volume":   0.25,
+		},
+	})
+
+	ctx := context.Background()
+	if got, err := player.GetPositionContext(ctx); err != nil || got != 2*time.Second {
+		t.Errorf("GetPositionContext() = %v, %v", got, err)
+	}
+	if err := player.SetVolumeContext(ctx, 0.75); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := player.GetVolume(); err != nil || got != 0.75 {
+		t.Errorf("GetVolume() after SetVolumeContext = %v, %v", got, err)
+	}
+}
@@ -0,0 +1,86 @@
+package mpris
+
+import (
+	"context"
+	"errors"
+)
+
+// orDefault returns v, or def when err is not nil, and records err as the
+// LastError of i unless it only says the player doesn't support what v was
+// read from.
+func orDefault[T any](i *Player, v T, err error, def T) T {
+	if err != nil {
+		v = def
+	}
+	if errors.Is(err, ErrNotSupported) || errors.Is(err, ErrUnknownProperty) {
+		err = nil
+	}
+	i.mu.Lock()
+	i.lastErr = err
+	i.mu.Unlock()
+	return v
+}
+
+// LastError returns the error of the latest GetVolumeOr, GetLoopStatusOr,
+// GetShuffleOr or GetRateOr call, or nil when it read the value or the
+// player doesn't support it. It tells a broken player, such as one failing
+// with ErrPlayerGone, from one lacking the feature. Calls made concurrently
+// from several goroutines each replace it.
+func (i *Player) LastError() error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.lastErr
+}
+
+// GetVolumeOr is like GetVolume, but returns def instead of an error when
+// the volume can't be read, so widgets can show a sensible value without
+// error handling. Failures other than the player not supporting Volume are
+// reported by LastError.
+func (i *Player) GetVolumeOr(def float64) float64 {
+	return i.GetVolumeOrContext(context.Background(), def)
+}
+
+// GetVolumeOrContext is like GetVolumeOr but takes a context.
+func (i *Player) GetVolumeOrContext(ctx context.Context, def float64) float64 {
+	v, err := i.GetVolumeContext(ctx)
+	return orDefault(i, v, err, def)
+}
+
+// GetLoopStatusOr is like GetLoopStatus, but returns def when the loop status
+// can't be read; see GetVolumeOr.
+func (i *Player) GetLoopStatusOr(def LoopStatus) LoopStatus {
+	return i.GetLoopStatusOrContext(context.Background(), def)
+}
+
+// GetLoopStatusOrContext is like GetLoopStatusOr but takes a context.
+func (i *Player) GetLoopStatusOrContext(
+	ctx context.Context,
+	def LoopStatus,
+) LoopStatus {
+	v, err := i.GetLoopStatusContext(ctx)
+	return orDefault(i, v, err, def)
+}
+
+// GetShuffleOr is like GetShuffle, but returns def when the shuffle mode
+// can't be read; see GetVolumeOr.
+func (i *Player) GetShuffleOr(def bool) bool {
+	return i.GetShuffleOrContext(context.Background(), def)
+}
+
+// GetShuffleOrContext is like GetShuffleOr but takes a context.
+func (i *Player) GetShuffleOrContext(ctx context.Context, def bool) bool {
+	v, err := i.GetShuffleContext(ctx)
+	return orDefault(i, v, err, def)
+}
+
+// GetRateOr is like GetRate, but returns def when the playback rate can't be
+// read; see GetVolumeOr.
+func (i *Player) GetRateOr(def float64) float64 {
+	return i.GetRateOrContext(context.Background(), def)
+}
+
+// GetRateOrContext is like GetRateOr but takes a context.
+func (i *Player) GetRateOrContext(ctx context.Context, def float64) float64 {
+	v, err := i.GetRateContext(ctx)
+	return orDefault(i, v, err, def)
+}
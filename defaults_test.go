@@ -0,0 +1,38 @@
+package mpris_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Nadim147c/go-mpris"
+	"github.com/Nadim147c/go-mpris/mpristest"
+)
+
+func TestGetOr(t *testing.T) {
+	fake, player := mpristest.NewFakePlayer(t)
+	fake.SetPlayer("Volume", 0.5)
+	fake.DropProperty("Shuffle")
+	fake.DropProperty("LoopStatus")
+	fake.DropProperty("Rate")
+
+	if v := player.GetVolumeOr(1); v != 0.5 || player.LastError() != nil {
+		t.Errorf("GetVolumeOr(1) = %v, %v, want the reported 0.5", v, player.LastError())
+	}
+	if v := player.GetShuffleOr(true); !v || player.LastError() != nil {
+		t.Errorf("GetShuffleOr(true) = %v, %v, want the default", v, player.LastError())
+	}
+	if v := player.GetLoopStatusOr(mpris.LoopNone); v != mpris.LoopNone || player.LastError() != nil {
+		t.Errorf("GetLoopStatusOr(None) = %v, %v, want the default", v, player.LastError())
+	}
+	if v := player.GetRateOr(1); v != 1 || player.LastError() != nil {
+		t.Errorf("GetRateOr(1) = %v, %v, want the default", v, player.LastError())
+	}
+
+	gone := mpris.New(fake.Connect(), mpris.BaseInterface+".gone")
+	if v := gone.GetVolumeOr(1); v != 1 || !errors.Is(gone.LastError(), mpris.ErrPlayerGone) {
+		t.Errorf("GetVolumeOr(1) of a gone player = %v, %v, want ErrPlayerGone", v, gone.LastError())
+	}
+	if gone.GetVolumeOr(1); !errors.Is(gone.LastError(), mpris.ErrPlayerGone) {
+		t.Errorf("LastError() = %v after another failure, want ErrPlayerGone", gone.LastError())
+	}
+}
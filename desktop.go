@@ -0,0 +1,202 @@
+package mpris
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNoDesktopEntry is returned by DesktopFile and IconName when the player
+// doesn't report a DesktopEntry.
+var ErrNoDesktopEntry = fmt.Errorf("%w: no desktop entry", ErrNotSupported)
+
+// ErrDesktopFileNotFound is returned by LookupDesktopFile, DesktopFile and
+// IconName when no desktop file matches the desktop entry. The error names
+// the entry.
+var ErrDesktopFileNotFound = errors.New("mpris: desktop file not found")
+
+// ErrInvalidDesktopEntry is returned by LookupDesktopFile, DesktopFile and
+// IconName for a desktop entry that is neither a desktop file ID nor an
+// absolute path, such as one climbing out of the applications directory.
+var ErrInvalidDesktopEntry = errors.New("mpris: invalid desktop entry")
+
+// DesktopFile holds the keys of a desktop file player pickers usually need.
+// Localized values, such as Name[de], are ignored.
+type DesktopFile struct {
+	// Path is the path of the desktop file.
+	Path string
+	// Name is the name of the application, such as "VLC media player".
+	Name string
+	// Icon is the icon of the application, either a name to look up in the
+	// icon theme, such as "vlc", or an absolute path.
+	Icon string
+	// Exec is the command line starting the application, with its field
+	// codes, such as "/usr/bin/vlc --started-from-file %U".
+	Exec string
+}
+
+// dataDirs returns the XDG base directories searched for data files, most
+// important first: $XDG_DATA_HOME, then $XDG_DATA_DIRS, with the defaults of
+// the XDG Base Directory Specification.
+func dataDirs() []string {
+	var dirs []string
+	if home := os.Getenv("XDG_DATA_HOME"); filepath.IsAbs(home) {
+		dirs = append(dirs, home)
+	} else if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".local", "share"))
+	}
+	system := os.Getenv("XDG_DATA_DIRS")
+	if system == "" {
+		system = "/usr/local/share/:/usr/share/"
+	}
+	for _, dir := range filepath.SplitList(system) {
+		if filepath.IsAbs(dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// LookupDesktopFile finds and parses the desktop file of entry, as reported
+// by the DesktopEntry property: the file entry.desktop in the applications
+// directory of the XDG data directories. Entries that are already absolute
+// paths, which some players report, are read as they are. A missing file is
+// reported as ErrDesktopFileNotFound.
+func LookupDesktopFile(entry string) (DesktopFile, error) {
+	entry = strings.TrimSpace(entry)
+	if filepath.IsAbs(entry) {
+		return readDesktopFile(entry, entry)
+	}
+	name := filepath.FromSlash(strings.TrimSuffix(entry, ".desktop") + ".desktop")
+	if entry == "" || !filepath.IsLocal(name) {
+		return DesktopFile{}, fmt.Errorf("%w: %q", ErrInvalidDesktopEntry, entry)
+	}
+	for _, dir := range dataDirs() {
+		d, err := readDesktopFile(entry, filepath.Join(dir, "applications", name))
+		if !errors.Is(err, ErrDesktopFileNotFound) {
+			return d, err
+		}
+	}
+	return DesktopFile{}, fmt.Errorf("%w: %q", ErrDesktopFileNotFound, entry)
+}
+
+// readDesktopFile reads and parses the desktop file at path for entry.
+func readDesktopFile(entry, path string) (DesktopFile, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return DesktopFile{}, fmt.Errorf(
+			"%w: %q: %w",
+			ErrDesktopFileNotFound,
+			entry,
+			err,
+		)
+	}
+	if err != nil {
+		return DesktopFile{}, fmt.Errorf(
+			"failed to read the desktop file of %q: %w",
+			entry,
+			err,
+		)
+	}
+	defer f.Close()
+	d, err := parseDesktopFile(f)
+	if err != nil {
+		return DesktopFile{}, fmt.Errorf(
+			"failed to parse %s: %w",
+			path,
+			err,
+		)
+	}
+	d.Path = path
+	return d, nil
+}
+
+// parseDesktopFile reads the Name, Icon and Exec keys of the [Desktop Entry]
+// group of a desktop file. Other groups, such as the desktop actions, and
+// other keys are skipped.
+func parseDesktopFile(r io.Reader) (DesktopFile, error) {
+	var d DesktopFile
+	group := ""
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || line[0] == '#':
+			continue
+		case line[0] == '[':
+			group = strings.Trim(line, "[]")
+			continue
+		case group != "Desktop Entry":
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		value = unescapeDesktopValue(strings.TrimSpace(value))
+		switch strings.TrimSpace(key) {
+		case "Name":
+			d.Name = value
+		case "Icon":
+			d.Icon = value
+		case "Exec":
+			d.Exec = value
+		}
+	}
+	return d, scanner.Err()
+}
+
+// unescapeDesktopValue replaces the escape sequences of desktop file string
+// values: \s, \n, \t, \r and \\.
+func unescapeDesktopValue(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	return strings.NewReplacer(
+		`\s`, " ",
+		`\n`, "\n",
+		`\t`, "\t",
+		`\r`, "\r",
+		`\\`, `\`,
+	).Replace(s)
+}
+
+// DesktopFile finds and parses the desktop file of the player, named by its
+// DesktopEntry, with LookupDesktopFile. Players without DesktopEntry return
+// ErrNoDesktopEntry.
+func (i *Player) DesktopFile() (DesktopFile, error) {
+	return i.DesktopFileContext(context.Background())
+}
+
+// DesktopFileContext is like DesktopFile but takes a context.
+func (i *Player) DesktopFileContext(ctx context.Context) (DesktopFile, error) {
+	entry, err := i.GetDesktopEntryContext(ctx)
+	switch {
+	case errors.Is(err, ErrUnknownProperty):
+		return DesktopFile{}, fmt.Errorf("%w: %w", ErrNoDesktopEntry, err)
+	case err != nil:
+		return DesktopFile{}, err
+	case strings.TrimSpace(entry) == "":
+		return DesktopFile{}, fmt.Errorf("%w: %s", ErrNoDesktopEntry, i.name)
+	}
+	return LookupDesktopFile(entry)
+}
+
+// IconName returns the Icon key of the desktop file of the player, see
+// DesktopFile: an icon theme name, such as "vlc", or an absolute path. It is
+// empty when the desktop file has no icon.
+func (i *Player) IconName() (string, error) {
+	return i.IconNameContext(context.Background())
+}
+
+// IconNameContext is like IconName but takes a context.
+func (i *Player) IconNameContext(ctx context.Context) (string, error) {
+	d, err := i.DesktopFileContext(ctx)
+	return d.Icon, err
+}
@@ -0,0 +1,104 @@
+package mpris
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const vlcDesktopFile = `# VLC
+[Desktop Entry]
+Version=1.0
+Name=VLC media player
+Name[de]=VLC Media Player
+Exec=/usr/bin/vlc --started-from-file %U
+Icon=vlc
+Comment=Read\sand play files
+
+[Desktop Action new-window]
+Name=New Window
+Icon=window-new
+`
+
+// writeDesktopFile writes a desktop file called name under the applications
+// directory of the data directory dir.
+func writeDesktopFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, "applications", name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// testDataDirs points the XDG data directories to two empty temporary
+// directories, the user one and a system one.
+func testDataDirs(t *testing.T) (home, system string) {
+	home, system = t.TempDir(), t.TempDir()
+	t.Setenv("XDG_DATA_HOME", home)
+	t.Setenv("XDG_DATA_DIRS", system)
+	return home, system
+}
+
+func TestLookupDesktopFile(t *testing.T) {
+	home, system := testDataDirs(t)
+	path := writeDesktopFile(t, system, "vlc.desktop", vlcDesktopFile)
+	writeDesktopFile(t, system, "spotify.desktop", "[Desktop Entry]\nName=Spotify\nIcon=spotify-client\n")
+	override := writeDesktopFile(t, home, "spotify.desktop", "[Desktop Entry]\nName=My Spotify\n")
+
+	d, err := LookupDesktopFile("vlc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := DesktopFile{
+		Path: path,
+		Name: "VLC media player",
+		Icon: "vlc",
+		Exec: "/usr/bin/vlc --started-from-file %U",
+	}
+	if d != want {
+		t.Errorf("LookupDesktopFile(vlc) = %+v, want %+v", d, want)
+	}
+	if d, err := LookupDesktopFile("vlc.desktop"); err != nil || d.Path != path {
+		t.Errorf("LookupDesktopFile(vlc.desktop) = %+v, %v", d, err)
+	}
+	if d, err := LookupDesktopFile(path); err != nil || d.Name != "VLC media player" {
+		t.Errorf("LookupDesktopFile(%s) = %+v, %v", path, d, err)
+	}
+	if d, err := LookupDesktopFile("spotify"); err != nil || d.Path != override || d.Icon != "" {
+		t.Errorf("LookupDesktopFile(spotify) = %+v, %v, want the user file", d, err)
+	}
+
+	for _, entry := range []string{"rhythmbox", filepath.Join(system, "gone.desktop")} {
+		if _, err := LookupDesktopFile(entry); !errors.Is(err, ErrDesktopFileNotFound) {
+			t.Errorf("LookupDesktopFile(%q) error = %v, want ErrDesktopFileNotFound", entry, err)
+		}
+	}
+	for _, entry := range []string{"", "../vlc"} {
+		if _, err := LookupDesktopFile(entry); !errors.Is(err, ErrInvalidDesktopEntry) {
+			t.Errorf("LookupDesktopFile(%q) error = %v, want ErrInvalidDesktopEntry", entry, err)
+		}
+	}
+}
+
+func TestIconName(t *testing.T) {
+	_, system := testDataDirs(t)
+	writeDesktopFile(t, system, "vlc.desktop", vlcDesktopFile)
+
+	server, player := testBus(t)
+	props := exportTestProperties(t, server, map[string]map[string]any{
+		BaseInterface: {"Identity": "VLC media player", "DesktopEntry": "vlc"},
+	})
+	if icon, err := player.IconName(); err != nil || icon != "vlc" {
+		t.Errorf("IconName() = %q, %v, want vlc", icon, err)
+	}
+
+	props.set(BaseInterface, "DesktopEntry", "")
+	if _, err := player.DesktopFile(); !errors.Is(err, ErrNoDesktopEntry) {
+		t.Errorf("DesktopFile() error = %v, want ErrNoDesktopEntry", err)
+	}
+}
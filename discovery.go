@@ -0,0 +1,293 @@
+package mpris
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// ErrPlayerNotFound is returned when no player on the bus matches a search.
+var ErrPlayerNotFound = errors.New("mpris: player not found")
+
+// findAll returns a Player for every player on the bus for which the base
+// property read by get matches want, ignoring case. Players whose property
+// can't be read are skipped.
+func findAll(
+	conn *dbus.Conn,
+	want string,
+	get func(*Player) (string, error),
+) ([]*Player, error) {
+	names, err := List(conn)
+	if err != nil {
+		return nil, err
+	}
+	var players []*Player
+	for _, name := range names {
+		player := New(conn, name)
+		got, err := get(player)
+		if err != nil {
+			continue
+		}
+		if strings.EqualFold(got, want) {
+			players = append(players, player)
+		}
+	}
+	return players, nil
+}
+
+// findFirst returns the first result of findAll, or ErrPlayerNotFound.
+func findFirst(
+	conn *dbus.Conn,
+	property, want string,
+	get func(*Player) (string, error),
+) (*Player, error) {
+	players, err := findAll(conn, want, get)
+	if err != nil {
+		return nil, err
+	}
+	if len(players) == 0 {
+		return nil, fmt.Errorf("%w: no player with %s %q", ErrPlayerNotFound, property, want)
+	}
+	return players[0], nil
+}
+
+// FindByIdentity returns the first player whose Identity, such as "Spotify",
+// matches identity, ignoring case. It returns ErrPlayerNotFound when nothing
+// matches. Players whose Identity can't be read are skipped.
+func FindByIdentity(conn *dbus.Conn, identity string) (*Player, error) {
+	return findFirst(conn, "Identity", identity, (*Player).GetIdentity)
+}
+
+// FindAllByIdentity is like FindByIdentity, but returns every matching player.
+// The result is empty when nothing matches.
+func FindAllByIdentity(conn *dbus.Conn, identity string) ([]*Player, error) {
+	return findAll(conn, identity, (*Player).GetIdentity)
+}
+
+// FindByDesktopEntry returns the first player whose DesktopEntry, such as
+// "spotify" or "org.gnome.Rhythmbox3", matches entry, ignoring case. It returns
+// ErrPlayerNotFound when nothing matches. Players whose DesktopEntry can't be
+// read are skipped.
+func FindByDesktopEntry(conn *dbus.Conn, entry string) (*Player, error) {
+	return findFirst(conn, "DesktopEntry", entry, (*Player).GetDesktopEntry)
+}
+
+// FindAllByDesktopEntry is like FindByDesktopEntry, but returns every matching
+// player. The result is empty when nothing matches.
+func FindAllByDesktopEntry(conn *dbus.Conn, entry string) ([]*Player, error) {
+	return findAll(conn, entry, (*Player).GetDesktopEntry)
+}
+
+// ListPlayers returns a Player for every player on the bus, in the order of
+// List. Unlike List, it applies every option, querying the players
+// concurrently for the ones that need it, like ListFiltered.
+func ListPlayers(conn *dbus.Conn, opts ...ListOption) ([]*Player, error) {
+	names, err := List(conn, opts...)
+	if err != nil {
+		return nil, err
+	}
+	names = filterQueried(conn, names, opts)
+	players := make([]*Player, 0, len(names))
+	for _, name := range names {
+		players = append(players, New(conn, name))
+	}
+	return players, nil
+}
+
+// PlayerInfo describes a player on the bus, with what a player picker usually
+// shows.
+type PlayerInfo struct {
+	Player         *Player
+	BusName        string
+	Identity       string
+	DesktopEntry   string
+	PlaybackStatus PlaybackStatus
+	// Err holds the errors reading the fields above. The fields that failed
+	// are left empty.
+	Err error
+}
+
+// ListPlayerInfo is like ListPlayers, but also reads the identity, desktop
+// entry and playback status of every player. The players, and the properties
+// of each, are read concurrently. Failing to read them doesn't fail the
+// listing; the error is recorded in PlayerInfo.Err instead.
+func ListPlayerInfo(conn *dbus.Conn) ([]PlayerInfo, error) {
+	players, err := ListPlayers(conn)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]PlayerInfo, len(players))
+	tasks := make([]func(), len(players))
+	for n, player := range players {
+		tasks[n] = func() { infos[n] = playerInfo(player) }
+	}
+	parallel(listWorkers, tasks...)
+	return infos, nil
+}
+
+// playerInfo reads the PlayerInfo of player, querying the base and player
+// interfaces concurrently.
+func playerInfo(player *Player) PlayerInfo {
+	info := PlayerInfo{Player: player, BusName: player.GetName()}
+	var (
+		base               BaseProperties
+		baseErr, statusErr error
+	)
+	parallel(2, func() {
+		base, baseErr = player.GetAllBaseProperties()
+	}, func() {
+		info.PlaybackStatus, statusErr = player.GetPlaybackStatus()
+	})
+	info.Identity = base.Identity
+	info.DesktopEntry = base.DesktopEntry
+	info.Err = errors.Join(baseErr, statusErr)
+	return info
+}
+
+// sortedNames returns the players on the bus sorted by bus name, so repeated
+// selections are stable.
+func sortedNames(conn *dbus.Conn) ([]string, error) {
+	names, err := List(conn)
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// FirstPlayer returns the player with the lowest bus name, or
+// ErrPlayerNotFound when there is none.
+func FirstPlayer(conn *dbus.Conn) (*Player, error) {
+	names, err := sortedNames(conn)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("%w: no players on the bus", ErrPlayerNotFound)
+	}
+	return New(conn, names[0]), nil
+}
+
+// ActivePlayer returns the player the user most likely means: the first
+// playing one, else the first paused one, else the first one, in the order of
+// the bus names. It returns ErrPlayerNotFound when there are no players.
+func ActivePlayer(conn *dbus.Conn) (*Player, error) {
+	names, err := sortedNames(conn)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("%w: no players on the bus", ErrPlayerNotFound)
+	}
+	return mostActive(conn, names), nil
+}
+
+// mostActive returns the first playing player out of names, else the first
+// paused one, else the first one. names must not be empty.
+func mostActive(conn *dbus.Conn, names []string) *Player {
+	var paused *Player
+	for _, name := range names {
+		player := New(conn, name)
+		status, err := player.GetPlaybackStatus()
+		if err != nil {
+			continue
+		}
+		switch status {
+		case PlaybackPlaying:
+			return player
+		case PlaybackPaused:
+			if paused == nil {
+				paused = player
+			}
+		}
+	}
+	if paused != nil {
+		return paused
+	}
+	return New(conn, names[0])
+}
+
+// playerMatcher matches players against name patterns, caching identities.
+type playerMatcher struct {
+	conn       *dbus.Conn
+	identities map[string]string
+}
+
+// globMatch returns whether s matches the path.Match pattern, ignoring case.
+func globMatch(pattern, s string) bool {
+	ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(s))
+	return ok
+}
+
+// nameMatches returns whether the bus name matches pattern, ignoring case,
+// either as its base name or without the MPRIS prefix, so "chromium*" matches
+// every Chromium instance.
+func nameMatches(name, pattern string) bool {
+	return globMatch(pattern, BaseName(name)) ||
+		globMatch(pattern, strings.TrimPrefix(name, BaseInterface+"."))
+}
+
+// matches returns whether the player called name matches pattern. The pattern
+// is matched, ignoring case, against the base name, the bus name without the
+// MPRIS prefix and the identity of the player, using path.Match syntax, so
+// "chromium*" matches every Chromium instance.
+func (m *playerMatcher) matches(name, pattern string) bool {
+	if nameMatches(name, pattern) {
+		return true
+	}
+	identity, ok := m.identities[name]
+	if !ok {
+		identity, _ = New(m.conn, name).GetIdentity()
+		m.identities[name] = identity
+	}
+	return identity != "" && globMatch(pattern, identity)
+}
+
+// matchesAny returns whether the player called name matches any of patterns.
+func (m *playerMatcher) matchesAny(name string, patterns []string) bool {
+	return slices.ContainsFunc(patterns, func(p string) bool {
+		return m.matches(name, p)
+	})
+}
+
+// SelectPlayer picks a player like playerctl's --player and --ignore-player
+// options. Players matching a pattern in ignore are never picked. Out of the
+// rest, players matching the earliest pattern in prefer win, no matter their
+// playback status; when no preferred player is running, any player is
+// picked. Ties are broken like in ActivePlayer.
+//
+// Patterns are matched, ignoring case, against the base name (see BaseName),
+// the bus name without the org.mpris.MediaPlayer2 prefix and the Identity of
+// the player. They use path.Match syntax, so "chromium*" matches every
+// Chromium instance. SelectPlayer returns ErrPlayerNotFound when every player
+// is ignored or there are none.
+func SelectPlayer(conn *dbus.Conn, prefer, ignore []string) (*Player, error) {
+	names, err := sortedNames(conn)
+	if err != nil {
+		return nil, err
+	}
+	m := &playerMatcher{conn: conn, identities: map[string]string{}}
+	names = slices.DeleteFunc(names, func(name string) bool {
+		return m.matchesAny(name, ignore)
+	})
+	if len(names) == 0 {
+		return nil, fmt.Errorf("%w: no players left to select", ErrPlayerNotFound)
+	}
+
+	for _, pattern := range prefer {
+		var matching []string
+		for _, name := range names {
+			if m.matches(name, pattern) {
+				matching = append(matching, name)
+			}
+		}
+		if len(matching) > 0 {
+			return mostActive(conn, matching), nil
+		}
+	}
+	return mostActive(conn, names), nil
+}
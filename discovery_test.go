@@ -0,0 +1,212 @@
+package mpris
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// addTestPlayer claims name on the bus at addr and exports props on it.
+func addTestPlayer(
+	t *testing.T,
+	addr, name string,
+	props map[string]map[string]any,
+) *dbus.Conn {
+	t.Helper()
+	conn := claimName(t, addr, name)
+	exportTestProperties(t, conn, props)
+	return conn
+}
+
+// playerNames returns the sorted bus names of players.
+func playerNames(players []*Player) []string {
+	var names []string
+	for _, p := range players {
+		names = append(names, p.GetName())
+	}
+	slices.Sort(names)
+	return names
+}
+
+func TestFindByIdentity(t *testing.T) {
+	addr := testBusAddress(t)
+	addTestPlayer(t, addr, BaseInterface+".spotify", map[string]map[string]any{
+		BaseInterface: {"Identity": "Spotify", "DesktopEntry": "spotify"},
+	})
+	addTestPlayer(t, addr, BaseInterface+".chromium.instance1", map[string]map[string]any{
+		BaseInterface: {"Identity": "Chromium", "DesktopEntry": "chromium-browser"},
+	})
+	addTestPlayer(t, addr, BaseInterface+".chromium.instance2", map[string]map[string]any{
+		BaseInterface: {"Identity": "chromium", "DesktopEntry": "chromium-browser"},
+	})
+	// A player without properties must not abort the search.
+	claimName(t, addr, BaseInterface+".broken")
+	conn := testConn(t, addr)
+
+	player, err := FindByIdentity(conn, "SPOTIFY")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if player.GetName() != BaseInterface+".spotify" {
+		t.Errorf("FindByIdentity() = %s", player.GetName())
+	}
+
+	player, err = FindByDesktopEntry(conn, "Spotify")
+	if err != nil || player.GetName() != BaseInterface+".spotify" {
+		t.Errorf("FindByDesktopEntry() = %v, %v", player, err)
+	}
+
+	all, err := FindAllByIdentity(conn, "chromium")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{BaseInterface + ".chromium.instance1", BaseInterface + ".chromium.instance2"}
+	if got := playerNames(all); !slices.Equal(got, want) {
+		t.Errorf("FindAllByIdentity() = %q, want %q", got, want)
+	}
+	all, err = FindAllByDesktopEntry(conn, "chromium-browser")
+	if err != nil || !slices.Equal(playerNames(all), want) {
+		t.Errorf("FindAllByDesktopEntry() = %q, %v", playerNames(all), err)
+	}
+
+	if _, err := FindByIdentity(conn, "VLC media player"); !errors.Is(err, ErrPlayerNotFound) {
+		t.Errorf("FindByIdentity() error = %v, want ErrPlayerNotFound", err)
+	}
+	if _, err := FindByDesktopEntry(conn, "vlc"); !errors.Is(err, ErrPlayerNotFound) {
+		t.Errorf("FindByDesktopEntry() error = %v, want ErrPlayerNotFound", err)
+	}
+}
+
+func TestListPlayerInfo(t *testing.T) {
+	addr := testBusAddress(t)
+	addTestPlayer(t, addr, BaseInterface+".vlc", map[string]map[string]any{
+		BaseInterface:   {"Identity": "VLC media player", "DesktopEntry": "vlc"},
+		PlayerInterface: {"PlaybackStatus": "Playing"},
+	})
+	addTestPlayer(t, addr, BaseInterface+".partial", map[string]map[string]any{
+		BaseInterface: {"Identity": "Partial"},
+	})
+	conn := testConn(t, addr)
+
+	players, err := ListPlayers(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{BaseInterface + ".partial", BaseInterface + ".vlc"}
+	if got := playerNames(players); !slices.Equal(got, want) {
+		t.Errorf("ListPlayers() = %q, want %q", got, want)
+	}
+
+	infos, err := ListPlayerInfo(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 2 {
+		t.Fatalf("ListPlayerInfo() returned %d entries, want 2", len(infos))
+	}
+	for _, info := range infos {
+		if info.Player.GetName() != info.BusName {
+			t.Errorf("Player %s doesn't match BusName %s", info.Player.GetName(), info.BusName)
+		}
+		switch info.BusName {
+		case BaseInterface + ".vlc":
+			if info.Err != nil || info.Identity != "VLC media player" ||
+				info.DesktopEntry != "vlc" || info.PlaybackStatus != PlaybackPlaying {
+				t.Errorf("vlc info = %+v", info)
+			}
+		case BaseInterface + ".partial":
+			if info.Err == nil || info.Identity != "Partial" || info.PlaybackStatus != "" {
+				t.Errorf("partial info = %+v", info)
+			}
+		}
+	}
+}
+
+func TestFirstAndActivePlayer(t *testing.T) {
+	addr := testBusAddress(t)
+	conn := testConn(t, addr)
+
+	if _, err := FirstPlayer(conn); !errors.Is(err, ErrPlayerNotFound) {
+		t.Errorf("FirstPlayer() on an empty bus error = %v", err)
+	}
+	if _, err := ActivePlayer(conn); !errors.Is(err, ErrPlayerNotFound) {
+		t.Errorf("ActivePlayer() on an empty bus error = %v", err)
+	}
+
+	status := func(s string) map[string]map[string]any {
+		return map[string]map[string]any{PlayerInterface: {"PlaybackStatus": s}}
+	}
+	a := addTestPlayer(t, addr, BaseInterface+".a", status("Stopped"))
+	addTestPlayer(t, addr, BaseInterface+".c", status("Paused"))
+
+	check := func(get func(*dbus.Conn) (*Player, error), want string) {
+		t.Helper()
+		for range 3 {
+			player, err := get(conn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if player.GetName() != BaseInterface+want {
+				t.Errorf("player = %s, want %s", player.GetName(), want)
+			}
+		}
+	}
+	check(FirstPlayer, ".a")
+	check(ActivePlayer, ".c")
+
+	addTestPlayer(t, addr, BaseInterface+".d", status("Playing"))
+	addTestPlayer(t, addr, BaseInterface+".b", status("Playing"))
+	check(ActivePlayer, ".b")
+
+	if _, err := a.ReleaseName(BaseInterface + ".a"); err != nil {
+		t.Fatal(err)
+	}
+	check(FirstPlayer, ".b")
+}
+
+func TestSelectPlayer(t *testing.T) {
+	addr := testBusAddress(t)
+	conn := testConn(t, addr)
+
+	props := func(identity, status string) map[string]map[string]any {
+		return map[string]map[string]any{
+			BaseInterface:   {"Identity": identity},
+			PlayerInterface: {"PlaybackStatus": status},
+		}
+	}
+	addTestPlayer(t, addr, BaseInterface+".spotify", props("Spotify", "Paused"))
+	addTestPlayer(t, addr, BaseInterface+".chromium.instance1", props("Chromium", "Playing"))
+	addTestPlayer(t, addr, BaseInterface+".mpd", props("Music Player Daemon", "Stopped"))
+
+	tests := []struct {
+		prefer, ignore []string
+		want           string
+	}{
+		{nil, nil, ".chromium.instance1"},
+		{nil, []string{"chromium*"}, ".spotify"},
+		{[]string{"MPD"}, nil, ".mpd"},
+		{[]string{"music player daemon"}, nil, ".mpd"},
+		{[]string{"vlc", "SPOTIFY", "mpd"}, nil, ".spotify"},
+		{[]string{"vlc"}, []string{"chromium"}, ".spotify"},
+		{[]string{"chromium.instance*"}, nil, ".chromium.instance1"},
+		{[]string{"mpd"}, []string{"mpd"}, ".chromium.instance1"},
+	}
+	for _, tt := range tests {
+		player, err := SelectPlayer(conn, tt.prefer, tt.ignore)
+		if err != nil {
+			t.Errorf("SelectPlayer(%q, %q) error = %v", tt.prefer, tt.ignore, err)
+			continue
+		}
+		if player.GetName() != BaseInterface+tt.want {
+			t.Errorf("SelectPlayer(%q, %q) = %s, want %s",
+				tt.prefer, tt.ignore, player.GetName(), tt.want)
+		}
+	}
+
+	_, err := SelectPlayer(conn, nil, []string{"*"})
+	if !errors.Is(err, ErrPlayerNotFound) {
+		t.Errorf("SelectPlayer() with everything ignored error = %v", err)
+	}
+}
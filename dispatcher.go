@@ -0,0 +1,325 @@
+package mpris
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// errConnectionClosed is reported by subscriptions whose connection was closed
+// while they were still active.
+var errConnectionClosed = errors.New("dbus connection closed")
+
+// dispatchers holds the dispatcher of every transport that currently has
+// live subscriptions.
+var (
+	dispatchersMu sync.Mutex
+	dispatchers   = map[transport]*dispatcher{}
+)
+
+// dispatcher receives the signals of a connection through a single channel
+// and fans them out to the subscriptions attached to it. Every subscription
+// owns a queue and a goroutine, so a slow subscriber never blocks the others
+// and each one sees the signals in the order they arrived.
+type dispatcher struct {
+	tr      transport
+	signals chan *dbus.Signal
+	stop    chan struct{}
+
+	mu   sync.Mutex
+	subs map[*Subscription]struct{}
+}
+
+func newDispatcher(tr transport) *dispatcher {
+	return &dispatcher{
+		tr:      tr,
+		signals: make(chan *dbus.Signal, 64),
+		stop:    make(chan struct{}),
+		subs:    map[*Subscription]struct{}{},
+	}
+}
+
+// attach adds s to the dispatcher of tr, starting one if needed.
+func attach(tr transport, s *Subscription) {
+	dispatchersMu.Lock()
+	defer dispatchersMu.Unlock()
+
+	d, ok := dispatchers[tr]
+	if !ok {
+		d = newDispatcher(tr)
+		dispatchers[tr] = d
+		tr.signal(d.signals)
+		go d.run()
+	}
+	d.add(s)
+	s.d = d
+}
+
+// detach removes s from its dispatcher and stops the dispatcher once its last
+// subscription is gone.
+func detach(s *Subscription) {
+	dispatchersMu.Lock()
+	defer dispatchersMu.Unlock()
+
+	d := s.d
+	if d == nil || d.remove(s) > 0 {
+		return
+	}
+	if dispatchers[d.tr] == d {
+		delete(dispatchers, d.tr)
+	}
+	d.tr.removeSignal(d.signals)
+	close(d.stop)
+}
+
+func (d *dispatcher) add(s *Subscription) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.subs[s] = struct{}{}
+}
+
+// remove removes s and returns the number of remaining subscriptions.
+func (d *dispatcher) remove(s *Subscription) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.subs, s)
+	return len(d.subs)
+}
+
+func (d *dispatcher) run() {
+	for {
+		select {
+		case <-d.stop:
+			return
+		case sig, ok := <-d.signals:
+			if !ok {
+				d.terminate(errConnectionClosed)
+				return
+			}
+			d.dispatch(sig)
+		}
+	}
+}
+
+// dispatch queues sig on every subscription interested in it. Every filter
+// runs before sig is queued anywhere, so the state filters update, such as
+// the property cache, is current by the time any handler sees sig.
+func (d *dispatcher) dispatch(sig *dbus.Signal) {
+	stamp := newStamp(sig, time.Now())
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	accepted := make([]*Subscription, 0, len(d.subs))
+	for s := range d.subs {
+		if s.filter == nil || s.filter(sig) {
+			accepted = append(accepted, s)
+		}
+	}
+	for _, s := range accepted {
+		s.pushStamped(sig, stamp)
+	}
+}
+
+// terminate fails every subscription with err.
+func (d *dispatcher) terminate(err error) {
+	dispatchersMu.Lock()
+	if dispatchers[d.tr] == d {
+		delete(dispatchers, d.tr)
+	}
+	dispatchersMu.Unlock()
+	forgetMatches(d.tr)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for s := range d.subs {
+		s.fail(err)
+	}
+}
+
+// Subscription is a handle to a single signal listener started by one of the
+// Watch methods. It holds references to the match rules it needs on the bus,
+// so closing it releases exactly those and leaves other subscriptions
+// untouched.
+type Subscription struct {
+	tr     transport
+	d      *dispatcher
+	rules  [][]dbus.MatchOption
+	filter func(*dbus.Signal) bool
+	handle func(context.Context, *dbus.Signal)
+
+	// detached subscriptions don't wait for their handler in Close, which
+	// allows callbacks to close their own subscription.
+	detached bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wake   chan struct{}
+	done   chan struct{}
+	once   sync.Once
+
+	mu    sync.Mutex
+	queue []stampedSignal
+	err   error
+}
+
+// stampedSignal is a signal queued for delivery with its Stamp.
+type stampedSignal struct {
+	sig   *dbus.Signal
+	stamp Stamp
+}
+
+func newSubscription(
+	filter func(*dbus.Signal) bool,
+	handle func(context.Context, *dbus.Signal),
+) *Subscription {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Subscription{
+		filter: filter,
+		handle: handle,
+		ctx:    ctx,
+		cancel: cancel,
+		wake:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+}
+
+// subscribe adds rules to the bus and starts delivering the signals accepted
+// by filter to handle. handle is called from a single goroutine, one signal at a
+// time; the context it receives is canceled when the subscription closes.
+func subscribe(
+	tr transport,
+	rules [][]dbus.MatchOption,
+	filter func(*dbus.Signal) bool,
+	handle func(context.Context, *dbus.Signal),
+) (*Subscription, error) {
+	s := newSubscription(filter, handle)
+	if err := s.start(tr, rules...); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// start adds rules to the bus and attaches s to the dispatcher of tr. Rules
+// other subscriptions of tr already added are shared; see addMatch.
+func (s *Subscription) start(tr transport, rules ...[]dbus.MatchOption) error {
+	for n, rule := range rules {
+		if err := addMatch(tr, rule); err != nil {
+			for _, added := range rules[:n] {
+				removeMatch(tr, added)
+			}
+			return err
+		}
+	}
+	s.tr = tr
+	s.rules = rules
+	attach(tr, s)
+	go s.run()
+	return nil
+}
+
+// push queues sig for delivery, observed now.
+func (s *Subscription) push(sig *dbus.Signal) {
+	s.pushStamped(sig, newStamp(sig, time.Now()))
+}
+
+// pushStamped queues sig for delivery with stamp.
+func (s *Subscription) pushStamped(sig *dbus.Signal, stamp Stamp) {
+	s.mu.Lock()
+	s.queue = append(s.queue, stampedSignal{sig, stamp})
+	s.mu.Unlock()
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Subscription) run() {
+	defer close(s.done)
+	for {
+		s.mu.Lock()
+		queue := s.queue
+		s.queue = nil
+		s.mu.Unlock()
+
+		for _, e := range queue {
+			if s.ctx.Err() != nil {
+				return
+			}
+			s.handle(withStamp(s.ctx, e.stamp), e.sig)
+		}
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-s.wake:
+		}
+	}
+}
+
+// fail stops the subscription and records err as the reason.
+func (s *Subscription) fail(err error) {
+	s.mu.Lock()
+	if s.err == nil {
+		s.err = err
+	}
+	s.mu.Unlock()
+	s.cancel()
+}
+
+// Close stops the subscription, waits for its delivery goroutine to return
+// and removes its match rules from the bus, unless other subscriptions still
+// use them. Once Close returns nothing is sent to the subscription's channel
+// anymore. Calling Close more than once is safe.
+func (s *Subscription) Close() error {
+	var err error
+	s.once.Do(func() {
+		s.cancel()
+		detach(s)
+		if !s.detached {
+			<-s.done
+		}
+		if s.tr == nil {
+			return
+		}
+		for _, rule := range s.rules {
+			if rerr := removeMatch(s.tr, rule); rerr != nil {
+				err = rerr
+			}
+		}
+	})
+	return err
+}
+
+// Err returns the error that terminated the subscription, such as the
+// connection being closed. It returns nil while the subscription is active and
+// after it was stopped with Close.
+func (s *Subscription) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Done returns a channel that is closed once the subscription stops, either
+// through Close or because it failed.
+func (s *Subscription) Done() <-chan struct{} {
+	return s.ctx.Done()
+}
+
+// wait blocks until ctx is canceled or the subscription fails, then closes
+// the subscription.
+func (s *Subscription) wait(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+	case <-s.ctx.Done():
+	}
+	cerr := s.Close()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	return cerr
+}
@@ -0,0 +1,150 @@
+package mpris
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// newTestDispatcher returns a dispatcher that is not connected to any bus, so
+// signals can be fed to it with dispatch.
+func newTestDispatcher(t *testing.T) *dispatcher {
+	t.Helper()
+	return newDispatcher(nil)
+}
+
+// addTestSubscription attaches a subscription running handle to d.
+func addTestSubscription(
+	t *testing.T,
+	d *dispatcher,
+	handle func(context.Context, *dbus.Signal),
+) *Subscription {
+	t.Helper()
+	s := newSubscription(nil, handle)
+	d.add(s)
+	go s.run()
+	t.Cleanup(func() {
+		d.remove(s)
+		s.Close()
+	})
+	return s
+}
+
+// metadataSignal returns a PropertiesChanged signal carrying m as the new
+// Metadata of the player interface.
+func metadataSignal(m map[string]dbus.Variant) *dbus.Signal {
+	return &dbus.Signal{
+		Sender: ":1.42",
+		Path:   DBusObjectPath,
+		Name:   PropertiesChangedSignal,
+		Body: []any{
+			PlayerInterface,
+			map[string]dbus.Variant{"Metadata": dbus.MakeVariant(m)},
+			[]string{},
+		},
+	}
+}
+
+func TestMetadataSubscribersGetPrivateCopies(t *testing.T) {
+	const (
+		subscribers = 4
+		signals     = 100
+	)
+
+	d := newTestDispatcher(t)
+
+	var wg sync.WaitGroup
+	for range subscribers {
+		ch := make(chan Metadata)
+		handle := (&Player{}).propertiesHandler(
+			PlayerInterface,
+			[]string{"Metadata"},
+			metadataHandler(ch),
+		)
+		addTestSubscription(t, d, handle)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range signals {
+				m := <-ch
+				// Consumers are free to mutate the value they receive.
+				artists, _ := m.Get("xesam:artist")
+				artists.([]string)[0] = "mutated"
+				m["xesam:title"] = dbus.MakeVariant("mutated")
+				delete(m, "mpris:length")
+			}
+		}()
+	}
+
+	// Every signal shares the same decoded map, exactly like godbus hands the
+	// same *dbus.Signal to every channel.
+	shared := map[string]dbus.Variant{
+		"xesam:title":  dbus.MakeVariant("Title"),
+		"xesam:artist": dbus.MakeVariant([]string{"Artist"}),
+		"mpris:length": dbus.MakeVariant(int64(1000)),
+		"xesam:genre": dbus.MakeVariant([]dbus.Variant{
+			dbus.MakeVariant("Rock"),
+		}),
+	}
+	sig := metadataSignal(shared)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		m := Metadata(shared)
+		for range signals {
+			// Reading the shared map while consumers mutate their copies.
+			if _, err := m.Get("xesam:title"); err != nil {
+				t.Error(err)
+			}
+			_ = m.Clone()
+		}
+	}()
+
+	for range signals {
+		d.dispatch(sig)
+	}
+	wg.Wait()
+	<-done
+
+	if got := shared["xesam:title"].Value(); got != "Title" {
+		t.Errorf("shared title changed to %v", got)
+	}
+	if got := shared["xesam:artist"].Value().([]string)[0]; got != "Artist" {
+		t.Errorf("shared artist changed to %v", got)
+	}
+	if _, ok := shared["mpris:length"]; !ok {
+		t.Error("shared length was deleted")
+	}
+}
+
+func TestMetadataClone(t *testing.T) {
+	m := Metadata{
+		"xesam:artist": dbus.MakeVariant([]string{"A", "B"}),
+		"nested": dbus.MakeVariant(map[string]dbus.Variant{
+			"k": dbus.MakeVariant([]any{"x"}),
+		}),
+	}
+
+	c := m.Clone()
+	c["xesam:artist"].Value().([]string)[0] = "changed"
+	nested := c["nested"].Value().(map[string]dbus.Variant)
+	nested["k"].Value().([]any)[0] = "changed"
+
+	if got := m["xesam:artist"].Value().([]string)[0]; got != "A" {
+		t.Errorf("clone shares artist slice, original is now %q", got)
+	}
+	orig := m["nested"].Value().(map[string]dbus.Variant)
+	if got := orig["k"].Value().([]any)[0]; got != "x" {
+		t.Errorf("clone shares nested slice, original is now %v", got)
+	}
+	if c["xesam:artist"].Signature() != m["xesam:artist"].Signature() {
+		t.Error("clone changed the variant signature")
+	}
+	if Metadata(nil).Clone() != nil {
+		t.Error("clone of nil metadata is not nil")
+	}
+}
@@ -0,0 +1,90 @@
+package mpris
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// maxMicroseconds is the largest number of microseconds that fits in a
+// time.Duration.
+const maxMicroseconds = math.MaxInt64 / int64(time.Microsecond)
+
+// microsecondsToDuration converts a value in microseconds, the unit of every
+// MPRIS position and length, to time.Duration. Every integer width is
+// accepted, since players disagree on the type; see toInt64. Values that
+// overflow a time.Duration are rejected.
+func microsecondsToDuration(a any) (time.Duration, error) {
+	micro, err := toInt64(a)
+	if err != nil {
+		return 0, err
+	}
+	if micro > maxMicroseconds || micro < -maxMicroseconds {
+		return 0, fmt.Errorf("%d microseconds overflows time.Duration", micro)
+	}
+	return time.Duration(micro) * time.Microsecond, nil
+}
+
+// durationToMicroseconds converts d to the microseconds sent to the player.
+func durationToMicroseconds(d time.Duration) int64 {
+	return d.Microseconds()
+}
+
+// toLength is microsecondsToDuration for lengths, rejecting negative values.
+func toLength(a any) (time.Duration, error) {
+	d, err := microsecondsToDuration(a)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("negative length %v", d)
+	}
+	return d, nil
+}
+
+// GetPropertyDuration returns the value of a property holding a time in
+// microseconds, the unit of every MPRIS time, as a time.Duration. Every
+// integer width is accepted, since players disagree on the type.
+func (i *Player) GetPropertyDuration(
+	iface, property string,
+) (time.Duration, error) {
+	return i.GetPropertyDurationContext(context.Background(), iface, property)
+}
+
+// GetPropertyDurationContext is like GetPropertyDuration but takes a context.
+func (i *Player) GetPropertyDurationContext(
+	ctx context.Context,
+	iface, property string,
+) (time.Duration, error) {
+	return getPropertyCast(ctx, i, iface, property, microsecondsToDuration)
+}
+
+// SetPropertyDuration sets a property holding a time in microseconds to d,
+// sent as an int64 like the spec mandates. Properties hold positions and
+// lengths, never offsets, so negative durations are rejected with
+// ErrOutOfRange.
+func (i *Player) SetPropertyDuration(
+	iface, property string,
+	d time.Duration,
+) error {
+	return i.SetPropertyDurationContext(context.Background(), iface, property, d)
+}
+
+// SetPropertyDurationContext is like SetPropertyDuration but takes a context.
+func (i *Player) SetPropertyDurationContext(
+	ctx context.Context,
+	iface, property string,
+	d time.Duration,
+) error {
+	if d < 0 {
+		return fmt.Errorf(
+			"%w: negative duration %v for %s.%s",
+			ErrOutOfRange,
+			d,
+			iface,
+			property,
+		)
+	}
+	return i.SetPropertyContext(ctx, iface, property, durationToMicroseconds(d))
+}
@@ -0,0 +1,195 @@
+package mpris
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestMicrosecondsToDuration(t *testing.T) {
+	tests := []struct {
+		in   any
+		want time.Duration
+	}{
+		{int64(1_500_000), 1500 * time.Millisecond},
+		{uint64(1_500_000), 1500 * time.Millisecond},
+		{int32(250_000), 250 * time.Millisecond},
+		{uint32(250_000), 250 * time.Millisecond},
+		{int16(-1000), -time.Millisecond},
+		{uint16(1000), time.Millisecond},
+		{byte(1), time.Microsecond},
+		{int64(-1), -time.Microsecond},
+	}
+	for _, tt := range tests {
+		got, err := microsecondsToDuration(tt.in)
+		if err != nil {
+			t.Errorf("microsecondsToDuration(%T %v) returned error: %v", tt.in, tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("microsecondsToDuration(%T %v) = %v, want %v", tt.in, tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []any{int64(math.MaxInt64), uint64(math.MaxUint64), 0.5} {
+		if got, err := microsecondsToDuration(in); err == nil {
+			t.Errorf("microsecondsToDuration(%T %v) = %v, want error", in, in, got)
+		}
+	}
+}
+
+func TestDurationToMicroseconds(t *testing.T) {
+	for _, d := range []time.Duration{0, time.Microsecond, -2 * time.Second, time.Hour} {
+		got, err := microsecondsToDuration(durationToMicroseconds(d))
+		if err != nil || got != d {
+			t.Errorf("round trip of %v = %v, %v", d, got, err)
+		}
+	}
+}
+
+func TestToLength(t *testing.T) {
+	if got, err := toLength(uint64(3_000_000)); err != nil || got != 3*time.Second {
+		t.Errorf("toLength(3s) = %v, %v", got, err)
+	}
+	if _, err := toLength(int64(-1)); err == nil {
+		t.Error("toLength accepted a negative length")
+	}
+}
+
+func TestGetPositionEncodings(t *testing.T) {
+	for _, position := range []any{int64(2_000_000), uint64(2_000_000), int32(2_000_000)} {
+		server, player := testBus(t)
+		exportTestProperties(t, server, map[string]map[string]any{
+			PlayerInterface: {"Position": position},
+		})
+
+		got, err := player.GetPosition()
+		if err != nil {
+			t.Errorf("GetPosition with %T returned error: %v", position, err)
+			continue
+		}
+		if got != 2*time.Second {
+			t.Errorf("GetPosition with %T = %v, want 2s", position, got)
+		}
+	}
+}
+
+func TestGetLengthRejectsNegative(t *testing.T) {
+	player := testMetadataPlayer(t, map[string]any{"mpris:length": int64(-5)})
+	if got, err := player.GetLength(); err == nil {
+		t.Errorf("GetLength() = %v, want error", got)
+	}
+}
+
+func TestSetPositionForRejectsNegative(t *testing.T) {
+	_, player := testBus(t)
+	err := player.SetPositionFor("/org/mpd/Tracks/1", -time.Second)
+	if !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("SetPositionFor() = %v, want ErrOutOfRange", err)
+	}
+}
+
+func TestSetPositionForValidates(t *testing.T) {
+	player := testMetadataPlayer(t, map[string]any{
+		"mpris:trackid": dbus.ObjectPath("/org/mpd/Tracks/1"),
+		"mpris:length":  int64(60_000_000),
+	})
+	tests := []struct {
+		trackID  dbus.ObjectPath
+		position time.Duration
+		want     error
+	}{
+		{"/org/mpd/Tracks/1", 61 * time.Second, ErrOutOfRange},
+		{NoTrack, time.Second, ErrNoTrack},
+		{"not a path", time.Second, ErrInvalidTrackID},
+	}
+	for _, tt := range tests {
+		err := player.SetPositionFor(tt.trackID, tt.position)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("SetPositionFor(%q, %v) = %v, want %v", tt.trackID, tt.position, err, tt.want)
+		}
+	}
+	// The length of the current track says nothing about another one.
+	err := player.SetPositionFor("/org/mpd/Tracks/2", 61*time.Second)
+	if errors.Is(err, ErrOutOfRange) {
+		t.Errorf("SetPositionFor(another track) = %v", err)
+	}
+
+	if err := player.SetTrackPosition(nil, time.Second); !errors.Is(err, ErrInvalidTrackID) {
+		t.Errorf("SetTrackPosition(nil) = %v, want ErrInvalidTrackID", err)
+	}
+}
+
+func TestPropertyDuration(t *testing.T) {
+	server, player := testBus(t)
+	exportTestProperties(t, server, map[string]map[string]any{
+		PlayerInterface: {"Position": uint64(3_000_000)},
+		"org.example.Vendor": {
+			"Cached": int32(1_500_000),
+			"Delay":  int64(-250_000),
+			"Name":   "not a duration",
+		},
+	})
+
+	tests := []struct {
+		iface, property string
+		want            time.Duration
+	}{
+		{PlayerInterface, "Position", 3 * time.Second},
+		{"org.example.Vendor", "Cached", 1500 * time.Millisecond},
+		{"org.example.Vendor", "Delay", -250 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		got, err := player.GetPropertyDuration(tt.iface, tt.property)
+		if err != nil || got != tt.want {
+			t.Errorf("GetPropertyDuration(%s) = %v, %v, want %v", tt.property, got, err, tt.want)
+		}
+	}
+	if got, err := player.GetPropertyDuration("org.example.Vendor", "Name"); err == nil {
+		t.Errorf("GetPropertyDuration(Name) = %v, want an error", got)
+	}
+
+	if err := player.SetPropertyDuration("org.example.Vendor", "Cached", 2*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := player.GetPropertyDuration("org.example.Vendor", "Cached"); err != nil || got != 2*time.Second {
+		t.Errorf("Cached = %v, %v after SetPropertyDuration, want 2s", got, err)
+	}
+	err := player.SetPropertyDuration("org.example.Vendor", "Cached", -time.Second)
+	if !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("SetPropertyDuration(-1s) = %v, want ErrOutOfRange", err)
+	}
+}
+
+// seekRecorder records the offsets of the Seek calls it gets.
+type seekRecorder struct {
+	offsets chan int64
+}
+
+// Offset implements org.mpris.MediaPlayer2.Player.Seek, under another name
+// since Seek would look like io.Seeker to go vet.
+func (s *seekRecorder) Offset(offset int64) *dbus.Error {
+	s.offsets <- offset
+	return nil
+}
+
+func TestSeekNegative(t *testing.T) {
+	server, player := testBus(t)
+	rec := &seekRecorder{offsets: make(chan int64, 1)}
+	methods := map[string]string{"Offset": "Seek"}
+	err := server.ExportWithMap(rec, methods, DBusObjectPath, PlayerInterface)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Seek offsets, unlike positions, go backward when negative.
+	if err := player.Seek(-2 * time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if got := <-rec.offsets; got != -2_000_000 {
+		t.Errorf("Seek(-2s) sent %d, want -2000000", got)
+	}
+}
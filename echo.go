@@ -0,0 +1,120 @@
+package mpris
+
+import (
+	"maps"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// echoTolerance is how far a number reported back may be from the one
+// written and still be taken as its echo, since players round volumes and
+// rates.
+const echoTolerance = 1e-3
+
+// WithEchoSuppression makes the property handlers of the player, given to
+// Subscribe or behind the Watch and On methods, skip the changes that echo a
+// value the player itself wrote less than window ago, with SetVolume or any
+// other setter. A UI updating a slider from VolumeChanged then doesn't get
+// its own writes back, rounded by the player, and fight the user. Numbers
+// within 1e-3 of the value written count as echoes. Changes made by others
+// are delivered as usual.
+func WithEchoSuppression(window time.Duration) Option {
+	return func(p *Player) {
+		p.echoWindow = window
+	}
+}
+
+// ownWrite is a value the player wrote to a property.
+type ownWrite struct {
+	value any
+	at    time.Time
+}
+
+// recordWrite remembers that value is being written to iface.property, for
+// dropEchoes. It returns the record, for forgetWrite.
+func (i *Player) recordWrite(iface, property string, value any) ownWrite {
+	if v, ok := value.(dbus.Variant); ok {
+		value = v.Value()
+	}
+	w := ownWrite{value: value, at: time.Now()}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.writes == nil {
+		i.writes = map[string]ownWrite{}
+	}
+	i.writes[iface+"."+property] = w
+	return w
+}
+
+// forgetWrite drops the record of w, a write that failed, unless another
+// write replaced it.
+func (i *Player) forgetWrite(iface, property string, w ownWrite) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	key := iface + "." + property
+	if i.writes[key].at.Equal(w.at) {
+		delete(i.writes, key)
+	}
+}
+
+// dropEchoes returns pc without the changes echoing the recent writes of the
+// player, see WithEchoSuppression. The changed map of pc is shared with other
+// subscriptions, so it is copied before anything is dropped.
+func (i *Player) dropEchoes(pc propertiesChanged) propertiesChanged {
+	if i.echoWindow <= 0 {
+		return pc
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	var changed map[string]dbus.Variant
+	for property, v := range pc.changed {
+		key := pc.iface + "." + property
+		w, ok := i.writes[key]
+		if !ok {
+			continue
+		}
+		if time.Since(w.at) > i.echoWindow {
+			delete(i.writes, key)
+			continue
+		}
+		if !sameValue(w.value, v.Value()) {
+			continue
+		}
+		if changed == nil {
+			changed = maps.Clone(pc.changed)
+		}
+		delete(changed, property)
+	}
+	if changed != nil {
+		pc.changed = changed
+	}
+	return pc
+}
+
+// sameValue reports whether a and b are equal, numbers being compared by
+// value within echoTolerance whatever their type.
+func sameValue(a, b any) bool {
+	x, okA := toNumber(a)
+	y, okB := toNumber(b)
+	if okA && okB {
+		return math.Abs(x-y) <= echoTolerance
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// toNumber returns a as a float64 when it is a number.
+func toNumber(a any) (float64, bool) {
+	v := reflect.ValueOf(a)
+	switch {
+	case v.CanFloat():
+		return v.Float(), true
+	case v.CanInt():
+		return float64(v.Int()), true
+	case v.CanUint():
+		return float64(v.Uint()), true
+	}
+	return 0, false
+}
@@ -0,0 +1,76 @@
+package mpris
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestEchoSuppression(t *testing.T) {
+	for _, suppress := range []bool{false, true} {
+		addr := testBusAddress(t)
+		server := claimName(t, addr, testPlayerName)
+		exportTestProperties(t, server, map[string]map[string]any{
+			PlayerInterface: {"Volume": 1.0},
+		})
+		var opts []Option
+		if suppress {
+			opts = append(opts, WithEchoSuppression(time.Minute))
+		}
+		player := New(testConn(t, addr), testPlayerName, opts...)
+
+		volumes := make(chan float64, 4)
+		sub, err := player.Subscribe(context.Background(), VolumeChanged(func(v float64) {
+			volumes <- v
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sub.Close()
+
+		if err := player.SetVolume(0.4); err != nil {
+			t.Fatal(err)
+		}
+		// The echo, rounded by the player.
+		emitPropertiesChanged(t, server, PlayerInterface, map[string]dbus.Variant{
+			"Volume": dbus.MakeVariant(0.4004),
+		})
+		emitPropertiesChanged(t, server, PlayerInterface, map[string]dbus.Variant{
+			"Volume": dbus.MakeVariant(0.7),
+		})
+
+		if !suppress {
+			for _, want := range []float64{0.4, 0.4004, 0.7} {
+				if got := receive(t, volumes); got != want {
+					t.Errorf("volume = %v, want %v", got, want)
+				}
+			}
+			continue
+		}
+		if got := receive(t, volumes); got != 0.7 {
+			t.Errorf("volume = %v with echoes suppressed, want 0.7", got)
+		}
+		expectNothing(t, volumes)
+	}
+}
+
+func TestSameValue(t *testing.T) {
+	tests := []struct {
+		a, b any
+		want bool
+	}{
+		{0.5, 0.5004, true},
+		{0.5, 0.6, false},
+		{int64(5), uint32(5), true},
+		{"Track", "Track", true},
+		{"Track", "Playlist", false},
+		{true, 1.0, false},
+	}
+	for _, tt := range tests {
+		if got := sameValue(tt.a, tt.b); got != tt.want {
+			t.Errorf("sameValue(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package mpris
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// Errors returned when the player rejects a call. They wrap the original
+// dbus.Error, so both errors.Is with these and errors.As with dbus.Error work.
+var (
+	// ErrNotSupported means the player doesn't support the operation or
+	// doesn't implement the interface at all.
+	ErrNotSupported = errors.New("mpris: not supported")
+	// ErrUnknownProperty means the player doesn't implement the property.
+	ErrUnknownProperty = errors.New("mpris: unknown property")
+	// ErrUnknownMethod means the player doesn't implement the method.
+	ErrUnknownMethod = errors.New("mpris: unknown method")
+	// ErrPlayerGone means the player is no longer on the bus, e.g. because it
+	// quit. The Player should be dropped; List finds the remaining ones.
+	ErrPlayerGone = errors.New("mpris: player is gone")
+	// ErrServiceUnknown means no player owns the bus name. It wraps
+	// ErrPlayerGone.
+	ErrServiceUnknown = fmt.Errorf("%w: service unknown", ErrPlayerGone)
+	// ErrOutOfRange means a value was rejected before being sent to the
+	// player, because it is outside what the player or the spec allows.
+	ErrOutOfRange = errors.New("mpris: value out of range")
+	// ErrNotAllowed means a call was not made because the capability the
+	// player advertises for it, such as CanGoNext, is false.
+	ErrNotAllowed = errors.New("mpris: not allowed")
+	// ErrQueueFull means a call was not made because the queue of
+	// WithSerializedCalls was full.
+	ErrQueueFull = errors.New("mpris: call queue full")
+	// ErrInvalidTrackID means a track ID isn't a valid D-Bus object path,
+	// as some players, like mpv, send, so it can't be passed back to them.
+	ErrInvalidTrackID = errors.New("mpris: invalid track ID")
+	// ErrNoTrack means a call was not made because it names the NoTrack
+	// track ID, which stands for no track at all.
+	ErrNoTrack = errors.New("mpris: no track")
+	// ErrUnknownLength is returned by functions that need the length of the
+	// current track when the player doesn't report one, which is common for
+	// live streams.
+	ErrUnknownLength = errors.New("mpris: track length unknown")
+	// ErrTrackChanged is returned by LoopSection when the player moves to
+	// another track, which ends the loop.
+	ErrTrackChanged = errors.New("mpris: track changed")
+)
+
+// dbusErrors maps D-Bus error names to the sentinel errors of this package.
+var dbusErrors = map[string]error{
+	"org.freedesktop.DBus.Error.NotSupported":                ErrNotSupported,
+	"org.freedesktop.DBus.Error.UnknownInterface":            ErrNotSupported,
+	"org.freedesktop.DBus.Error.UnknownObject":               ErrNotSupported,
+	"org.freedesktop.DBus.Error.PropertyReadOnly":            ErrNotSupported,
+	"org.freedesktop.DBus.Error.UnknownProperty":             ErrUnknownProperty,
+	"org.freedesktop.DBus.Properties.Error.PropertyNotFound": ErrUnknownProperty,
+	"org.freedesktop.DBus.Error.UnknownMethod":               ErrUnknownMethod,
+	"org.freedesktop.DBus.Error.ServiceUnknown":              ErrServiceUnknown,
+	"org.freedesktop.DBus.Error.NameHasNoOwner":              ErrServiceUnknown,
+}
+
+// dbusErrorName returns the name of the dbus.Error in err's chain, if any.
+func dbusErrorName(err error) (string, bool) {
+	var e dbus.Error
+	if errors.As(err, &e) {
+		return e.Name, true
+	}
+	var p *dbus.Error
+	if errors.As(err, &p) && p != nil {
+		return p.Name, true
+	}
+	return "", false
+}
+
+// translateError makes err match the sentinel error for its D-Bus error name
+// with errors.Is. Other errors are returned as they are.
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+	name, ok := dbusErrorName(err)
+	if !ok {
+		return err
+	}
+	sentinel, ok := dbusErrors[name]
+	if !ok || errors.Is(err, sentinel) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", sentinel, err)
+}
+
+// missingAsNotSupported makes an error about a property the player doesn't
+// implement also match ErrNotSupported, for features that need the property.
+func missingAsNotSupported(err error) error {
+	if errors.Is(err, ErrUnknownProperty) && !errors.Is(err, ErrNotSupported) {
+		return fmt.Errorf("%w: %w", ErrNotSupported, err)
+	}
+	return err
+}
+
+// callContext calls method on the player object and translates the error.
+func (i *Player) callContext(
+	ctx context.Context,
+	method string,
+	args ...any,
+) error {
+	err := i.do(ctx, method, args...).Err
+	if err != nil {
+		return fmt.Errorf("failed to call %s: %w", method, translateError(err))
+	}
+	return nil
+}
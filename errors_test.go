@@ -0,0 +1,109 @@
+package mpris
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestTranslateError(t *testing.T) {
+	tests := []struct {
+		name string
+		want error
+	}{
+		{"org.freedesktop.DBus.Error.NotSupported", ErrNotSupported},
+		{"org.freedesktop.DBus.Error.UnknownProperty", ErrUnknownProperty},
+		{"org.freedesktop.DBus.Error.UnknownMethod", ErrUnknownMethod},
+		{"org.freedesktop.DBus.Error.ServiceUnknown", ErrServiceUnknown},
+		{"org.freedesktop.DBus.Error.Failed", nil},
+	}
+	sentinels := []error{ErrNotSupported, ErrUnknownProperty, ErrUnknownMethod, ErrServiceUnknown}
+
+	for _, tt := range tests {
+		for _, raw := range []error{
+			dbus.Error{Name: tt.name, Body: []any{"message"}},
+			dbus.NewError(tt.name, nil),
+			fmt.Errorf("wrapped: %w", dbus.Error{Name: tt.name}),
+		} {
+			err := translateError(raw)
+			for _, s := range sentinels {
+				if got := errors.Is(err, s); got != (s == tt.want) {
+					t.Errorf("errors.Is(translateError(%v), %v) = %v", raw, s, got)
+				}
+			}
+			if name, ok := dbusErrorName(err); !ok || name != tt.name {
+				t.Errorf("translateError(%v) lost the original error", raw)
+			}
+		}
+	}
+
+	if translateError(nil) != nil {
+		t.Error("translateError(nil) != nil")
+	}
+}
+
+func TestErrorsFromPlayer(t *testing.T) {
+	server, player := testBus(t)
+	exportTestProperties(t, server, map[string]map[string]any{
+		PlayerInterface: {"PlaybackStatus": "Playing"},
+	})
+
+	if _, err := player.GetLoopStatus(); !errors.Is(err, ErrUnknownProperty) {
+		t.Errorf("GetLoopStatus() error = %v, want ErrUnknownProperty", err)
+	}
+	var dbusErr dbus.Error
+	if _, err := player.GetLoopStatus(); !errors.As(err, &dbusErr) {
+		t.Errorf("GetLoopStatus() error %v is not a dbus.Error", err)
+	}
+	if err := player.Next(); !errors.Is(err, ErrUnknownMethod) && !errors.Is(err, ErrNotSupported) {
+		t.Errorf("Next() error = %v, want ErrUnknownMethod or ErrNotSupported", err)
+	}
+
+	gone := New(player.conn, BaseInterface+".gone")
+	if err := gone.Play(); !errors.Is(err, ErrServiceUnknown) {
+		t.Errorf("Play() on a missing player error = %v, want ErrServiceUnknown", err)
+	}
+	if _, err := gone.getOwner(); !errors.Is(err, ErrServiceUnknown) {
+		t.Errorf("getOwner() of a missing player error = %v, want ErrServiceUnknown", err)
+	}
+}
+
+func TestErrPlayerGone(t *testing.T) {
+	_, player := testBus(t)
+	gone := New(player.conn, BaseInterface+".doesNotExist")
+
+	calls := map[string]func() error{
+		"Play":  gone.Play,
+		"Raise": gone.Raise,
+		"GetMetadata": func() error {
+			_, err := gone.GetMetadata()
+			return err
+		},
+		"SetVolume": func() error { return gone.SetVolume(0.5) },
+		"GetAllPlayerProperties": func() error {
+			_, err := gone.GetAllPlayerProperties()
+			return err
+		},
+		"WatchMetadataChanged": func() error {
+			_, err := gone.WatchMetadataChanged(make(chan Metadata))
+			return err
+		},
+		"WatchSeeked": func() error {
+			_, err := gone.WatchSeeked(make(chan time.Duration))
+			return err
+		},
+		"Subscribe": func() error {
+			_, err := gone.Subscribe(context.Background())
+			return err
+		},
+	}
+	for name, call := range calls {
+		if err := call(); !errors.Is(err, ErrPlayerGone) {
+			t.Errorf("%s() error = %v, want ErrPlayerGone", name, err)
+		}
+	}
+}
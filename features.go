@@ -0,0 +1,73 @@
+package mpris
+
+import (
+	"slices"
+	"sync"
+)
+
+// Feature identifies an optional part of this module that may or may not be
+// linked into the program.
+type Feature string
+
+//revive:disable:exported
+
+const (
+	FeatureServer    Feature = "server"
+	FeatureSystemBus Feature = "system-bus"
+)
+
+//revive:enable:exported
+
+// FeatureSet reports which optional features are available at runtime.
+type FeatureSet struct {
+	// Server is true when the server subpackage, which exports a player over
+	// D-Bus, is linked into the program.
+	Server bool
+	// SystemBus is true unless the module was built with the
+	// mpris_nosystembus tag.
+	SystemBus bool
+}
+
+var (
+	featuresMu sync.RWMutex
+	features   = map[Feature]bool{}
+)
+
+// RegisterFeature marks f as available. Optional subpackages and build-tagged
+// files call it from their init functions, so applications normally never need
+// to call it themselves.
+func RegisterFeature(f Feature) {
+	featuresMu.Lock()
+	defer featuresMu.Unlock()
+	features[f] = true
+}
+
+// HasFeature returns whether f was registered.
+func HasFeature(f Feature) bool {
+	featuresMu.RLock()
+	defer featuresMu.RUnlock()
+	return features[f]
+}
+
+// RegisteredFeatures returns every registered feature, sorted, including ones
+// unknown to FeatureSet.
+func RegisteredFeatures() []Feature {
+	featuresMu.RLock()
+	defer featuresMu.RUnlock()
+	list := make([]Feature, 0, len(features))
+	for f := range features {
+		list = append(list, f)
+	}
+	slices.Sort(list)
+	return list
+}
+
+// Features returns the optional features available in this program, so that
+// applications can e.g. only offer "Expose this app over MPRIS" when the server
+// support is actually linked in.
+func Features() FeatureSet {
+	return FeatureSet{
+		Server:    HasFeature(FeatureServer),
+		SystemBus: HasFeature(FeatureSystemBus),
+	}
+}
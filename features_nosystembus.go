@@ -0,0 +1,6 @@
+//go:build mpris_nosystembus
+
+package mpris
+
+// systemBusSupport reports whether system bus support is compiled in.
+const systemBusSupport = false
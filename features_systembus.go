@@ -0,0 +1,10 @@
+//go:build !mpris_nosystembus
+
+package mpris
+
+// systemBusSupport reports whether system bus support is compiled in.
+const systemBusSupport = true
+
+func init() {
+	RegisterFeature(FeatureSystemBus)
+}
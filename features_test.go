@@ -0,0 +1,54 @@
+package mpris
+
+import (
+	"slices"
+	"testing"
+)
+
+// withFeatures restores the registered features when the test ends.
+func withFeatures(t *testing.T) {
+	t.Helper()
+	featuresMu.Lock()
+	saved := make(map[Feature]bool, len(features))
+	for f, ok := range features {
+		saved[f] = ok
+	}
+	featuresMu.Unlock()
+	t.Cleanup(func() {
+		featuresMu.Lock()
+		features = saved
+		featuresMu.Unlock()
+	})
+}
+
+func TestFeaturesDefault(t *testing.T) {
+	got := Features()
+	if got.Server {
+		t.Error("Server reported without the server package linked in")
+	}
+	if got.SystemBus != systemBusSupport {
+		t.Errorf("SystemBus = %v, want %v", got.SystemBus, systemBusSupport)
+	}
+}
+
+func TestRegisterFeature(t *testing.T) {
+	withFeatures(t)
+
+	RegisterFeature(FeatureServer)
+	RegisterFeature(FeatureServer)
+	RegisterFeature("custom")
+
+	if !Features().Server {
+		t.Error("Server not reported after registration")
+	}
+	if !HasFeature("custom") {
+		t.Error("custom feature not reported after registration")
+	}
+	want := []Feature{"custom", FeatureServer}
+	if systemBusSupport {
+		want = append(want, FeatureSystemBus)
+	}
+	if got := RegisteredFeatures(); !slices.Equal(got, want) {
+		t.Errorf("RegisteredFeatures() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,247 @@
+package mpris
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Format renders tmpl, a text/template, with the state of the player, in the
+// spirit of playerctl --format:
+//
+//	{{artist}} - {{title}} [{{duration(position)}}]
+//
+// The template can use the fields artist (the artists joined with ", "),
+// title, album, status, volume, position and length, both in microseconds,
+// and playerName, the application part of the bus name such as "vlc". The
+// helpers are duration, which formats microseconds or a time.Duration as
+// m:ss, or h:mm:ss past an hour; emoji, which shows a playback status or a
+// volume as an emoji; lc and uc, which change the case; default, which
+// returns its second argument when the first is empty; and trunc, which
+// shortens a string to a number of characters, ending it with "…". The
+// helpers can be called both playerctl style, as duration(position), and
+// template style, as (duration position). The dot is the Status, for the
+// fields without a name, such as {{.Track.TrackNumber}}.
+//
+// The state is read with a single Status, so formatting takes about one
+// round trip. Properties the player doesn't report or that can't be read are
+// rendered as their zero value.
+func (i *Player) Format(tmpl string) (string, error) {
+	return i.FormatContext(context.Background(), tmpl)
+}
+
+// FormatContext is like Format but takes a context.
+func (i *Player) FormatContext(ctx context.Context, tmpl string) (string, error) {
+	var s Status
+	t, err := template.New("format").
+		Funcs(i.formatFuncs(&s)).
+		Parse(translateFormat(tmpl))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse the format: %w", err)
+	}
+	// Status reports the fields it couldn't read alongside the others, which
+	// are still worth showing.
+	s, err = i.StatusContext(ctx)
+	if err != nil && s.Errors == nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, s); err != nil {
+		return "", fmt.Errorf("failed to format %s: %w", i.name, err)
+	}
+	return b.String(), nil
+}
+
+// formatFuncs returns the fields and helpers of Format, the fields reading
+// *s when the template is executed.
+func (i *Player) formatFuncs(s *Status) template.FuncMap {
+	return template.FuncMap{
+		"artist": func() string { return strings.Join(s.Track.Artists, ", ") },
+		"title":  func() string { return s.Track.Title },
+		"album":  func() string { return s.Track.Album },
+		"status": func() PlaybackStatus { return s.PlaybackStatus },
+		"volume": func() float64 { return s.Volume },
+		"position": func() int64 {
+			return durationToMicroseconds(s.Position)
+		},
+		"length": func() int64 {
+			return durationToMicroseconds(s.Track.Length)
+		},
+		"playerName": i.ShortName,
+		"duration":   formatDuration,
+		"emoji":      formatEmoji,
+		"lc":         func(v any) string { return strings.ToLower(fmt.Sprint(v)) },
+		"uc":         func(v any) string { return strings.ToUpper(fmt.Sprint(v)) },
+		"default":    formatDefault,
+		"trunc":      formatTrunc,
+	}
+}
+
+// formatDuration formats v, microseconds or a time.Duration, as m:ss, or as
+// h:mm:ss from an hour on. Negative durations are formatted as 0:00.
+func formatDuration(v any) (string, error) {
+	d, ok := v.(time.Duration)
+	if !ok {
+		var err error
+		if d, err = microsecondsToDuration(v); err != nil {
+			return "", fmt.Errorf("duration: %w", err)
+		}
+	}
+	seconds := int64(max(d, 0) / time.Second)
+	if seconds >= 3600 {
+		return fmt.Sprintf(
+			"%d:%02d:%02d",
+			seconds/3600,
+			seconds/60%60,
+			seconds%60,
+		), nil
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60), nil
+}
+
+// formatEmoji returns the emoji of a playback status, or of a volume as
+// playerctl does: a speaker with more waves the louder it is.
+func formatEmoji(v any) (string, error) {
+	switch v := v.(type) {
+	case PlaybackStatus:
+		return formatEmoji(string(v))
+	case string:
+		switch PlaybackStatus(v) {
+		case PlaybackPlaying:
+			return "▶️", nil
+		case PlaybackPaused:
+			return "⏸️", nil
+		case PlaybackStopped:
+			return "⏹️", nil
+		}
+		return "", fmt.Errorf("emoji: unknown playback status %q", v)
+	case float64:
+		switch {
+		case v < 0.3:
+			return "🔈", nil
+		case v < 0.7:
+			return "🔉", nil
+		}
+		return "🔊", nil
+	}
+	return "", fmt.Errorf("emoji: unsupported value %v of type %T", v, v)
+}
+
+// formatDefault returns fallback when v is empty.
+func formatDefault(v, fallback any) any {
+	if v == nil || fmt.Sprint(v) == "" {
+		return fallback
+	}
+	return v
+}
+
+// formatTrunc shortens v to n characters, the last of them being "…".
+func formatTrunc(v any, n int) string {
+	s := []rune(fmt.Sprint(v))
+	if len(s) <= n {
+		return string(s)
+	}
+	if n <= 0 {
+		return ""
+	}
+	return string(s[:n-1]) + "…"
+}
+
+// translateFormat rewrites the function calls of playerctl's format syntax
+// inside the actions of tmpl, such as duration(position) or trunc(title, 10),
+// as text/template calls, (duration position) and (trunc title 10). The rest
+// of the template, including strings, is left as it is.
+func translateFormat(tmpl string) string {
+	var b strings.Builder
+	for {
+		start := strings.Index(tmpl, "{{")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(tmpl[start:], "}}")
+		if end < 0 {
+			break
+		}
+		end += start
+		b.WriteString(tmpl[:start+2])
+		b.WriteString(translateAction(tmpl[start+2 : end]))
+		tmpl = tmpl[end:]
+		b.WriteString(tmpl[:2])
+		tmpl = tmpl[2:]
+	}
+	b.WriteString(tmpl)
+	return b.String()
+}
+
+// translateAction rewrites the function calls of playerctl's format syntax
+// in the action a. See translateFormat.
+func translateAction(a string) string {
+	var (
+		out   []byte
+		calls []bool // whether each open parenthesis opened a call
+	)
+	for n := 0; n < len(a); n++ {
+		c := a[n]
+		switch {
+		case c == '"' || c == '`':
+			end := quoteEnd(a, n)
+			out = append(out, a[n:end]...)
+			n = end - 1
+		case c == '(':
+			word := len(out)
+			for word > 0 && isIdentByte(out[word-1]) {
+				word--
+			}
+			call := word < len(out) &&
+				(out[word] < '0' || out[word] > '9') &&
+				(word == 0 || out[word-1] != '.' && out[word-1] != '$')
+			calls = append(calls, call)
+			if !call {
+				out = append(out, c)
+				continue
+			}
+			ident := string(out[word:])
+			out = append(append(append(out[:word], '('), ident...), ' ')
+		case c == ')':
+			if len(calls) > 0 {
+				calls = calls[:len(calls)-1]
+			}
+			out = append(out, c)
+		case c == ',' && len(calls) > 0 && calls[len(calls)-1]:
+			out = append(out, ' ')
+			for n+1 < len(a) && a[n+1] == ' ' {
+				n++
+			}
+		default:
+			out = append(out, c)
+		}
+	}
+	return string(out)
+}
+
+// quoteEnd returns the index after the string starting at a[start], or
+// len(a) when it isn't terminated.
+func quoteEnd(a string, start int) int {
+	q := a[start]
+	for n := start + 1; n < len(a); n++ {
+		switch a[n] {
+		case '\\':
+			if q == '"' {
+				n++
+			}
+		case q:
+			return n + 1
+		}
+	}
+	return len(a)
+}
+
+// isIdentByte reports whether c may be part of a template function name.
+func isIdentByte(c byte) bool {
+	return c == '_' ||
+		'a' <= c && c <= 'z' ||
+		'A' <= c && c <= 'Z' ||
+		'0' <= c && c <= '9'
+}
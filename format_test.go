@@ -0,0 +1,111 @@
+package mpris
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestFormat(t *testing.T) {
+	const name = BaseInterface + ".vlc.instance7"
+	m := &recordingMetrics{}
+	addr := testBusAddress(t)
+	addTestPlayer(t, addr, name, map[string]map[string]any{
+		BaseInterface: {"Identity": "VLC media player"},
+		PlayerInterface: {
+			"PlaybackStatus": "Playing",
+			"Volume":         0.5,
+			"Position":       int64(83 * time.Second / time.Microsecond),
+			"Metadata": map[string]dbus.Variant(track(map[string]any{
+				"mpris:trackid": dbus.ObjectPath("/track/1"),
+				"mpris:length":  int64(time.Hour / time.Microsecond),
+				"xesam:title":   "Echoes",
+				"xesam:artist":  []string{"Pink Floyd", "Guest"},
+			})),
+		},
+	})
+	player := New(testConn(t, addr), name, WithMetrics(m))
+
+	tests := []struct {
+		tmpl, want string
+	}{
+		{
+			"{{artist}} - {{title}} [{{duration(position)}}]",
+			"Pink Floyd, Guest - Echoes [1:23]",
+		},
+		{"{{duration length}} {{position | duration}}", "1:00:00 1:23"},
+		{"{{emoji(status)}} {{emoji(volume)}} {{volume}}", "▶️ 🔉 0.5"},
+		{"{{playerName}}: {{uc(status)}} {{lc(title)}}", "vlc: PLAYING echoes"},
+		{`{{default(album, "No album")}} {{default(title, "-")}}`, "No album Echoes"},
+		{`{{trunc(artist, 6)}}|{{trunc(title, 6)}}`, "Pink …|Echoes"},
+		{`{{printf("%s (%d)", title, 1)}}`, "Echoes (1)"},
+		{`{{if eq .Identity "VLC media player"}}{{.Track.TrackID}}{{end}}`, "/track/1"},
+	}
+	for _, tt := range tests {
+		got, err := player.Format(tt.tmpl)
+		if err != nil || got != tt.want {
+			t.Errorf("Format(%q) = %q, %v, want %q", tt.tmpl, got, err, tt.want)
+		}
+	}
+
+	m.mu.Lock()
+	calls := len(m.calls)
+	m.mu.Unlock()
+	if want := 2 * len(tests); calls != want {
+		t.Errorf("Format made %d calls for %d formats, want two GetAll each", calls, len(tests))
+	}
+
+	if _, err := player.Format("{{title"); err == nil {
+		t.Error("Format of an unterminated action succeeded")
+	}
+	if _, err := player.Format("{{emoji(title)}}"); err == nil {
+		t.Error("emoji of a title succeeded")
+	}
+
+	gone := New(testConn(t, addr), BaseInterface+".gone")
+	if _, err := gone.Format("{{title}}"); !errors.Is(err, ErrPlayerGone) {
+		t.Errorf("Format of a gone player = %v, want ErrPlayerGone", err)
+	}
+}
+
+func TestTranslateFormat(t *testing.T) {
+	tests := []struct {
+		tmpl, want string
+	}{
+		{"{{title}}", "{{title}}"},
+		{"a(b) {{duration(position)}}", "a(b) {{(duration position)}}"},
+		{"{{trunc(lc(title), 10)}}", "{{(trunc (lc title) 10)}}"},
+		{`{{default(title, "a(b, c)")}}`, `{{(default title "a(b, c)")}}`},
+		{"{{range $i, $a := (list)}}", "{{range $i, $a := (list)}}"},
+		{"{{if (eq status `x`)}}", "{{if (eq status `x`)}}"},
+		{"{{title", "{{title"},
+	}
+	for _, tt := range tests {
+		if got := translateFormat(tt.tmpl); got != tt.want {
+			t.Errorf("translateFormat(%q) = %q, want %q", tt.tmpl, got, tt.want)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		v    any
+		want string
+	}{
+		{int64(0), "0:00"},
+		{int64(59_999_999), "0:59"},
+		{uint32(61_000_000), "1:01"},
+		{int64(-5_000_000), "0:00"},
+		{3*time.Hour + 2*time.Minute + time.Second, "3:02:01"},
+	}
+	for _, tt := range tests {
+		if got, err := formatDuration(tt.v); err != nil || got != tt.want {
+			t.Errorf("formatDuration(%v) = %q, %v, want %q", tt.v, got, err, tt.want)
+		}
+	}
+	if _, err := formatDuration("soon"); err == nil {
+		t.Error(`formatDuration("soon") succeeded`)
+	}
+}
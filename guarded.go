@@ -0,0 +1,90 @@
+package mpris
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+// ifAllowed calls do when the capability property of the player interface is
+// true, and fails with an error wrapping ErrNotAllowed otherwise. Players
+// disagree on whether a call they don't allow fails or does nothing, which
+// makes the result of the unguarded call unpredictable.
+func (i *Player) ifAllowed(
+	ctx context.Context,
+	capability string,
+	do func(context.Context) error,
+) error {
+	can, err := getPlayerPropertyCast(ctx, i, capability, cast.ToBoolE)
+	if err != nil {
+		return err
+	}
+	if !can {
+		return fmt.Errorf(
+			"%w: %s.%s is false",
+			ErrNotAllowed,
+			PlayerInterface,
+			capability,
+		)
+	}
+	return do(ctx)
+}
+
+// NextIfAllowed is like Next, but fails with an error wrapping ErrNotAllowed
+// without calling Next when CanGoNext is false.
+func (i *Player) NextIfAllowed() error {
+	return i.NextIfAllowedContext(context.Background())
+}
+
+// NextIfAllowedContext is like NextIfAllowed but takes a context.
+func (i *Player) NextIfAllowedContext(ctx context.Context) error {
+	return i.ifAllowed(ctx, "CanGoNext", i.NextContext)
+}
+
+// PreviousIfAllowed is like Previous, but fails with an error wrapping
+// ErrNotAllowed without calling Previous when CanGoPrevious is false.
+func (i *Player) PreviousIfAllowed() error {
+	return i.PreviousIfAllowedContext(context.Background())
+}
+
+// PreviousIfAllowedContext is like PreviousIfAllowed but takes a context.
+func (i *Player) PreviousIfAllowedContext(ctx context.Context) error {
+	return i.ifAllowed(ctx, "CanGoPrevious", i.PreviousContext)
+}
+
+// PlayIfAllowed is like Play, but fails with an error wrapping ErrNotAllowed
+// without calling Play when CanPlay is false.
+func (i *Player) PlayIfAllowed() error {
+	return i.PlayIfAllowedContext(context.Background())
+}
+
+// PlayIfAllowedContext is like PlayIfAllowed but takes a context.
+func (i *Player) PlayIfAllowedContext(ctx context.Context) error {
+	return i.ifAllowed(ctx, "CanPlay", i.PlayContext)
+}
+
+// PauseIfAllowed is like Pause, but fails with an error wrapping ErrNotAllowed
+// without calling Pause when CanPause is false.
+func (i *Player) PauseIfAllowed() error {
+	return i.PauseIfAllowedContext(context.Background())
+}
+
+// PauseIfAllowedContext is like PauseIfAllowed but takes a context.
+func (i *Player) PauseIfAllowedContext(ctx context.Context) error {
+	return i.ifAllowed(ctx, "CanPause", i.PauseContext)
+}
+
+// SeekIfAllowed is like Seek, but fails with an error wrapping ErrNotAllowed
+// without calling Seek when CanSeek is false.
+func (i *Player) SeekIfAllowed(offset time.Duration) error {
+	return i.SeekIfAllowedContext(context.Background(), offset)
+}
+
+// SeekIfAllowedContext is like SeekIfAllowed but takes a context.
+func (i *Player) SeekIfAllowedContext(ctx context.Context, offset time.Duration) error {
+	return i.ifAllowed(ctx, "CanSeek", func(ctx context.Context) error {
+		return i.SeekContext(ctx, offset)
+	})
+}
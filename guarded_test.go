@@ -0,0 +1,87 @@
+package mpris
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// recordingPlayer implements the playback methods of a player, recording the
+// names of the ones called.
+type recordingPlayer struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (p *recordingPlayer) record(method string) *dbus.Error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls = append(p.calls, method)
+	return nil
+}
+
+func (p *recordingPlayer) Next() *dbus.Error     { return p.record("Next") }
+func (p *recordingPlayer) Previous() *dbus.Error { return p.record("Previous") }
+func (p *recordingPlayer) Play() *dbus.Error     { return p.record("Play") }
+func (p *recordingPlayer) Pause() *dbus.Error    { return p.record("Pause") }
+
+// SeekBy implements Seek, which can't be its name because of io.Seeker.
+func (p *recordingPlayer) SeekBy(int64) *dbus.Error { return p.record("Seek") }
+
+// taken returns the recorded calls and forgets them.
+func (p *recordingPlayer) taken() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	calls := p.calls
+	p.calls = nil
+	return calls
+}
+
+func TestIfAllowed(t *testing.T) {
+	server, player := testBus(t)
+	props := exportTestProperties(t, server, map[string]map[string]any{
+		PlayerInterface: {
+			"CanGoNext":     true,
+			"CanGoPrevious": true,
+			"CanPlay":       true,
+			"CanPause":      true,
+			"CanSeek":       true,
+		},
+	})
+	fake := &recordingPlayer{}
+	methods := map[string]string{"SeekBy": "Seek"}
+	err := server.ExportWithMap(fake, methods, DBusObjectPath, PlayerInterface)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	guarded := []struct {
+		method, capability string
+		call               func() error
+	}{
+		{"Next", "CanGoNext", player.NextIfAllowed},
+		{"Previous", "CanGoPrevious", player.PreviousIfAllowed},
+		{"Play", "CanPlay", player.PlayIfAllowed},
+		{"Pause", "CanPause", player.PauseIfAllowed},
+		{"Seek", "CanSeek", func() error { return player.SeekIfAllowed(1) }},
+	}
+	for _, g := range guarded {
+		if err := g.call(); err != nil {
+			t.Errorf("%sIfAllowed() error = %v", g.method, err)
+		}
+		if calls := fake.taken(); !slices.Equal(calls, []string{g.method}) {
+			t.Errorf("%sIfAllowed() called %v", g.method, calls)
+		}
+
+		props.set(PlayerInterface, g.capability, false)
+		if err := g.call(); !errors.Is(err, ErrNotAllowed) {
+			t.Errorf("%sIfAllowed() error = %v, want ErrNotAllowed", g.method, err)
+		}
+		if calls := fake.taken(); len(calls) != 0 {
+			t.Errorf("%sIfAllowed() called %v while not allowed", g.method, calls)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package mpris
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/godbus/dbus/v5/introspect"
+)
+
+// Errors returned early by the accessors of the optional interfaces when the
+// introspection data of the player shows it doesn't export them. They wrap
+// ErrNotSupported.
+var (
+	ErrNoTrackList = fmt.Errorf("%w: no track list", ErrNotSupported)
+	ErrNoPlaylists = fmt.Errorf("%w: no playlists", ErrNotSupported)
+)
+
+// IntrospectMethod is the method returning the introspection data of an
+// object.
+const IntrospectMethod = "org.freedesktop.DBus.Introspectable.Introspect"
+
+// Introspection is what a player exports at its object path, according to
+// its introspection data.
+type Introspection struct {
+	// Node is the parsed introspection data.
+	Node introspect.Node
+}
+
+// iface returns the interface called name, or nil.
+func (n Introspection) iface(name string) *introspect.Interface {
+	for k := range n.Node.Interfaces {
+		if n.Node.Interfaces[k].Name == name {
+			return &n.Node.Interfaces[k]
+		}
+	}
+	return nil
+}
+
+// Interfaces returns the names of the interfaces the player exports, sorted,
+// including the standard D-Bus ones.
+func (n Introspection) Interfaces() []string {
+	names := make([]string, 0, len(n.Node.Interfaces))
+	for _, iface := range n.Node.Interfaces {
+		names = append(names, iface.Name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// HasInterface reports whether the player exports the interface called name.
+func (n Introspection) HasInterface(name string) bool {
+	return n.iface(name) != nil
+}
+
+// Methods returns the names of the methods of iface, sorted. It is empty
+// when the player doesn't export iface.
+func (n Introspection) Methods(iface string) []string {
+	var names []string
+	if i := n.iface(iface); i != nil {
+		for _, method := range i.Methods {
+			names = append(names, method.Name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// SupportsMethod reports whether the player exports method on iface.
+func (n Introspection) SupportsMethod(iface, method string) bool {
+	return slices.Contains(n.Methods(iface), method)
+}
+
+// Properties returns the names of the properties of iface, sorted. It is
+// empty when the player doesn't export iface.
+func (n Introspection) Properties(iface string) []string {
+	var names []string
+	if i := n.iface(iface); i != nil {
+		for _, property := range i.Properties {
+			names = append(names, property.Name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// HasProperty reports whether the player exports property on iface.
+func (n Introspection) HasProperty(iface, property string) bool {
+	return slices.Contains(n.Properties(iface), property)
+}
+
+// Introspect reads the introspection data of the player object and reports
+// which interfaces, methods and properties the player exports, rather than
+// probing them and interpreting the errors. The result is also kept for
+// HasInterface and SupportsMethod and the accessors of the TrackList and
+// Playlists interfaces. Players made with WithOwnerTracking or
+// WithPropertyCache drop it when the application restarts, since the new
+// instance may export other interfaces.
+func (i *Player) Introspect(ctx context.Context) (Introspection, error) {
+	var data string
+	err := i.do(ctx, IntrospectMethod).Store(&data)
+	if err != nil {
+		err = fmt.Errorf(
+			"failed to introspect %s: %w",
+			i.name,
+			translateError(err),
+		)
+		if errors.Is(err, ErrUnknownMethod) || errors.Is(err, ErrNotSupported) {
+			i.mu.Lock()
+			i.noIntrospection = true
+			i.mu.Unlock()
+		}
+		return Introspection{}, err
+	}
+	var n Introspection
+	if err := xml.Unmarshal([]byte(data), &n.Node); err != nil {
+		return Introspection{}, fmt.Errorf(
+			"failed to parse the introspection data of %s: %w",
+			i.name,
+			err,
+		)
+	}
+	i.mu.Lock()
+	i.introspection = &n
+	i.mu.Unlock()
+	return n, nil
+}
+
+// introspected returns the introspection data kept by Introspect, reading it
+// first when there is none. ok is false when the player can't be
+// introspected.
+func (i *Player) introspected(ctx context.Context) (n Introspection, ok bool) {
+	i.mu.Lock()
+	cached, unavailable := i.introspection, i.noIntrospection
+	i.mu.Unlock()
+	switch {
+	case cached != nil:
+		return *cached, true
+	case unavailable:
+		return Introspection{}, false
+	}
+	n, err := i.Introspect(ctx)
+	return n, err == nil
+}
+
+// HasInterface reports whether the player exports the interface called name,
+// using the introspection data kept by Introspect, which it reads first when
+// needed.
+func (i *Player) HasInterface(name string) (bool, error) {
+	return i.HasInterfaceContext(context.Background(), name)
+}
+
+// HasInterfaceContext is like HasInterface but takes a context.
+func (i *Player) HasInterfaceContext(
+	ctx context.Context,
+	name string,
+) (bool, error) {
+	n, err := i.introspectedOrErr(ctx)
+	return n.HasInterface(name), err
+}
+
+// SupportsMethod reports whether the player exports method on iface, like
+// HasInterface.
+func (i *Player) SupportsMethod(iface, method string) (bool, error) {
+	return i.SupportsMethodContext(context.Background(), iface, method)
+}
+
+// SupportsMethodContext is like SupportsMethod but takes a context.
+func (i *Player) SupportsMethodContext(
+	ctx context.Context,
+	iface, method string,
+) (bool, error) {
+	n, err := i.introspectedOrErr(ctx)
+	return n.SupportsMethod(iface, method), err
+}
+
+// introspectedOrErr is like introspected, but returns the error of Introspect.
+func (i *Player) introspectedOrErr(ctx context.Context) (Introspection, error) {
+	i.mu.Lock()
+	cached := i.introspection
+	i.mu.Unlock()
+	if cached != nil {
+		return *cached, nil
+	}
+	return i.Introspect(ctx)
+}
+
+// requireInterface returns missing, naming the player, when its
+// introspection data shows it doesn't export iface. Players that can't be
+// introspected are given the benefit of the doubt.
+func (i *Player) requireInterface(
+	ctx context.Context,
+	iface string,
+	missing error,
+) error {
+	n, ok := i.introspected(ctx)
+	if !ok || n.HasInterface(iface) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s doesn't export %s", missing, i.name, iface)
+}
@@ -0,0 +1,59 @@
+package mpris_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/Nadim147c/go-mpris"
+	"github.com/Nadim147c/go-mpris/mpristest"
+)
+
+func TestIntrospect(t *testing.T) {
+	fake, player := mpristest.NewFakePlayer(t)
+	fake.Imitate(mpristest.Presets["firefox"])
+
+	n, err := player.Introspect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !n.HasInterface(mpris.PlayerInterface) || n.HasInterface(mpris.TrackListInterface) {
+		t.Errorf("Interfaces() = %q", n.Interfaces())
+	}
+	if !n.SupportsMethod(mpris.PlayerInterface, "Seek") || n.SupportsMethod(mpris.PlayerInterface, "Explode") {
+		t.Errorf("Methods() = %q", n.Methods(mpris.PlayerInterface))
+	}
+	properties := n.Properties(mpris.PlayerInterface)
+	if !slices.Contains(properties, "Rate") || slices.Contains(properties, "Shuffle") {
+		t.Errorf("Properties() = %q", properties)
+	}
+
+	if ok, err := player.HasInterface(mpris.BaseInterface); err != nil || !ok {
+		t.Errorf("HasInterface(base) = %v, %v", ok, err)
+	}
+	if ok, err := player.SupportsMethod(mpris.BaseInterface, "Quit"); err != nil || !ok {
+		t.Errorf("SupportsMethod(Quit) = %v, %v", ok, err)
+	}
+}
+
+func TestNoTrackList(t *testing.T) {
+	_, player := mpristest.NewFakePlayer(t)
+
+	_, err := player.CanEditTracks()
+	if !errors.Is(err, mpris.ErrNoTrackList) || !errors.Is(err, mpris.ErrNotSupported) {
+		t.Errorf("CanEditTracks() error = %v, want ErrNoTrackList", err)
+	}
+	if _, err := player.GetPlaylistsProperty("PlaylistCount"); !errors.Is(err, mpris.ErrNoPlaylists) {
+		t.Errorf("GetPlaylistsProperty() error = %v, want ErrNoPlaylists", err)
+	}
+}
+
+func TestTrackList(t *testing.T) {
+	fake, player := mpristest.NewFakePlayer(t)
+	fake.Set(mpris.TrackListInterface, "CanEditTracks", true)
+
+	if ok, err := player.CanEditTracks(); err != nil || !ok {
+		t.Errorf("CanEditTracks() = %v, %v", ok, err)
+	}
+}
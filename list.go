@@ -0,0 +1,140 @@
+package mpris
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// listWorkers bounds how many players ListFiltered, ListPlayers and
+// ListPlayerInfo query at once.
+const listWorkers = 8
+
+// ExcludePlayerctld leaves PlayerctldName out of the list. It is the same as
+// IncludePlayerctld(false).
+func ExcludePlayerctld() ListOption {
+	return IncludePlayerctld(false)
+}
+
+// ExcludeNames leaves out the players whose bus name matches any of globs.
+// The globs use path.Match syntax and are matched, ignoring case, against the
+// base name and the bus name without the org.mpris.MediaPlayer2 prefix, so
+// "chromium*" excludes every Chromium instance.
+func ExcludeNames(globs ...string) ListOption {
+	return func(o *listOptions) {
+		o.exclude = append(o.exclude, globs...)
+	}
+}
+
+// MatchIdentity only keeps the players whose Identity matches glob, ignoring
+// case. The glob uses path.Match syntax. Only ListFiltered and ListPlayers
+// apply it.
+func MatchIdentity(glob string) ListOption {
+	return func(o *listOptions) {
+		o.identity = glob
+	}
+}
+
+// OnlyPlaying only keeps the players that are currently playing. Only
+// ListFiltered and ListPlayers apply it.
+func OnlyPlaying() ListOption {
+	return func(o *listOptions) {
+		o.onlyPlaying = true
+	}
+}
+
+// OnlyResponsive only keeps the players answering Ping, leaving out the names
+// whose owner went away since they were listed or doesn't answer anymore,
+// like the instances Chromium abandons. It costs a round trip per player.
+// Only ListFiltered and ListPlayers apply it.
+func OnlyResponsive() ListOption {
+	return func(o *listOptions) {
+		o.responsive = true
+	}
+}
+
+// ListRetry makes List try listing the bus names up to maxAttempts times
+// while it fails with a transient error, like WithRetry does for the
+// operations of a Player.
+func ListRetry(maxAttempts int, backoff time.Duration) ListOption {
+	return func(o *listOptions) {
+		o.attempts = maxAttempts
+		o.backoff = backoff
+	}
+}
+
+// ListFiltered is like List, but applies every option, including the ones
+// that have to query the players, which are queried concurrently. Players
+// failing such a query are left out. The result is sorted by base name, then
+// instance suffix; see BaseName and InstanceID.
+func ListFiltered(conn *dbus.Conn, opts ...ListOption) ([]string, error) {
+	names, err := List(conn, opts...)
+	if err != nil {
+		return nil, err
+	}
+	names = filterQueried(conn, names, opts)
+	slices.SortFunc(names, func(a, b string) int {
+		aBase, aInstance, _ := splitInstance(a)
+		bBase, bInstance, _ := splitInstance(b)
+		return cmp.Or(
+			strings.Compare(aBase, bBase),
+			strings.Compare(aInstance, bInstance),
+			strings.Compare(a, b),
+		)
+	})
+	return names, nil
+}
+
+// filterQueried returns the names, in order, whose players pass the options
+// of opts that query players. The players are queried concurrently.
+func filterQueried(conn *dbus.Conn, names []string, opts []ListOption) []string {
+	var o listOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.identity == "" && !o.onlyPlaying && !o.responsive {
+		return names
+	}
+
+	keep := make([]bool, len(names))
+	tasks := make([]func(), len(names))
+	for n, name := range names {
+		tasks[n] = func() { keep[n] = o.keep(New(conn, name)) }
+	}
+	parallel(listWorkers, tasks...)
+
+	var kept []string
+	for n, name := range names {
+		if keep[n] {
+			kept = append(kept, name)
+		}
+	}
+	return kept
+}
+
+// keep returns whether player passes the filters of o that query players.
+func (o *listOptions) keep(player *Player) bool {
+	if o.responsive {
+		ctx := context.Background()
+		if unresponsive(ctx, player.Ping(ctx)) {
+			return false
+		}
+	}
+	if o.identity != "" {
+		identity, err := player.GetIdentity()
+		if err != nil || !globMatch(o.identity, identity) {
+			return false
+		}
+	}
+	if o.onlyPlaying {
+		status, err := player.GetPlaybackStatus()
+		if err != nil || status != PlaybackPlaying {
+			return false
+		}
+	}
+	return true
+}
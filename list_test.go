@@ -0,0 +1,122 @@
+package mpris
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestListFiltered(t *testing.T) {
+	addr := testBusAddress(t)
+	conn := testConn(t, addr)
+
+	props := func(identity, status string) map[string]map[string]any {
+		return map[string]map[string]any{
+			BaseInterface:   {"Identity": identity},
+			PlayerInterface: {"PlaybackStatus": status},
+		}
+	}
+	addTestPlayer(t, addr, BaseInterface+".vlc", props("VLC media player", "Paused"))
+	addTestPlayer(t, addr, BaseInterface+".chromium.instance2", props("Chromium", "Playing"))
+	addTestPlayer(t, addr, BaseInterface+".chromium.instance1", props("Chromium", "Stopped"))
+	addTestPlayer(t, addr, BaseInterface+".spotify", props("Spotify", "Playing"))
+	claimName(t, addr, PlayerctldName)
+
+	tests := []struct {
+		name string
+		opts []ListOption
+		want []string
+	}{
+		{"none", nil, []string{
+			".chromium.instance1", ".chromium.instance2", ".playerctld", ".spotify", ".vlc",
+		}},
+		{"ExcludePlayerctld", []ListOption{ExcludePlayerctld()}, []string{
+			".chromium.instance1", ".chromium.instance2", ".spotify", ".vlc",
+		}},
+		{"ExcludeNames", []ListOption{ExcludeNames("CHROMIUM*", "playerctld")}, []string{
+			".spotify", ".vlc",
+		}},
+		{"MatchIdentity", []ListOption{MatchIdentity("vlc*")}, []string{".vlc"}},
+		{"OnlyPlaying", []ListOption{OnlyPlaying()}, []string{
+			".chromium.instance2", ".spotify",
+		}},
+		{"combined", []ListOption{OnlyPlaying(), MatchIdentity("chrom*")}, []string{
+			".chromium.instance2",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ListFiltered(conn, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var want []string
+			for _, name := range tt.want {
+				want = append(want, BaseInterface+name)
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("ListFiltered() = %q, want %q", got, want)
+			}
+		})
+	}
+
+	// List only applies the options looking at names.
+	got, err := List(conn, OnlyPlaying(), ExcludeNames("chromium*"), ExcludePlayerctld())
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(got)
+	want := []string{BaseInterface + ".spotify", BaseInterface + ".vlc"}
+	if !slices.Equal(got, want) {
+		t.Errorf("List() = %q, want %q", got, want)
+	}
+}
+
+// frozenPlayer claims name on a connection that stops answering calls once it
+// is pinged, like a player whose process hangs.
+func frozenPlayer(t *testing.T, addr, name string) {
+	t.Helper()
+	release := make(chan struct{})
+	conn, err := dbus.Connect(addr, dbus.WithIncomingInterceptor(
+		func(msg *dbus.Message) {
+			member, _ := msg.Headers[dbus.FieldMember].Value().(string)
+			if msg.Type == dbus.TypeMethodCall && member == "Ping" {
+				<-release
+			}
+		},
+	))
+	if err != nil {
+		t.Fatalf("Could not connect to test bus: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	t.Cleanup(func() { close(release) })
+	reply, err := conn.RequestName(name, dbus.NameFlagDoNotQueue)
+	if err != nil || reply != dbus.RequestNameReplyPrimaryOwner {
+		t.Fatalf("Could not claim %s: %v", name, err)
+	}
+}
+
+func TestOnlyResponsive(t *testing.T) {
+	addr := testBusAddress(t)
+	conn := testConn(t, addr)
+	claimName(t, addr, BaseInterface+".chromium.instance2")
+	frozenPlayer(t, addr, BaseInterface+".chromium.instance1")
+
+	want := []string{BaseInterface + ".chromium.instance2"}
+	got, err := ListFiltered(conn, OnlyResponsive())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("ListFiltered() = %q, want %q", got, want)
+	}
+
+	players, err := ListPlayers(conn, OnlyResponsive())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := playerNames(players); !slices.Equal(got, want) {
+		t.Errorf("ListPlayers() = %q, want %q", got, want)
+	}
+}
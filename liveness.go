@@ -0,0 +1,191 @@
+package mpris
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// pingTimeout bounds Ping when neither ctx nor the call timeout of the player
+// is shorter, so a health check never waits for the D-Bus default timeout.
+const pingTimeout = time.Second
+
+// Ping checks that the player process is alive and answering calls by calling
+// org.freedesktop.DBus.Peer.Ping on its object, waiting at most pingTimeout.
+// The error wraps ErrPlayerGone when nobody owns the player's name and
+// context.DeadlineExceeded when the process doesn't answer in time.
+func (i *Player) Ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+	ctx, cancelCall := i.callCtx(ctx)
+	defer cancelCall()
+	method := "org.freedesktop.DBus.Peer.Ping"
+	call := i.tr.call(ctx, i.name, i.path, method, i.flags)
+	if call.Err != nil {
+		return fmt.Errorf(
+			"failed to ping %s: %w",
+			i.name,
+			translateError(call.Err),
+		)
+	}
+	return nil
+}
+
+// unresponsive reports whether err, returned by Ping with ctx, means the
+// player's owner is gone or hangs, rather than ctx being canceled.
+func unresponsive(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	return errors.Is(err, ErrPlayerGone) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
+
+// Exists returns whether the player's name is currently owned on the bus. It
+// only asks the bus, which is cheaper than Ping but says nothing about whether
+// the player answers calls.
+func (i *Player) Exists() (bool, error) {
+	return i.ExistsContext(context.Background())
+}
+
+// ExistsContext is like Exists but takes a context.
+func (i *Player) ExistsContext(ctx context.Context) (bool, error) {
+	ctx, cancel := i.callCtx(ctx)
+	defer cancel()
+	var hasOwner bool
+	err := busCall(ctx, i.tr, "org.freedesktop.DBus.NameHasOwner", i.name).
+		Store(&hasOwner)
+	if err != nil {
+		return false, translateError(err)
+	}
+	return hasOwner, nil
+}
+
+// PID returns the ID of the process owning the player's name, for example to
+// focus its window. The error wraps ErrPlayerGone when nobody owns the name,
+// including when the owner exits while it is being looked up twice in a row.
+func (i *Player) PID(ctx context.Context) (uint32, error) {
+	for attempt := 0; ; attempt++ {
+		owner, err := i.getOwnerContext(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get the owner of %s: %w", i.name, err)
+		}
+		pid, err := i.ownerPID(ctx, owner)
+		if err == nil {
+			return pid, nil
+		}
+		// The owner may have vanished between the two calls, e.g. while
+		// the application restarts, so look up the new owner once.
+		if !errors.Is(err, ErrPlayerGone) {
+			return 0, err
+		}
+		if attempt > 0 {
+			return 0, fmt.Errorf("failed to get the PID of %s: %w", i.name, err)
+		}
+	}
+}
+
+// ownerPID returns the ID of the process behind the unique name owner.
+func (i *Player) ownerPID(ctx context.Context, owner string) (uint32, error) {
+	ctx, cancel := i.callCtx(ctx)
+	defer cancel()
+	method := "org.freedesktop.DBus.GetConnectionUnixProcessID"
+	var pid uint32
+	err := busCall(ctx, i.tr, method, owner).Store(&pid)
+	return pid, translateError(err)
+}
+
+// escalateWait is how long QuitWaitEscalate waits for the player to go away
+// after calling the escalation hook.
+const escalateWait = time.Second
+
+// QuitWait asks the player to quit and waits until its name leaves the bus.
+// It fails with an error wrapping ErrNotAllowed when CanQuit is false and
+// with an error wrapping ctx.Err() when the player is still there once ctx is
+// done, so a nil error means the player actually exited.
+func (i *Player) QuitWait(ctx context.Context) error {
+	return i.QuitWaitEscalate(ctx, nil)
+}
+
+// QuitWaitEscalate is like QuitWait, but when the player is still there once
+// ctx is done, escalate is called with the PID of the player, e.g. to kill
+// it, and the player is given another second to go away. The PID is looked up
+// before calling Quit, while the player still answers. escalate isn't called
+// when the PID can't be looked up.
+func (i *Player) QuitWaitEscalate(
+	ctx context.Context,
+	escalate func(pid uint32) error,
+) error {
+	can, err := i.CanQuitContext(ctx)
+	if err != nil {
+		return missingAsNotSupported(err)
+	}
+	if !can {
+		return fmt.Errorf("%w: %s.CanQuit is false", ErrNotAllowed, BaseInterface)
+	}
+
+	gone := make(chan struct{})
+	rule := []dbus.MatchOption{
+		dbus.WithMatchSender(busName),
+		dbus.WithMatchInterface(busName),
+		dbus.WithMatchMember("NameOwnerChanged"),
+		dbus.WithMatchArg(0, i.name),
+		dbus.WithMatchArg(2, ""),
+	}
+	filter := func(sig *dbus.Signal) bool {
+		var name, oldOwner, newOwner string
+		err := dbus.Store(sig.Body, &name, &oldOwner, &newOwner)
+		return err == nil && sig.Name == nameOwnerChangedSignal &&
+			name == i.name && newOwner == ""
+	}
+	var once sync.Once
+	handle := func(context.Context, *dbus.Signal) {
+		once.Do(func() { close(gone) })
+	}
+	// Subscribe before calling Quit, so the player going away isn't missed.
+	sub, err := subscribe(i.tr, [][]dbus.MatchOption{rule}, filter, handle)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	var pid uint32
+	if escalate != nil {
+		pid, _ = i.PID(ctx)
+	}
+	// A player exiting before it replies to Quit is what's asked for.
+	err = i.QuitContext(ctx)
+	if name, _ := dbusErrorName(err); err != nil && name != noReplyError &&
+		!errors.Is(err, ErrPlayerGone) {
+		return err
+	}
+	// The player may have been gone before subscribing.
+	if exists, err := i.ExistsContext(ctx); err == nil && !exists {
+		return nil
+	}
+
+	select {
+	case <-gone:
+		return nil
+	case <-ctx.Done():
+	}
+	quitErr := fmt.Errorf("%s didn't quit: %w", i.name, ctx.Err())
+	if escalate == nil || pid == 0 {
+		return quitErr
+	}
+	if err := escalate(pid); err != nil {
+		return errors.Join(quitErr, err)
+	}
+	timer := time.NewTimer(escalateWait)
+	defer timer.Stop()
+	select {
+	case <-gone:
+		return nil
+	case <-timer.C:
+		return quitErr
+	}
+}
@@ -0,0 +1,135 @@
+package mpris
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestPing(t *testing.T) {
+	_, player := testBus(t)
+	if err := player.Ping(context.Background()); err != nil {
+		t.Errorf("Ping() error = %v", err)
+	}
+
+	gone := New(player.conn, BaseInterface+".gone")
+	if err := gone.Ping(context.Background()); !errors.Is(err, ErrPlayerGone) {
+		t.Errorf("Ping() of a missing player error = %v, want ErrPlayerGone", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := player.Ping(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Ping() with a canceled context error = %v", err)
+	}
+}
+
+func TestExists(t *testing.T) {
+	_, player := testBus(t)
+	for name, want := range map[string]bool{
+		testPlayerName:          true,
+		BaseInterface + ".gone": false,
+	} {
+		ok, err := New(player.conn, name).Exists()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != want {
+			t.Errorf("Exists() of %s = %v, want %v", name, ok, want)
+		}
+	}
+}
+
+func TestPID(t *testing.T) {
+	_, player := testBus(t)
+	pid, err := player.PID(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The test bus runs the player connection in this process.
+	if pid != uint32(os.Getpid()) {
+		t.Errorf("PID() = %d, want %d", pid, os.Getpid())
+	}
+
+	gone := New(player.conn, BaseInterface+".gone")
+	if _, err := gone.PID(context.Background()); !errors.Is(err, ErrPlayerGone) {
+		t.Errorf("PID() of a missing player error = %v, want ErrPlayerGone", err)
+	}
+}
+
+// quittingPlayer implements Quit by releasing its name, unless it is wedged.
+type quittingPlayer struct {
+	conn   *dbus.Conn
+	wedged bool
+}
+
+func (p *quittingPlayer) Quit() *dbus.Error {
+	if !p.wedged {
+		go p.conn.ReleaseName(testPlayerName)
+	}
+	return nil
+}
+
+func exportQuittingPlayer(t *testing.T, server *dbus.Conn, canQuit, wedged bool) {
+	t.Helper()
+	exportTestProperties(t, server, map[string]map[string]any{
+		BaseInterface: {"CanQuit": canQuit},
+	})
+	p := &quittingPlayer{conn: server, wedged: wedged}
+	if err := server.Export(p, DBusObjectPath, BaseInterface); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestQuitWait(t *testing.T) {
+	server, player := testBus(t)
+	exportQuittingPlayer(t, server, true, false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := player.QuitWait(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if exists, _ := player.Exists(); exists {
+		t.Error("player still exists after QuitWait()")
+	}
+}
+
+func TestQuitWaitNotAllowed(t *testing.T) {
+	server, player := testBus(t)
+	exportQuittingPlayer(t, server, false, false)
+
+	if err := player.QuitWait(context.Background()); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("QuitWait() error = %v, want ErrNotAllowed", err)
+	}
+}
+
+func TestQuitWaitEscalate(t *testing.T) {
+	server, player := testBus(t)
+	exportQuittingPlayer(t, server, true, true)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := player.QuitWait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("QuitWait() error = %v, want DeadlineExceeded", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	var escalated uint32
+	err := player.QuitWaitEscalate(ctx, func(pid uint32) error {
+		escalated = pid
+		_, err := server.ReleaseName(testPlayerName)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if escalated != uint32(os.Getpid()) {
+		t.Errorf("escalation hook got PID %d, want %d", escalated, os.Getpid())
+	}
+}
@@ -0,0 +1,118 @@
+package mpris
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// WithLogger makes the player log every method call, property read and
+// write, and every signal it handles to logger at debug level, with the bus
+// name of the player, the interface, the member and, for calls, the latency
+// and error. Property reads and writes are logged with the interface and
+// name of the property. The logger is called without any lock of the
+// package held, so handlers may call back into the Player.
+func WithLogger(logger *slog.Logger) Option {
+	return func(p *Player) {
+		p.logger = logger
+	}
+}
+
+// logging reports whether the player logs at debug level.
+func (i *Player) logging(ctx context.Context) bool {
+	return i.logger != nil && i.logger.Enabled(ctx, slog.LevelDebug)
+}
+
+// splitMember splits a qualified method or signal name into its interface and
+// member.
+func splitMember(name string) (iface, member string) {
+	n := strings.LastIndexByte(name, '.')
+	if n < 0 {
+		return "", name
+	}
+	return name[:n], name[n+1:]
+}
+
+// memberAttrs returns the attributes naming the method of the player called,
+// or the interface and name of the property read or written when method is
+// one of org.freedesktop.DBus.Properties, whose own name goes under "method".
+// Properties calls without arguments, which the player rejects, are named
+// like any other method.
+func (i *Player) memberAttrs(method string, args []any) []slog.Attr {
+	iface, member := splitMember(method)
+	switch method {
+	case GetPropertyMethod, SetPropertyMethod, GetAllPropertiesMethod:
+		if len(args) == 0 {
+			break
+		}
+		attrs := []slog.Attr{
+			slog.String("player", i.name),
+			slog.Any("interface", args[0]),
+			slog.String("method", member),
+		}
+		if len(args) > 1 {
+			attrs = append(attrs, slog.Any("member", args[1]))
+		}
+		return attrs
+	}
+	return []slog.Attr{
+		slog.String("player", i.name),
+		slog.String("interface", iface),
+		slog.String("member", member),
+	}
+}
+
+// logCall logs a call of method, which took latency and failed with err, if
+// not nil. attempts is the number of attempts the retries of the player took,
+// or 0 when unknown, as for queued calls.
+func (i *Player) logCall(
+	ctx context.Context,
+	method string,
+	args []any,
+	latency time.Duration,
+	attempts int,
+	err error,
+) {
+	if !i.logging(ctx) {
+		return
+	}
+	attrs := append(
+		i.memberAttrs(method, args),
+		slog.Duration("latency", latency),
+	)
+	if attempts > 1 {
+		attrs = append(attrs, slog.Int("attempts", attempts))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", translateError(err)))
+	}
+	i.logger.LogAttrs(ctx, slog.LevelDebug, "mpris: call", attrs...)
+}
+
+// logSignal logs sig.
+func (i *Player) logSignal(ctx context.Context, sig *dbus.Signal) {
+	if !i.logging(ctx) {
+		return
+	}
+	iface, member := splitMember(sig.Name)
+	var changed []string
+	if c, ok := parsePropertiesChanged(sig); ok {
+		iface = c.iface
+		changed = slices.Sorted(maps.Keys(c.changed))
+		changed = append(changed, c.invalidated...)
+	}
+	attrs := []slog.Attr{
+		slog.String("player", i.name),
+		slog.String("interface", iface),
+		slog.String("member", member),
+	}
+	if changed != nil {
+		attrs = append(attrs, slog.Any("properties", changed))
+	}
+	i.logger.LogAttrs(ctx, slog.LevelDebug, "mpris: signal", attrs...)
+}
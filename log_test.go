@@ -0,0 +1,162 @@
+package mpris
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// recordHandler is a slog.Handler keeping the attributes of every record,
+// calling onRecord, if set, after each one.
+type recordHandler struct {
+	mu       sync.Mutex
+	records  []map[string]any
+	onRecord func(map[string]any)
+}
+
+func (h *recordHandler) Enabled(context.Context, slog.Level) bool { return true }
+func (h *recordHandler) WithAttrs([]slog.Attr) slog.Handler       { return h }
+func (h *recordHandler) WithGroup(string) slog.Handler            { return h }
+
+func (h *recordHandler) Handle(_ context.Context, r slog.Record) error {
+	attrs := map[string]any{"msg": r.Message}
+	r.Attrs(func(a slog.Attr) bool {
+		attrs[a.Key] = a.Value.Any()
+		return true
+	})
+	h.mu.Lock()
+	h.records = append(h.records, attrs)
+	h.mu.Unlock()
+	if h.onRecord != nil {
+		h.onRecord(attrs)
+	}
+	return nil
+}
+
+// find returns the first record whose attributes include want.
+func (h *recordHandler) find(want map[string]any) (map[string]any, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, r := range h.records {
+		matches := true
+		for k, v := range want {
+			if r[k] != v {
+				matches = false
+			}
+		}
+		if matches {
+			return r, true
+		}
+	}
+	return nil, false
+}
+
+// loggedPlayer returns a Player logging to h, for a player owning
+// testPlayerName and exporting props, and the connection of the latter.
+func loggedPlayer(
+	t *testing.T,
+	h *recordHandler,
+	props map[string]map[string]any,
+	opts ...Option,
+) (*dbus.Conn, *Player) {
+	t.Helper()
+	addr := testBusAddress(t)
+	server := addTestPlayer(t, addr, testPlayerName, props)
+	opts = append(opts, WithLogger(slog.New(h)))
+	return server, New(testConn(t, addr), testPlayerName, opts...)
+}
+
+func TestWithLogger(t *testing.T) {
+	h := &recordHandler{}
+	server, player := loggedPlayer(t, h, map[string]map[string]any{
+		PlayerInterface: {"PlaybackStatus": "Playing"},
+	})
+
+	if _, err := player.GetPlaybackStatus(); err != nil {
+		t.Fatal(err)
+	}
+	r, ok := h.find(map[string]any{
+		"interface": PlayerInterface,
+		"method":    "Get",
+		"member":    "PlaybackStatus",
+	})
+	if !ok || r["player"] != testPlayerName || r["error"] != nil {
+		t.Errorf("records = %v, want a Get of PlaybackStatus", h.records)
+	} else if _, ok := r["latency"]; !ok {
+		t.Errorf("record %v has no latency", r)
+	}
+
+	if err := player.Next(); err == nil {
+		t.Fatal("Next() succeeded on a player without the method")
+	}
+	r, ok = h.find(map[string]any{"interface": PlayerInterface, "member": "Next"})
+	if err, _ := r["error"].(error); !ok || !errors.Is(err, ErrNotSupported) {
+		t.Errorf("records = %v, want a failed Next", h.records)
+	}
+
+	statuses := make(chan PlaybackStatus, 1)
+	sub, err := player.Subscribe(context.Background(), PlaybackStatusChanged(func(s PlaybackStatus) {
+		statuses <- s
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+	emitPropertiesChanged(t, server, PlayerInterface, map[string]dbus.Variant{
+		"PlaybackStatus": dbus.MakeVariant("Paused"),
+	})
+	<-statuses
+	r, ok = h.find(map[string]any{"msg": "mpris: signal", "interface": PlayerInterface})
+	if !ok || r["member"] != "PropertiesChanged" ||
+		!slices.Equal(r["properties"].([]string), []string{"PlaybackStatus"}) {
+		t.Errorf("records = %v, want the PropertiesChanged signal", h.records)
+	}
+}
+
+func TestLoggerPropertiesWithoutArgs(t *testing.T) {
+	h := &recordHandler{}
+	_, player := loggedPlayer(t, h, map[string]map[string]any{
+		PlayerInterface: {"PlaybackStatus": "Playing"},
+	})
+
+	if _, err := player.Call("org.freedesktop.DBus.Properties", "GetAll"); err == nil {
+		t.Fatal("GetAll without an interface succeeded")
+	}
+	r, ok := h.find(map[string]any{
+		"interface": "org.freedesktop.DBus.Properties",
+		"member":    "GetAll",
+	})
+	if !ok || r["error"] == nil {
+		t.Errorf("records = %v, want a failed GetAll", h.records)
+	}
+}
+
+func TestLoggerCallingBack(t *testing.T) {
+	var (
+		player *Player
+		called atomic.Bool
+		done   = make(chan error, 1)
+	)
+	h := &recordHandler{onRecord: func(map[string]any) {
+		if called.CompareAndSwap(false, true) {
+			_, err := player.GetIdentity()
+			done <- err
+		}
+	}}
+	_, player = loggedPlayer(t, h, map[string]map[string]any{
+		BaseInterface: {"Identity": "Logged"},
+	}, WithSerializedCalls(0))
+
+	if _, err := player.GetIdentity(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("GetIdentity() from the logger = %v", err)
+	}
+}
@@ -0,0 +1,145 @@
+package mpris
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// loopCheckInterval is how often LoopSection checks the estimated
+	// position.
+	loopCheckInterval = 50 * time.Millisecond
+	// loopSyncInterval is how often LoopSection reads the actual position,
+	// which catches seeks by players that don't emit Seeked.
+	loopSyncInterval = time.Second
+)
+
+// LoopSection plays the section of the current track between from and to over
+// and over, for practicing music or learning languages. It moves to from and
+// jumps back to it whenever playback passes to, until ctx is done. The
+// position is estimated like TrackPosition does and checked against the
+// player every second, so players that don't emit Seeked are followed too.
+// While the player is paused or stopped, LoopSection waits.
+//
+// LoopSection returns ctx.Err() when ctx is done and an error wrapping
+// ErrTrackChanged when the player moves to another track. It fails with an
+// error wrapping ErrNotAllowed when CanSeek is false and ErrOutOfRange when
+// the section is empty or not within the track.
+func (i *Player) LoopSection(ctx context.Context, from, to time.Duration) error {
+	if from < 0 || to <= from {
+		return fmt.Errorf("%w: section %v to %v", ErrOutOfRange, from, to)
+	}
+	can, err := i.CanSeekContext(ctx)
+	if err != nil {
+		return missingAsNotSupported(err)
+	}
+	if !can {
+		return fmt.Errorf(
+			"%w: %s.CanSeek is false, can't loop",
+			ErrNotAllowed,
+			PlayerInterface,
+		)
+	}
+
+	var playing atomic.Bool
+	changed := make(chan Metadata, 1)
+	// Subscribe before reading the current track, so a change in between
+	// isn't missed.
+	sub, err := i.Subscribe(ctx,
+		PlaybackStatusChanged(func(s PlaybackStatus) {
+			playing.Store(s == PlaybackPlaying)
+		}),
+		MetadataChanged(func(m Metadata) {
+			select {
+			case <-changed:
+			default:
+			}
+			changed <- m
+		}),
+	)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	m, err := i.GetMetadataContext(ctx)
+	if err != nil {
+		return err
+	}
+	trackID, err := m.GetObjectPath("mpris:trackid")
+	if err != nil {
+		return err
+	}
+	if length, err := m.getLength("mpris:length"); err == nil && length > 0 &&
+		to > length {
+		return fmt.Errorf(
+			"%w: section ends at %v, after the track at %v",
+			ErrOutOfRange,
+			to,
+			length,
+		)
+	}
+	status, err := i.GetPlaybackStatusContext(ctx)
+	if err != nil {
+		return err
+	}
+	playing.Store(status == PlaybackPlaying)
+
+	tracker, err := i.TrackPosition(ctx)
+	if err != nil {
+		return err
+	}
+	defer tracker.Close()
+
+	restart := func() error {
+		if err := i.setTrackPosition(ctx, m, trackID, from); err != nil {
+			return err
+		}
+		tracker.seeked(from)
+		return nil
+	}
+	if err := restart(); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(loopCheckInterval)
+	defer ticker.Stop()
+	synced := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-sub.Done():
+			if err := sub.Err(); err != nil {
+				return err
+			}
+			return ctx.Err()
+		case m := <-changed:
+			if id, _ := m.GetObjectPath("mpris:trackid"); id != trackID {
+				return fmt.Errorf("%w: looping %s", ErrTrackChanged, trackID)
+			}
+			continue
+		case <-ticker.C:
+		}
+
+		if !playing.Load() {
+			continue
+		}
+		if tracker.Position() < to && time.Since(synced) < loopSyncInterval {
+			continue
+		}
+		position, err := i.GetPositionContext(ctx)
+		if err != nil {
+			return err
+		}
+		tracker.seeked(position)
+		synced = time.Now()
+		if position >= to {
+			if err := restart(); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package mpris
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// clockPlayer is a player whose position advances with the wall clock while
+// it is playing. It implements SetPosition and counts the calls, and doesn't
+// emit Seeked, like some real players.
+type clockPlayer struct {
+	*testProperties
+
+	mu       sync.Mutex
+	base     time.Duration
+	at       time.Time
+	restarts int
+}
+
+func (p *clockPlayer) Get(iface, name string) (dbus.Variant, *dbus.Error) {
+	if iface != PlayerInterface || name != "Position" {
+		return p.testProperties.Get(iface, name)
+	}
+	status, _ := p.testProperties.Get(PlayerInterface, "PlaybackStatus")
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	position := p.base
+	if status.Value() == string(PlaybackPlaying) {
+		position += time.Since(p.at)
+	}
+	return dbus.MakeVariant(position.Microseconds()), nil
+}
+
+func (p *clockPlayer) SetPosition(_ dbus.ObjectPath, position int64) *dbus.Error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.base = time.Duration(position) * time.Microsecond
+	p.at = time.Now()
+	p.restarts++
+	return nil
+}
+
+func (p *clockPlayer) setPositionCalls() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.restarts
+}
+
+func exportClockPlayer(t *testing.T, server *dbus.Conn, status PlaybackStatus) *clockPlayer {
+	t.Helper()
+	props := exportTestProperties(t, server, map[string]map[string]any{
+		PlayerInterface: {
+			"CanSeek":        true,
+			"PlaybackStatus": string(status),
+			"Rate":           1.0,
+			"Metadata": map[string]dbus.Variant{
+				"mpris:trackid": dbus.MakeVariant(dbus.ObjectPath("/track/1")),
+				"mpris:length":  dbus.MakeVariant(int64(10_000_000)),
+			},
+		},
+	})
+	p := &clockPlayer{testProperties: props, at: time.Now()}
+	if err := server.Export(p, DBusObjectPath, "org.freedesktop.DBus.Properties"); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.Export(p, DBusObjectPath, PlayerInterface); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestLoopSection(t *testing.T) {
+	server, player := testBus(t)
+	fake := exportClockPlayer(t, server, PlaybackPlaying)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 700*time.Millisecond)
+	defer cancel()
+	err := player.LoopSection(ctx, 100*time.Millisecond, 300*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("LoopSection() error = %v, want DeadlineExceeded", err)
+	}
+	// The initial move to the start and at least two jumps back.
+	if n := fake.setPositionCalls(); n < 3 {
+		t.Errorf("SetPosition calls = %d, want at least 3", n)
+	}
+}
+
+func TestLoopSectionPaused(t *testing.T) {
+	server, player := testBus(t)
+	fake := exportClockPlayer(t, server, PlaybackPaused)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	player.LoopSection(ctx, 0, 100*time.Millisecond)
+	if n := fake.setPositionCalls(); n != 1 {
+		t.Errorf("SetPosition calls = %d while paused, want 1", n)
+	}
+}
+
+func TestLoopSectionTrackChanged(t *testing.T) {
+	server, player := testBus(t)
+	fake := exportClockPlayer(t, server, PlaybackPlaying)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- player.LoopSection(context.Background(), 0, 5*time.Second)
+	}()
+	waitFor(t, func() bool { return fake.setPositionCalls() > 0 })
+	fake.set(PlayerInterface, "Metadata", map[string]dbus.Variant{
+		"mpris:trackid": dbus.MakeVariant(dbus.ObjectPath("/track/2")),
+	})
+	if err := receive(t, done); !errors.Is(err, ErrTrackChanged) {
+		t.Errorf("LoopSection() error = %v, want ErrTrackChanged", err)
+	}
+}
+
+func TestLoopSectionInvalid(t *testing.T) {
+	server, player := testBus(t)
+	fake := exportClockPlayer(t, server, PlaybackPlaying)
+	ctx := context.Background()
+
+	for _, section := range [][2]time.Duration{
+		{2 * time.Second, time.Second},
+		{-time.Second, time.Second},
+		{time.Second, 20 * time.Second},
+	} {
+		err := player.LoopSection(ctx, section[0], section[1])
+		if !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("LoopSection(%v, %v) error = %v, want ErrOutOfRange",
+				section[0], section[1], err)
+		}
+	}
+
+	fake.set(PlayerInterface, "CanSeek", false)
+	if err := player.LoopSection(ctx, 0, time.Second); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("LoopSection() error = %v, want ErrNotAllowed", err)
+	}
+}
@@ -0,0 +1,396 @@
+package mpris
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/spf13/cast"
+)
+
+// managerStatusTimeout bounds reading the playback status of a player that
+// just appeared, so a hung player can't stall the Manager.
+const managerStatusTimeout = time.Second
+
+// Manager maintains the live set of players on a connection. It tracks
+// players as they appear and go away and remembers which one changed its
+// playback status last, the way playerctld picks the active player. A
+// Manager is safe for concurrent use.
+type Manager struct {
+	conn    *dbus.Conn
+	sub     *Subscription
+	events  chan Event
+	stop    chan struct{}
+	done    chan struct{}
+	added   *playerQueue
+	removed *playerQueue
+	once    sync.Once
+
+	// pruneEvery is the interval of WithPruning, zero without it; pruned is
+	// closed when its goroutine returns.
+	pruneEvery time.Duration
+	pruned     chan struct{}
+
+	mu      sync.Mutex
+	players map[string]*managedPlayer
+	// seq is bumped on every player activity and stamped on the player.
+	seq uint64
+}
+
+// managedPlayer is a player known to a Manager.
+type managedPlayer struct {
+	player *Player
+	status PlaybackStatus
+	// active is the Manager sequence number of the last activity of the
+	// player: appearing or changing its playback status.
+	active uint64
+}
+
+// ManagerOption customizes a Manager.
+type ManagerOption func(*Manager)
+
+// WithPruning makes the Manager call Prune every interval, so players whose
+// name outlives them without the bus announcing it, or whose process hangs,
+// don't pile up, as happens with the instances Chromium creates per tab. A
+// Manager without it relies on the bus announcing that a name lost its owner,
+// which costs no round trips.
+func WithPruning(interval time.Duration) ManagerOption {
+	return func(m *Manager) {
+		m.pruneEvery = interval
+	}
+}
+
+// NewManager starts tracking the players on conn. Players already on the bus
+// are known when NewManager returns and are also announced on OnAdded. The
+// Manager must be closed with Close.
+func NewManager(conn *dbus.Conn, opts ...ManagerOption) (*Manager, error) {
+	m := &Manager{
+		conn:    conn,
+		events:  make(chan Event),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+		players: map[string]*managedPlayer{},
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	sub, err := WatchPlayers(conn, m.events)
+	if err != nil {
+		return nil, err
+	}
+	m.sub = sub
+
+	// Take in the players already on the bus before returning, so Players
+	// is complete right away.
+	names, err := List(conn)
+	if err != nil {
+		sub.Close()
+		return nil, err
+	}
+	// The queues run a goroutine each, so they are only started once
+	// nothing can fail anymore.
+	m.added = newPlayerQueue()
+	m.removed = newPlayerQueue()
+	for _, name := range names {
+		if isPlayerName(name) {
+			m.add(name)
+		}
+	}
+
+	go m.run()
+	if m.pruneEvery > 0 {
+		m.pruned = make(chan struct{})
+		go m.pruneLoop()
+	}
+	return m, nil
+}
+
+// pruneLoop calls Prune every pruneEvery until the Manager is closed.
+func (m *Manager) pruneLoop() {
+	defer close(m.pruned)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-m.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	ticker := time.NewTicker(m.pruneEvery)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.Prune(ctx)
+		}
+	}
+}
+
+// Prune pings every known player and removes the ones whose name has no
+// owner anymore or that don't answer within a second, announcing them on
+// OnRemoved. The players are pinged concurrently. A pruned player is only
+// added again once its name changes owner.
+func (m *Manager) Prune(ctx context.Context) {
+	players := m.Players()
+	tasks := make([]func(), len(players))
+	for n, player := range players {
+		tasks[n] = func() {
+			if unresponsive(ctx, player.Ping(ctx)) {
+				m.drop(player)
+			}
+		}
+	}
+	parallel(listWorkers, tasks...)
+}
+
+func (m *Manager) run() {
+	defer close(m.done)
+	for {
+		select {
+		case <-m.stop:
+			return
+		case e := <-m.events:
+			m.handle(e)
+		}
+	}
+}
+
+func (m *Manager) handle(e Event) {
+	switch e.Kind {
+	case EventPlayerAdded:
+		m.add(e.Player)
+	case EventPlayerRemoved:
+		m.remove(e.Player)
+	case EventPropertiesChanged:
+		if e.Interface != PlayerInterface {
+			return
+		}
+		v, ok := e.Changed["PlaybackStatus"]
+		if !ok {
+			return
+		}
+		status, err := cast.ToStringE(v.Value())
+		if err != nil {
+			return
+		}
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if p, ok := m.players[e.Player]; ok {
+			m.seq++
+			p.status = PlaybackStatus(status)
+			p.active = m.seq
+		}
+	}
+}
+
+// add starts tracking the player called name, unless it is already known.
+func (m *Manager) add(name string) {
+	m.mu.Lock()
+	_, ok := m.players[name]
+	m.mu.Unlock()
+	if ok {
+		return
+	}
+
+	player := New(m.conn, name)
+	ctx, cancel := context.WithTimeout(context.Background(), managerStatusTimeout)
+	status, err := player.GetPlaybackStatusContext(ctx)
+	cancel()
+	// A name that lost its owner before the multiplexer learned about it is
+	// never announced as removed, so don't take it in at all.
+	if errors.Is(err, ErrPlayerGone) {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.players[name]; ok {
+		return
+	}
+	m.seq++
+	m.players[name] = &managedPlayer{player: player, status: status, active: m.seq}
+	m.added.push(player)
+}
+
+// remove stops tracking the player called name.
+func (m *Manager) remove(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p, ok := m.players[name]
+	if !ok {
+		return
+	}
+	delete(m.players, name)
+	m.removed.push(p.player)
+}
+
+// drop stops tracking player, unless its name was taken over by another
+// player in the meantime.
+func (m *Manager) drop(player *Player) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p, ok := m.players[player.name]
+	if !ok || p.player != player {
+		return
+	}
+	delete(m.players, player.name)
+	m.removed.push(player)
+}
+
+// Players returns the players currently on the bus, sorted by bus name.
+func (m *Manager) Players() []*Player {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	players := make([]*Player, 0, len(m.players))
+	for _, p := range m.players {
+		players = append(players, p.player)
+	}
+	slices.SortFunc(players, func(a, b *Player) int {
+		return strings.Compare(a.name, b.name)
+	})
+	return players
+}
+
+// Player returns the player called name, or nil when it isn't on the bus.
+func (m *Manager) Player(name string) *Player {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if p, ok := m.players[name]; ok {
+		return p.player
+	}
+	return nil
+}
+
+// Active returns the player the user most likely means: the playing player
+// that changed its playback status last, or else the player that appeared
+// or changed its playback status last. It returns nil when there are no
+// players.
+func (m *Manager) Active() *Player {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var active, playing *managedPlayer
+	for _, p := range m.players {
+		if active == nil || p.active > active.active {
+			active = p
+		}
+		if p.status == PlaybackPlaying &&
+			(playing == nil || p.active > playing.active) {
+			playing = p
+		}
+	}
+	switch {
+	case playing != nil:
+		return playing.player
+	case active != nil:
+		return active.player
+	default:
+		return nil
+	}
+}
+
+// OnAdded returns a channel receiving every player that appears on the bus,
+// starting with the ones already there. Players are queued until received, so
+// a slow receiver doesn't hold up the Manager. The channel is closed by Close.
+func (m *Manager) OnAdded() <-chan *Player {
+	return m.added.out
+}
+
+// OnRemoved returns a channel receiving every player that goes away. Players
+// are queued until received, so a slow receiver doesn't hold up the Manager.
+// The channel is closed by Close.
+func (m *Manager) OnRemoved() <-chan *Player {
+	return m.removed.out
+}
+
+// Close stops tracking players, removes the match rules of the Manager from
+// the bus and closes the OnAdded and OnRemoved channels. Calling Close more
+// than once is safe.
+func (m *Manager) Close() error {
+	var err error
+	m.once.Do(func() {
+		err = m.sub.Close()
+		close(m.stop)
+		<-m.done
+		if m.pruned != nil {
+			<-m.pruned
+		}
+		m.added.close()
+		m.removed.close()
+	})
+	return err
+}
+
+// playerQueue delivers players to out in order, queuing as many as needed
+// until they are received.
+type playerQueue struct {
+	out  chan *Player
+	wake chan struct{}
+	stop chan struct{}
+	done chan struct{}
+
+	mu      sync.Mutex
+	pending []*Player
+}
+
+func newPlayerQueue() *playerQueue {
+	q := &playerQueue{
+		out:  make(chan *Player),
+		wake: make(chan struct{}, 1),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go q.run()
+	return q
+}
+
+func (q *playerQueue) push(p *Player) {
+	q.mu.Lock()
+	q.pending = append(q.pending, p)
+	q.mu.Unlock()
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (q *playerQueue) run() {
+	defer close(q.done)
+	for {
+		q.mu.Lock()
+		var next *Player
+		if len(q.pending) > 0 {
+			next = q.pending[0]
+			q.pending = q.pending[1:]
+		}
+		q.mu.Unlock()
+
+		if next != nil {
+			select {
+			case q.out <- next:
+			case <-q.stop:
+				return
+			}
+			continue
+		}
+		select {
+		case <-q.wake:
+		case <-q.stop:
+			return
+		}
+	}
+}
+
+// close stops the delivery and closes out.
+func (q *playerQueue) close() {
+	close(q.stop)
+	<-q.done
+	close(q.out)
+}
@@ -0,0 +1,175 @@
+package mpris
+
+import (
+	"context"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// waitFor polls cond until it holds, failing the test after a while.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for condition")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestManager(t *testing.T) {
+	addr := testBusAddress(t)
+	conn := testConn(t, addr)
+
+	status := func(s string) map[string]map[string]any {
+		return map[string]map[string]any{PlayerInterface: {"PlaybackStatus": s}}
+	}
+	addTestPlayer(t, addr, BaseInterface+".a", status("Paused"))
+
+	m, err := NewManager(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	if got := playerNames(m.Players()); !slices.Equal(got, []string{BaseInterface + ".a"}) {
+		t.Errorf("Players() = %q", got)
+	}
+	if p := receive(t, m.OnAdded()); p.GetName() != BaseInterface+".a" {
+		t.Errorf("added = %s, want a", p.GetName())
+	}
+
+	b := addTestPlayer(t, addr, BaseInterface+".b", status("Stopped"))
+	if p := receive(t, m.OnAdded()); p.GetName() != BaseInterface+".b" {
+		t.Errorf("added = %s, want b", p.GetName())
+	}
+	if m.Player(BaseInterface+".b") == nil {
+		t.Error("Player(b) = nil")
+	}
+	// b appeared last and nothing is playing.
+	if p := m.Active(); p.GetName() != BaseInterface+".b" {
+		t.Errorf("Active() = %s, want b", p.GetName())
+	}
+
+	c := addTestPlayer(t, addr, BaseInterface+".c", status("Stopped"))
+	receive(t, m.OnAdded())
+	emitPropertiesChanged(t, b, PlayerInterface, map[string]dbus.Variant{
+		"PlaybackStatus": dbus.MakeVariant("Playing"),
+	})
+	waitFor(t, func() bool { return m.Active().GetName() == BaseInterface+".b" })
+
+	// c changed last, but b is the one playing.
+	emitPropertiesChanged(t, c, PlayerInterface, map[string]dbus.Variant{
+		"PlaybackStatus": dbus.MakeVariant("Paused"),
+	})
+	expectNothing(t, m.OnAdded())
+	if p := m.Active(); p.GetName() != BaseInterface+".b" {
+		t.Errorf("Active() = %s, want b", p.GetName())
+	}
+
+	if _, err := b.ReleaseName(BaseInterface + ".b"); err != nil {
+		t.Fatal(err)
+	}
+	if p := receive(t, m.OnRemoved()); p.GetName() != BaseInterface+".b" {
+		t.Errorf("removed = %s, want b", p.GetName())
+	}
+	if p := m.Active(); p.GetName() != BaseInterface+".c" {
+		t.Errorf("Active() = %s, want c", p.GetName())
+	}
+
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := <-m.OnAdded(); ok {
+		t.Error("OnAdded() is still open after Close")
+	}
+	if err := m.Close(); err != nil {
+		t.Errorf("second Close() error = %v", err)
+	}
+}
+
+func TestManagerEmpty(t *testing.T) {
+	m, err := NewManager(testConn(t, testBusAddress(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+	if m.Active() != nil {
+		t.Error("Active() without players is not nil")
+	}
+	if len(m.Players()) != 0 {
+		t.Errorf("Players() = %v", m.Players())
+	}
+}
+
+func TestManagerError(t *testing.T) {
+	conn := testConn(t, testBusAddress(t))
+	conn.Close()
+	// Let the goroutines of the connection exit first.
+	time.Sleep(100 * time.Millisecond)
+	before := runtime.NumGoroutine()
+	if _, err := NewManager(conn); err == nil {
+		t.Fatal("NewManager succeeded on a closed connection")
+	}
+	// Nothing NewManager started outlives its failure.
+	waitFor(t, func() bool { return runtime.NumGoroutine() <= before })
+}
+
+func TestManagerPruning(t *testing.T) {
+	addr := testBusAddress(t)
+	conn := testConn(t, addr)
+	claimName(t, addr, BaseInterface+".chromium.instance2")
+	frozenPlayer(t, addr, BaseInterface+".chromium.instance1")
+
+	m, err := NewManager(conn, WithPruning(50*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+	receive(t, m.OnAdded())
+	receive(t, m.OnAdded())
+
+	if p := receive(t, m.OnRemoved()); p.GetName() != BaseInterface+".chromium.instance1" {
+		t.Errorf("removed = %s, want instance1", p.GetName())
+	}
+	want := []string{BaseInterface + ".chromium.instance2"}
+	if got := playerNames(m.Players()); !slices.Equal(got, want) {
+		t.Errorf("Players() = %q, want %q", got, want)
+	}
+}
+
+func TestManagerPrune(t *testing.T) {
+	addr := testBusAddress(t)
+	conn := testConn(t, addr)
+	claimName(t, addr, BaseInterface+".a")
+
+	m, err := NewManager(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+	receive(t, m.OnAdded())
+
+	m.Prune(context.Background())
+	expectNothing(t, m.OnRemoved())
+	if len(m.Players()) != 1 {
+		t.Errorf("Players() = %q after pruning a live player", playerNames(m.Players()))
+	}
+
+	// A player the bus never announced as gone, as if the signal was lost.
+	m.mu.Lock()
+	m.players[BaseInterface+".ghost"] = &managedPlayer{player: New(conn, BaseInterface+".ghost")}
+	m.mu.Unlock()
+	m.Prune(context.Background())
+	if p := receive(t, m.OnRemoved()); p.GetName() != BaseInterface+".ghost" {
+		t.Errorf("removed = %s, want ghost", p.GetName())
+	}
+	if m.Player(BaseInterface+".a") == nil {
+		t.Error("Player(a) = nil after pruning")
+	}
+}
@@ -0,0 +1,82 @@
+package mpris
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// matchRules counts, per transport, the subscriptions using every match rule,
+// so a rule several subscriptions share is added to the bus once and only
+// removed with the last of them. The bus applies every rule to every signal,
+// and a player with a Manager and a few watchers would otherwise register
+// the same rules many times over.
+var (
+	matchRulesMu sync.Mutex
+	matchRules   = map[transport]map[string]int{}
+)
+
+// ruleKey identifies rule. Rules with the same options in a different order
+// get different keys, so they are added twice, which is harmless.
+func ruleKey(rule []dbus.MatchOption) string {
+	return fmt.Sprint(rule)
+}
+
+// addMatch adds rule to the bus of tr, unless a subscription already did.
+//
+// matchRulesMu is held across the bus calls, so a rule being removed isn't
+// counted as added by a subscription starting meanwhile.
+func addMatch(tr transport, rule []dbus.MatchOption) error {
+	matchRulesMu.Lock()
+	defer matchRulesMu.Unlock()
+
+	key := ruleKey(rule)
+	counts := matchRules[tr]
+	if counts[key] > 0 {
+		counts[key]++
+		return nil
+	}
+	if err := tr.addMatchSignal(rule...); err != nil {
+		return err
+	}
+	if counts == nil {
+		counts = map[string]int{}
+		matchRules[tr] = counts
+	}
+	counts[key] = 1
+	return nil
+}
+
+// removeMatch releases rule, removing it from the bus of tr once no
+// subscription uses it anymore. Nothing is sent to a disconnected bus.
+func removeMatch(tr transport, rule []dbus.MatchOption) error {
+	matchRulesMu.Lock()
+	defer matchRulesMu.Unlock()
+
+	key := ruleKey(rule)
+	counts := matchRules[tr]
+	if counts[key] == 0 {
+		return nil
+	}
+	counts[key]--
+	if counts[key] > 0 {
+		return nil
+	}
+	delete(counts, key)
+	if len(counts) == 0 {
+		delete(matchRules, tr)
+	}
+	if !tr.connected() {
+		return nil
+	}
+	return tr.removeMatchSignal(rule...)
+}
+
+// forgetMatches drops the rules counted for tr, whose connection was closed
+// along with every rule on it.
+func forgetMatches(tr transport) {
+	matchRulesMu.Lock()
+	defer matchRulesMu.Unlock()
+	delete(matchRules, tr)
+}
@@ -0,0 +1,205 @@
+package mpris
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// ruleTransport is a transport that only tracks the match rules on its bus.
+// Adding the rule with the member failMember fails.
+type ruleTransport struct {
+	failMember string
+
+	mu      sync.Mutex
+	rules   map[string]int
+	adds    int
+	removes int
+	closed  bool
+}
+
+func newRuleTransport() *ruleTransport {
+	return &ruleTransport{rules: map[string]int{}}
+}
+
+func (r *ruleTransport) call(
+	context.Context,
+	string,
+	dbus.ObjectPath,
+	string,
+	dbus.Flags,
+	...any,
+) *dbus.Call {
+	return &dbus.Call{Err: errors.New("no calls on a rule transport")}
+}
+
+func (r *ruleTransport) addMatchSignal(rule ...dbus.MatchOption) error {
+	if r.failMember != "" &&
+		ruleKey(rule) == ruleKey(memberRule(r.failMember)) {
+		return errors.New("rule rejected")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rules[ruleKey(rule)]++
+	r.adds++
+	return nil
+}
+
+func (r *ruleTransport) removeMatchSignal(rule ...dbus.MatchOption) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := ruleKey(rule)
+	if r.rules[key] == 0 {
+		return errors.New("rule not found")
+	}
+	r.rules[key]--
+	if r.rules[key] == 0 {
+		delete(r.rules, key)
+	}
+	r.removes++
+	return nil
+}
+
+func (r *ruleTransport) signal(chan<- *dbus.Signal)       {}
+func (r *ruleTransport) removeSignal(chan<- *dbus.Signal) {}
+
+func (r *ruleTransport) connected() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return !r.closed
+}
+
+// onBus returns how many times the rule matching member is on the bus.
+func (r *ruleTransport) onBus(member string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rules[ruleKey(memberRule(member))]
+}
+
+// memberRule returns a rule matching the signals named member.
+func memberRule(member string) []dbus.MatchOption {
+	return []dbus.MatchOption{dbus.WithMatchMember(member)}
+}
+
+// subscribeMembers subscribes to the signals named members on tr.
+func subscribeMembers(
+	t *testing.T,
+	tr transport,
+	members ...string,
+) (*Subscription, error) {
+	t.Helper()
+	rules := make([][]dbus.MatchOption, len(members))
+	for n, member := range members {
+		rules[n] = memberRule(member)
+	}
+	return subscribe(tr, rules, nil, func(context.Context, *dbus.Signal) {})
+}
+
+// mustSubscribeMembers is like subscribeMembers but fails t on errors.
+func mustSubscribeMembers(
+	t *testing.T,
+	tr transport,
+	members ...string,
+) *Subscription {
+	t.Helper()
+	sub, err := subscribeMembers(t, tr, members...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sub.Close() })
+	return sub
+}
+
+// expectUnregistered fails t unless the registry holds no rules for tr.
+func expectUnregistered(t *testing.T, tr transport) {
+	t.Helper()
+	matchRulesMu.Lock()
+	defer matchRulesMu.Unlock()
+	if counts, ok := matchRules[tr]; ok {
+		t.Errorf("registry still holds %v", counts)
+	}
+}
+
+func TestMatchRulesShared(t *testing.T) {
+	tr := newRuleTransport()
+	first := mustSubscribeMembers(t, tr, "A")
+	second := mustSubscribeMembers(t, tr, "A", "B")
+	if tr.onBus("A") != 1 || tr.onBus("B") != 1 || tr.adds != 2 {
+		t.Fatalf("rules on the bus = %v, want A and B once", tr.rules)
+	}
+
+	if err := second.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if tr.onBus("A") != 1 || tr.onBus("B") != 0 {
+		t.Errorf("after closing A+B: rules on the bus = %v, want A", tr.rules)
+	}
+	if err := first.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(tr.rules) != 0 || tr.removes != 2 {
+		t.Errorf("after closing all: rules on the bus = %v", tr.rules)
+	}
+	expectUnregistered(t, tr)
+
+	// Closing twice mustn't release the rules of others.
+	third := mustSubscribeMembers(t, tr, "A")
+	first.Close()
+	if tr.onBus("A") != 1 {
+		t.Errorf("A on the bus %d times, want once", tr.onBus("A"))
+	}
+	third.Close()
+	expectUnregistered(t, tr)
+}
+
+func TestMatchRulesFailedAdd(t *testing.T) {
+	tr := newRuleTransport()
+	tr.failMember = "Fail"
+	held := mustSubscribeMembers(t, tr, "A")
+
+	if _, err := subscribeMembers(t, tr, "A", "B", "Fail"); err == nil {
+		t.Fatal("subscribing with a rejected rule succeeded")
+	}
+	if tr.onBus("A") != 1 || tr.onBus("B") != 0 {
+		t.Errorf("rules on the bus = %v, want A", tr.rules)
+	}
+
+	held.Close()
+	if len(tr.rules) != 0 {
+		t.Errorf("rules on the bus = %v, want none", tr.rules)
+	}
+	expectUnregistered(t, tr)
+}
+
+func TestMatchRulesDisconnected(t *testing.T) {
+	tr := newRuleTransport()
+	first := mustSubscribeMembers(t, tr, "A")
+	second := mustSubscribeMembers(t, tr, "A")
+	tr.mu.Lock()
+	tr.closed = true
+	tr.mu.Unlock()
+
+	first.Close()
+	second.Close()
+	if tr.removes != 0 {
+		t.Errorf("%d rules removed from a closed bus", tr.removes)
+	}
+	expectUnregistered(t, tr)
+}
+
+func TestMatchRulesConnectionClosed(t *testing.T) {
+	_, player := testBus(t)
+	sub, err := player.WatchMetadataChanged(make(chan Metadata))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	tr := connTransport{player.conn}
+	player.conn.Close()
+	receive(t, sub.Done())
+	expectUnregistered(t, tr)
+}
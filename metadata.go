@@ -0,0 +1,488 @@
+package mpris
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/spf13/cast"
+)
+
+// Metadata represents the metadata of the current track.
+//
+// Every Metadata handed out by this package is a private copy: GetMetadata and
+// each metadata listener build their own map, so one consumer mutating its
+// value never affects another. The methods on Metadata never modify the
+// receiver, which makes a Metadata safe to read from multiple goroutines as
+// long as nobody writes to it at the same time.
+type Metadata map[string]dbus.Variant
+
+// ErrMetadataKeyMissing is returned when a metadata key is missing or nil.
+var ErrMetadataKeyMissing = errors.New("mpris: metadata key missing")
+
+// Get returns the value for the given metadata key. The error wraps
+// ErrMetadataKeyMissing when the key is missing or nil.
+func (m Metadata) Get(key string) (any, error) {
+	v, ok := m[key]
+	if !ok || v.Value() == nil {
+		return v, fmt.Errorf(
+			"%s.Metadata missing or nil for key %q: %w",
+			PlayerInterface,
+			key,
+			ErrMetadataKeyMissing,
+		)
+	}
+	return v.Value(), nil
+}
+
+// metadataCast returns the value of key cast using the provided caster
+// function.
+func metadataCast[T any](m Metadata, key string, caster func(any) (T, error)) (T, error) {
+	var v T
+	val, err := m.Get(key)
+	if err != nil {
+		return v, err
+	}
+	v, err = caster(val)
+	if err != nil {
+		return v, castError(key, val, err)
+	}
+	return v, nil
+}
+
+// castError wraps the failure to cast val, the value of the metadata key.
+func castError(key string, val any, err error) error {
+	return fmt.Errorf(
+		"%s.Metadata: failed to cast value (%v) of %q: %w",
+		PlayerInterface,
+		val,
+		key,
+		err,
+	)
+}
+
+// GetString returns the value of key as a string.
+func (m Metadata) GetString(key string) (string, error) {
+	return metadataCast(m, key, cast.ToStringE)
+}
+
+// GetStringSlice returns the value of key as a list of strings. A single
+// string is accepted as a list of one. See toStrings.
+func (m Metadata) GetStringSlice(key string) ([]string, error) {
+	return metadataCast(m, key, toStrings)
+}
+
+// GetInt64 returns the value of key as an int64. See toInt64 for the accepted
+// values.
+func (m Metadata) GetInt64(key string) (int64, error) {
+	return metadataCast(m, key, toInt64)
+}
+
+// getInt is GetInt64 for keys decoded into an int.
+func (m Metadata) getInt(key string) (int, error) {
+	return metadataCast(m, key, toInt)
+}
+
+// GetFloat64 returns the value of key as a float64.
+func (m Metadata) GetFloat64(key string) (float64, error) {
+	return metadataCast(m, key, cast.ToFloat64E)
+}
+
+// GetBool returns the value of key as a bool.
+func (m Metadata) GetBool(key string) (bool, error) {
+	return metadataCast(m, key, cast.ToBoolE)
+}
+
+// GetDuration returns the value of key, which is in microseconds, as a
+// time.Duration.
+func (m Metadata) GetDuration(key string) (time.Duration, error) {
+	return metadataCast(m, key, microsecondsToDuration)
+}
+
+// getLength is GetDuration for lengths, which are never negative.
+func (m Metadata) getLength(key string) (time.Duration, error) {
+	return metadataCast(m, key, toLength)
+}
+
+// GetObjectPath returns the value of key as a dbus.ObjectPath.
+func (m Metadata) GetObjectPath(key string) (dbus.ObjectPath, error) {
+	return metadataCast(m, key, toObjectPath)
+}
+
+// getLyrics is GetString for lyrics, which are also accepted as a list of
+// lines.
+func (m Metadata) getLyrics(key string) (string, error) {
+	return metadataCast(m, key, toLyrics)
+}
+
+// GetRating returns the value of key as a rating in the range 0.0 to 1.0. See
+// toRating for how integer star ratings are handled.
+func (m Metadata) GetRating(key string) (float64, error) {
+	return metadataCast(m, key, toRating)
+}
+
+// GetTime returns the value of key as a time.Time. See toTime for the
+// accepted formats.
+func (m Metadata) GetTime(key string) (time.Time, error) {
+	return metadataCast(m, key, toTime)
+}
+
+// Keys returns the keys of m, sorted.
+func (m Metadata) Keys() []string {
+	return slices.Sorted(maps.Keys(m))
+}
+
+// Has returns whether m holds a non-nil value for key.
+func (m Metadata) Has(key string) bool {
+	v, ok := m[key]
+	return ok && v.Value() != nil
+}
+
+// Namespace returns a copy of the entries of m whose key starts with prefix,
+// such as "xesam:" or a player specific prefix. The values are deep copies, see
+// Clone.
+func (m Metadata) Namespace(prefix string) Metadata {
+	ns := Metadata{}
+	for k, v := range m {
+		if strings.HasPrefix(k, prefix) {
+			ns[k] = cloneVariant(v)
+		}
+	}
+	return ns
+}
+
+// Clone returns a deep copy of m. Slices and maps nested inside the variant
+// values are copied as well, so the result shares no memory with m.
+func (m Metadata) Clone() Metadata {
+	if m == nil {
+		return nil
+	}
+	c := make(Metadata, len(m))
+	for k, v := range m {
+		c[k] = cloneVariant(v)
+	}
+	return c
+}
+
+// cloneVariant returns a copy of v whose value shares no memory with v.
+func cloneVariant(v dbus.Variant) dbus.Variant {
+	if v.Value() == nil {
+		return v
+	}
+	return dbus.MakeVariantWithSignature(cloneValue(v.Value()), v.Signature())
+}
+
+// cloneValue deep copies the container types godbus produces when decoding a
+// message. Any other value is returned as is, since it is immutable.
+func cloneValue(v any) any {
+	switch v := v.(type) {
+	case dbus.Variant:
+		return cloneVariant(v)
+	case []string:
+		return append([]string(nil), v...)
+	case []byte:
+		return append([]byte(nil), v...)
+	case []int32:
+		return append([]int32(nil), v...)
+	case []int64:
+		return append([]int64(nil), v...)
+	case []uint32:
+		return append([]uint32(nil), v...)
+	case []uint64:
+		return append([]uint64(nil), v...)
+	case []float64:
+		return append([]float64(nil), v...)
+	case []dbus.ObjectPath:
+		return append([]dbus.ObjectPath(nil), v...)
+	case []dbus.Variant:
+		c := make([]dbus.Variant, len(v))
+		for i, e := range v {
+			c[i] = cloneVariant(e)
+		}
+		return c
+	case []any:
+		c := make([]any, len(v))
+		for i, e := range v {
+			c[i] = cloneValue(e)
+		}
+		return c
+	case map[string]dbus.Variant:
+		c := make(map[string]dbus.Variant, len(v))
+		for k, e := range v {
+			c[k] = cloneVariant(e)
+		}
+		return c
+	case map[string]any:
+		c := make(map[string]any, len(v))
+		for k, e := range v {
+			c[k] = cloneValue(e)
+		}
+		return c
+	case map[string]string:
+		return maps.Clone(v)
+	default:
+		return v
+	}
+}
+
+// toMetadata converts a decoded Metadata property value into Metadata. The
+// returned map is the one godbus decoded, callers that share it must Clone it.
+func toMetadata(a any) (Metadata, error) {
+	v, ok := a.(map[string]dbus.Variant)
+	if !ok {
+		return Metadata{}, fmt.Errorf(
+			"failed to cast %s.Metadata value (%v) to map[string]dbus.Variant",
+			PlayerInterface,
+			a,
+		)
+	}
+	return Metadata(v), nil
+}
+
+// TrackMetadata is the decoded form of the well-known Metadata keys.
+type TrackMetadata struct {
+	TrackID        dbus.ObjectPath
+	Length         time.Duration
+	ArtURL         string
+	Title          string
+	Album          string
+	AlbumArtists   []string
+	Artists        []string
+	URL            string
+	TrackNumber    int
+	DiscNumber     int
+	Genres         []string
+	Composers      []string
+	Lyricists      []string
+	Comments       []string
+	UserRating     float64
+	AutoRating     float64
+	AudioBPM       int
+	ContentCreated time.Time
+	FirstUsed      time.Time
+	LastUsed       time.Time
+	UseCount       int
+	// Raw holds the complete metadata, including keys without a field.
+	Raw Metadata
+}
+
+// toInt64 is the numeric coercion shared by every integer value. It accepts
+// any integer width, floats without a fractional part and decimal strings,
+// since players don't agree on the integer types. Values that don't fit in an
+// int64 are rejected instead of wrapping around.
+func toInt64(a any) (int64, error) {
+	switch v := a.(type) {
+	case int:
+		return int64(v), nil
+	case int8:
+		return int64(v), nil
+	case int16:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case uint8:
+		return int64(v), nil
+	case uint16:
+		return int64(v), nil
+	case uint32:
+		return int64(v), nil
+	case uint:
+		return toInt64(uint64(v))
+	case uint64:
+		if v > math.MaxInt64 {
+			return 0, fmt.Errorf("%d overflows int64", v)
+		}
+		return int64(v), nil
+	case float32:
+		return toInt64(float64(v))
+	case float64:
+		if v != math.Trunc(v) || v < math.MinInt64 || v >= math.MaxInt64 {
+			return 0, fmt.Errorf("unable to cast %v to an integer", v)
+		}
+		return int64(v), nil
+	case string:
+		return strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+	default:
+		return 0, fmt.Errorf("unable to cast %#v of type %T to int64", a, a)
+	}
+}
+
+// toInt is toInt64 for fields of type int.
+func toInt(a any) (int, error) {
+	v, err := toInt64(a)
+	return int(v), err
+}
+
+// toObjectPath casts a string or object path value to dbus.ObjectPath.
+func toObjectPath(a any) (dbus.ObjectPath, error) {
+	s, err := cast.ToStringE(a)
+	return dbus.ObjectPath(s), err
+}
+
+// toStrings casts a string list value. Besides the spec's array of strings, a
+// plain string and arrays of variants are accepted, since players get this
+// wrong regularly.
+func toStrings(a any) ([]string, error) {
+	switch v := a.(type) {
+	case string:
+		return []string{v}, nil
+	case []string:
+		return append([]string(nil), v...), nil
+	case []dbus.Variant:
+		list := make([]string, 0, len(v))
+		for _, e := range v {
+			s, err := cast.ToStringE(e.Value())
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, s)
+		}
+		return list, nil
+	case []any:
+		list := make([]string, 0, len(v))
+		for _, e := range v {
+			if ev, ok := e.(dbus.Variant); ok {
+				e = ev.Value()
+			}
+			s, err := cast.ToStringE(e)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, s)
+		}
+		return list, nil
+	default:
+		return nil, fmt.Errorf("unable to cast %#v of type %T to []string", a, a)
+	}
+}
+
+// toLyrics casts a lyrics value. At least one player sends the lines as an
+// array of strings, which are joined with newlines.
+func toLyrics(a any) (string, error) {
+	if s, ok := a.(string); ok {
+		return s, nil
+	}
+	lines, err := toStrings(a)
+	return strings.Join(lines, "\n"), err
+}
+
+// maxStars is the scale misbehaving players use for integer ratings.
+const maxStars = 5
+
+// toRating casts a rating value to the 0.0 to 1.0 range of the spec. Some
+// players send integer star ratings from 0 to 5 instead; an integer greater
+// than 1 is therefore taken as a number of stars and divided by 5, capped at
+// 1.0. The integers 0 and 1 can't be told apart from a valid rating and are
+// used as they are.
+func toRating(a any) (float64, error) {
+	switch a.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		stars, err := toInt64(a)
+		if err != nil {
+			return 0, err
+		}
+		if stars > 1 {
+			return min(float64(stars)/maxStars, 1), nil
+		}
+		return float64(stars), nil
+	default:
+		return cast.ToFloat64E(a)
+	}
+}
+
+// timeLayouts are the date formats accepted by toTime, in order.
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	time.DateOnly,
+}
+
+// toTime parses a date value. The spec asks for ISO-8601 strings, but some
+// players send unix timestamps, so RFC3339, RFC3339 without a zone, date-only
+// (2006-01-02) and integer seconds, either as a number or a string, are all
+// accepted. The error includes the raw value when none of them match.
+func toTime(a any) (time.Time, error) {
+	switch a.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		sec, err := toInt64(a)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Unix(sec, 0), nil
+	}
+
+	s, err := cast.ToStringE(a)
+	if err != nil {
+		return time.Time{}, err
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	if sec, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(sec, 0), nil
+	}
+	return time.Time{}, fmt.Errorf("unable to parse %q as a date", s)
+}
+
+// decodeKey casts the value of key into dst when it is present. Missing keys
+// leave dst untouched; a failed cast is returned as an error naming the key.
+func decodeKey[T any](m Metadata, key string, dst *T, caster func(any) (T, error)) error {
+	v, ok := m[key]
+	if !ok || v.Value() == nil {
+		return nil
+	}
+	val, err := caster(v.Value())
+	if err != nil {
+		return castError(key, v.Value(), err)
+	}
+	*dst = val
+	return nil
+}
+
+// Decode decodes the well-known keys of m into a TrackMetadata. Decoding is
+// lenient: missing keys leave zero values and numeric keys accept any integer
+// width. Values that can't be cast leave their field zero too and are reported
+// together in the returned error, alongside an otherwise complete result.
+func (m Metadata) Decode() (TrackMetadata, error) {
+	return m.decode(m.Clone())
+}
+
+// decode is like Decode but keeps raw as TrackMetadata.Raw. Callers owning
+// m pass m itself, saving a copy of the map.
+func (m Metadata) decode(raw Metadata) (TrackMetadata, error) {
+	t := TrackMetadata{Raw: raw}
+	err := errors.Join(
+		decodeKey(m, "mpris:trackid", &t.TrackID, toObjectPath),
+		decodeKey(m, "mpris:length", &t.Length, toLength),
+		decodeKey(m, "mpris:artUrl", &t.ArtURL, cast.ToStringE),
+		decodeKey(m, "xesam:title", &t.Title, cast.ToStringE),
+		decodeKey(m, "xesam:album", &t.Album, cast.ToStringE),
+		decodeKey(m, "xesam:albumArtist", &t.AlbumArtists, toStrings),
+		decodeKey(m, "xesam:artist", &t.Artists, toStrings),
+		decodeKey(m, "xesam:url", &t.URL, cast.ToStringE),
+		decodeKey(m, "xesam:trackNumber", &t.TrackNumber, toInt),
+		decodeKey(m, "xesam:discNumber", &t.DiscNumber, toInt),
+		decodeKey(m, "xesam:genre", &t.Genres, toStrings),
+		decodeKey(m, "xesam:composer", &t.Composers, toStrings),
+		decodeKey(m, "xesam:lyricist", &t.Lyricists, toStrings),
+		decodeKey(m, "xesam:comment", &t.Comments, toStrings),
+		decodeKey(m, "xesam:userRating", &t.UserRating, toRating),
+		decodeKey(m, "xesam:autoRating", &t.AutoRating, toRating),
+		decodeKey(m, "xesam:audioBPM", &t.AudioBPM, toInt),
+		decodeKey(m, "xesam:contentCreated", &t.ContentCreated, toTime),
+		decodeKey(m, "xesam:firstUsed", &t.FirstUsed, toTime),
+		decodeKey(m, "xesam:lastUsed", &t.LastUsed, toTime),
+		decodeKey(m, "xesam:useCount", &t.UseCount, toInt),
+	)
+	return t, err
+}
@@ -0,0 +1,68 @@
+package mpris
+
+import (
+	"reflect"
+	"slices"
+)
+
+// MetadataDiff lists the keys that differ between two Metadata values. Every
+// list is sorted.
+type MetadataDiff struct {
+	// Added holds the keys only present in the new metadata.
+	Added []string
+	// Removed holds the keys only present in the old metadata.
+	Removed []string
+	// Changed holds the keys present in both whose values differ.
+	Changed []string
+}
+
+// Empty returns whether d holds no differences.
+func (d MetadataDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// valueEqual returns whether the variants a and b hold the same value. Values
+// are compared unwrapped, so nesting variants or sending a list of variants
+// instead of a typed list doesn't make a difference; see plainValue.
+func valueEqual(a, b any) bool {
+	return reflect.DeepEqual(plainValue(a), plainValue(b))
+}
+
+// Equal returns whether m and other hold the same keys with the same values.
+// Values are compared after unwrapping variants, not as variant structs.
+func (m Metadata) Equal(other Metadata) bool {
+	if len(m) != len(other) {
+		return false
+	}
+	for k, v := range m {
+		o, ok := other[k]
+		if !ok || !valueEqual(v, o) {
+			return false
+		}
+	}
+	return true
+}
+
+// Diff returns the keys added, removed and changed going from m to other.
+// Values are compared like in Equal.
+func (m Metadata) Diff(other Metadata) MetadataDiff {
+	var d MetadataDiff
+	for k, v := range m {
+		o, ok := other[k]
+		switch {
+		case !ok:
+			d.Removed = append(d.Removed, k)
+		case !valueEqual(v, o):
+			d.Changed = append(d.Changed, k)
+		}
+	}
+	for k := range other {
+		if _, ok := m[k]; !ok {
+			d.Added = append(d.Added, k)
+		}
+	}
+	slices.Sort(d.Added)
+	slices.Sort(d.Removed)
+	slices.Sort(d.Changed)
+	return d
+}
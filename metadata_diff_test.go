@@ -0,0 +1,110 @@
+package mpris
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestMetadataEqual(t *testing.T) {
+	base := Metadata{
+		"xesam:title": dbus.MakeVariant("Title"),
+		"xesam:artist": dbus.MakeVariant([]dbus.Variant{
+			dbus.MakeVariant("A"),
+			dbus.MakeVariant(dbus.MakeVariant("B")),
+		}),
+		"vendor:extra": dbus.MakeVariant(map[string]dbus.Variant{
+			"nested": dbus.MakeVariant([]string{"x"}),
+		}),
+	}
+
+	tests := []struct {
+		name  string
+		other Metadata
+		want  bool
+	}{
+		{"clone", base.Clone(), true},
+		{"typed list", Metadata{
+			"xesam:title":  dbus.MakeVariant("Title"),
+			"xesam:artist": dbus.MakeVariant([]string{"A", "B"}),
+			"vendor:extra": dbus.MakeVariant(map[string]any{"nested": []any{"x"}}),
+		}, true},
+		{"wrapped title", Metadata{
+			"xesam:title":  dbus.MakeVariant(dbus.MakeVariant("Title")),
+			"xesam:artist": dbus.MakeVariant([]string{"A", "B"}),
+			"vendor:extra": base["vendor:extra"],
+		}, true},
+		{"artist order", Metadata{
+			"xesam:title":  dbus.MakeVariant("Title"),
+			"xesam:artist": dbus.MakeVariant([]string{"B", "A"}),
+			"vendor:extra": base["vendor:extra"],
+		}, false},
+		{"nested change", Metadata{
+			"xesam:title":  dbus.MakeVariant("Title"),
+			"xesam:artist": base["xesam:artist"],
+			"vendor:extra": dbus.MakeVariant(map[string]dbus.Variant{
+				"nested": dbus.MakeVariant([]string{"y"}),
+			}),
+		}, false},
+		{"missing key", Metadata{
+			"xesam:title":  dbus.MakeVariant("Title"),
+			"xesam:artist": base["xesam:artist"],
+		}, false},
+		{"other key", Metadata{
+			"xesam:title":  dbus.MakeVariant("Title"),
+			"xesam:artist": base["xesam:artist"],
+			"xesam:album":  base["vendor:extra"],
+		}, false},
+		{"nil", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := base.Equal(tt.other); got != tt.want {
+				t.Errorf("Equal() = %v, want %v", got, tt.want)
+			}
+			if got := tt.other.Equal(base); got != tt.want {
+				t.Errorf("reversed Equal() = %v, want %v", got, tt.want)
+			}
+			if got := base.Diff(tt.other).Empty(); got != tt.want {
+				t.Errorf("Diff().Empty() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if !Metadata(nil).Equal(Metadata{}) {
+		t.Error("nil and empty metadata are not equal")
+	}
+}
+
+func TestMetadataDiff(t *testing.T) {
+	old := track(map[string]any{
+		"mpris:trackid": dbus.ObjectPath("/t/1"),
+		"mpris:artUrl":  "file:///a.png",
+		"xesam:title":   "Title",
+		"xesam:genre":   []string{"Rock"},
+	})
+	updated := track(map[string]any{
+		"mpris:trackid": dbus.ObjectPath("/t/1"),
+		"xesam:title":   "Title (Remastered)",
+		"xesam:genre":   []dbus.Variant{dbus.MakeVariant("Rock")},
+		"xesam:album":   "Album",
+		"xesam:url":     "file:///a.flac",
+	})
+
+	d := old.Diff(updated)
+	if want := []string{"xesam:album", "xesam:url"}; !slices.Equal(d.Added, want) {
+		t.Errorf("Added = %q, want %q", d.Added, want)
+	}
+	if want := []string{"mpris:artUrl"}; !slices.Equal(d.Removed, want) {
+		t.Errorf("Removed = %q, want %q", d.Removed, want)
+	}
+	if want := []string{"xesam:title"}; !slices.Equal(d.Changed, want) {
+		t.Errorf("Changed = %q, want %q", d.Changed, want)
+	}
+
+	r := updated.Diff(old)
+	if !slices.Equal(r.Added, d.Removed) || !slices.Equal(r.Removed, d.Added) {
+		t.Errorf("reversed Diff() = %+v", r)
+	}
+}
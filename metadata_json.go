@@ -0,0 +1,93 @@
+package mpris
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// LengthFormat selects how mpris:length is written by Metadata.MarshalJSON.
+type LengthFormat int
+
+//revive:disable:exported
+
+const (
+	// LengthMicroseconds writes the length as an integer in microseconds, as
+	// the player reports it.
+	LengthMicroseconds LengthFormat = iota
+	// LengthString writes the length as a duration string such as "3m35s".
+	LengthString
+)
+
+//revive:enable:exported
+
+// MarshalJSON encodes m as a JSON object of plain values. Variants are
+// unwrapped recursively, object paths and signatures become strings, and
+// mpris:length is written in microseconds. Use MarshalJSONWith to write the
+// length as a duration string instead.
+func (m Metadata) MarshalJSON() ([]byte, error) {
+	return m.MarshalJSONWith(LengthMicroseconds)
+}
+
+// MarshalJSONWith is like MarshalJSON, but writes mpris:length in the given
+// format. A length that isn't an integer is written as it is.
+func (m Metadata) MarshalJSONWith(format LengthFormat) ([]byte, error) {
+	if m == nil {
+		return []byte("null"), nil
+	}
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = plainValue(v)
+	}
+	if format == LengthString {
+		if d, err := m.GetDuration("mpris:length"); err == nil {
+			out["mpris:length"] = d.String()
+		}
+	}
+	return json.Marshal(out)
+}
+
+// plainValue converts a value decoded by godbus into one encoding/json writes
+// naturally: variants are unwrapped, D-Bus string types become strings, and
+// slices and string-keyed maps are converted element by element.
+func plainValue(v any) any {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case dbus.Variant:
+		return plainValue(v.Value())
+	case dbus.ObjectPath:
+		return string(v)
+	case dbus.Signature:
+		return v.String()
+	case string, bool, []byte:
+		return v
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		list := make([]any, rv.Len())
+		for i := range list {
+			list[i] = plainValue(rv.Index(i).Interface())
+		}
+		return list
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return v
+		}
+		obj := make(map[string]any, rv.Len())
+		for it := rv.MapRange(); it.Next(); {
+			obj[it.Key().String()] = plainValue(it.Value().Interface())
+		}
+		return obj
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return nil
+		}
+		return plainValue(rv.Elem().Interface())
+	default:
+		return v
+	}
+}
@@ -0,0 +1,62 @@
+package mpris
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func ExampleMetadata_MarshalJSON() {
+	m := Metadata{
+		"mpris:trackid": dbus.MakeVariant(dbus.ObjectPath("/org/mpd/Tracks/12")),
+		"mpris:length":  dbus.MakeVariant(int64(215_000_000)),
+		"xesam:title":   dbus.MakeVariant("Title"),
+		"xesam:artist": dbus.MakeVariant([]dbus.Variant{
+			dbus.MakeVariant("A"),
+			dbus.MakeVariant(dbus.MakeVariant("B")),
+		}),
+		"xesam:trackNumber": dbus.MakeVariant(int32(3)),
+		"vendor:extra": dbus.MakeVariant(map[string]dbus.Variant{
+			"rating": dbus.MakeVariant(0.5),
+		}),
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(data))
+
+	data, err = m.MarshalJSONWith(LengthString)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(data))
+	// Output:
+	// {"mpris:length":215000000,"mpris:trackid":"/org/mpd/Tracks/12","vendor:extra":{"rating":0.5},"xesam:artist":["A","B"],"xesam:title":"Title","xesam:trackNumber":3}
+	// {"mpris:length":"3m35s","mpris:trackid":"/org/mpd/Tracks/12","vendor:extra":{"rating":0.5},"xesam:artist":["A","B"],"xesam:title":"Title","xesam:trackNumber":3}
+}
+
+func TestMetadataMarshalJSONEmbedded(t *testing.T) {
+	v := struct {
+		Player   string   `json:"player"`
+		Metadata Metadata `json:"metadata"`
+		Missing  Metadata `json:"missing"`
+	}{
+		Player: "test",
+		Metadata: Metadata{
+			"xesam:genre": dbus.MakeVariant([]any{"Jazz", dbus.MakeVariant("Blues")}),
+		},
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"player":"test","metadata":{"xesam:genre":["Jazz","Blues"]},"missing":null}`
+	if string(data) != want {
+		t.Errorf("json = %s, want %s", data, want)
+	}
+}
@@ -0,0 +1,306 @@
+package mpris
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestMetadataDecode(t *testing.T) {
+	m := track(map[string]any{
+		"mpris:trackid":        dbus.ObjectPath("/org/mpd/Tracks/12"),
+		"mpris:length":         uint64(215_000_000),
+		"mpris:artUrl":         "file:///tmp/art.png",
+		"xesam:title":          "Title",
+		"xesam:album":          "Album",
+		"xesam:albumArtist":    []string{"Album Artist"},
+		"xesam:artist":         []dbus.Variant{dbus.MakeVariant("A"), dbus.MakeVariant("B")},
+		"xesam:url":            "file:///music/a.flac",
+		"xesam:trackNumber":    int32(3),
+		"xesam:discNumber":     uint32(1),
+		"xesam:genre":          "Jazz",
+		"xesam:composer":       "Composer",
+		"xesam:lyricist":       []string{"L1", "L2"},
+		"xesam:comment":        []any{"C"},
+		"xesam:userRating":     0.8,
+		"xesam:autoRating":     0.5,
+		"xesam:audioBPM":       uint64(120),
+		"xesam:contentCreated": "2007-04-29T14:35:51Z",
+		"vendor:extra":         "kept",
+	})
+
+	got, err := m.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.TrackID != "/org/mpd/Tracks/12" {
+		t.Errorf("TrackID = %q", got.TrackID)
+	}
+	if got.Length != 215*time.Second {
+		t.Errorf("Length = %v", got.Length)
+	}
+	if got.ArtURL != "file:///tmp/art.png" || got.URL != "file:///music/a.flac" {
+		t.Errorf("ArtURL = %q, URL = %q", got.ArtURL, got.URL)
+	}
+	if got.Title != "Title" || got.Album != "Album" {
+		t.Errorf("Title = %q, Album = %q", got.Title, got.Album)
+	}
+	if !slices.Equal(got.AlbumArtists, []string{"Album Artist"}) {
+		t.Errorf("AlbumArtists = %q", got.AlbumArtists)
+	}
+	if !slices.Equal(got.Artists, []string{"A", "B"}) {
+		t.Errorf("Artists = %q", got.Artists)
+	}
+	if !slices.Equal(got.Genres, []string{"Jazz"}) {
+		t.Errorf("Genres = %q", got.Genres)
+	}
+	if !slices.Equal(got.Composers, []string{"Composer"}) ||
+		!slices.Equal(got.Lyricists, []string{"L1", "L2"}) ||
+		!slices.Equal(got.Comments, []string{"C"}) {
+		t.Errorf("Composers = %q, Lyricists = %q, Comments = %q",
+			got.Composers, got.Lyricists, got.Comments)
+	}
+	if got.TrackNumber != 3 || got.DiscNumber != 1 || got.AudioBPM != 120 {
+		t.Errorf("TrackNumber = %d, DiscNumber = %d, AudioBPM = %d",
+			got.TrackNumber, got.DiscNumber, got.AudioBPM)
+	}
+	if got.UserRating != 0.8 || got.AutoRating != 0.5 {
+		t.Errorf("UserRating = %v, AutoRating = %v", got.UserRating, got.AutoRating)
+	}
+	want := time.Date(2007, 4, 29, 14, 35, 51, 0, time.UTC)
+	if !got.ContentCreated.Equal(want) {
+		t.Errorf("ContentCreated = %v, want %v", got.ContentCreated, want)
+	}
+	if v, _ := got.Raw.Get("vendor:extra"); v != "kept" {
+		t.Errorf("Raw vendor:extra = %v", v)
+	}
+}
+
+func TestMetadataDecodeLenient(t *testing.T) {
+	got, err := Metadata{}.Decode()
+	if err != nil {
+		t.Fatalf("Decode of empty metadata failed: %v", err)
+	}
+	if got.Title != "" || got.Length != 0 || got.Artists != nil {
+		t.Errorf("empty metadata decoded to %+v", got)
+	}
+
+	m := track(map[string]any{
+		"xesam:title":  "Title",
+		"mpris:length": []string{"not a number"},
+	})
+	got, err = m.Decode()
+	if err == nil {
+		t.Error("Decode accepted a malformed length")
+	}
+	if got.Title != "Title" {
+		t.Errorf("Title = %q, want the valid keys decoded anyway", got.Title)
+	}
+}
+
+func TestGetTrackMetadata(t *testing.T) {
+	server, player := testBus(t)
+	exportTestProperties(t, server, map[string]map[string]any{
+		PlayerInterface: {
+			"Metadata": map[string]dbus.Variant(track(map[string]any{
+				"xesam:title":  "Title",
+				"mpris:length": int64(1_000_000),
+			})),
+		},
+	})
+
+	got, err := player.GetTrackMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != "Title" || got.Length != time.Second {
+		t.Errorf("GetTrackMetadata() = %+v", got)
+	}
+}
+
+func TestToRating(t *testing.T) {
+	tests := []struct {
+		in   any
+		want float64
+	}{
+		{0.75, 0.75},
+		{1.0, 1},
+		{int32(0), 0},
+		{int32(1), 1},
+		{int64(4), 0.8},
+		{uint32(5), 1},
+		{int64(7), 1},
+		{uint8(3), 0.6},
+	}
+	for _, tt := range tests {
+		got, err := toRating(tt.in)
+		if err != nil {
+			t.Errorf("toRating(%T %v) returned error: %v", tt.in, tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("toRating(%T %v) = %v, want %v", tt.in, tt.in, got, tt.want)
+		}
+	}
+	if _, err := toRating([]string{"x"}); err == nil {
+		t.Error("toRating accepted a string slice")
+	}
+}
+
+func TestToTime(t *testing.T) {
+	tests := []struct {
+		in   any
+		want time.Time
+	}{
+		{"2007-04-29T14:35:51Z", time.Date(2007, 4, 29, 14, 35, 51, 0, time.UTC)},
+		{
+			"2007-04-29T14:35:51+02:00",
+			time.Date(2007, 4, 29, 12, 35, 51, 0, time.UTC),
+		},
+		{"2007-04-29T14:35:51", time.Date(2007, 4, 29, 14, 35, 51, 0, time.UTC)},
+		{"2007-04-29", time.Date(2007, 4, 29, 0, 0, 0, 0, time.UTC)},
+		{int64(1177857351), time.Unix(1177857351, 0)},
+		{uint32(1177857351), time.Unix(1177857351, 0)},
+		{"1177857351", time.Unix(1177857351, 0)},
+	}
+	for _, tt := range tests {
+		got, err := toTime(tt.in)
+		if err != nil {
+			t.Errorf("toTime(%#v) returned error: %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("toTime(%#v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	_, err := toTime("last tuesday")
+	if err == nil || !strings.Contains(err.Error(), "last tuesday") {
+		t.Errorf("toTime error %v doesn't include the raw value", err)
+	}
+}
+
+func TestToInt64(t *testing.T) {
+	tests := []struct {
+		in   any
+		want int64
+	}{
+		{int32(-3), -3},
+		{uint8(7), 7},
+		{uint64(215_000_000), 215_000_000},
+		{int64(-1), -1},
+		{3.0, 3},
+		{" 42 ", 42},
+	}
+	for _, tt := range tests {
+		got, err := toInt64(tt.in)
+		if err != nil {
+			t.Errorf("toInt64(%T %v) returned error: %v", tt.in, tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("toInt64(%T %v) = %d, want %d", tt.in, tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []any{uint64(1 << 63), 1.5, "1e3", true, []int{1}} {
+		if got, err := toInt64(in); err == nil {
+			t.Errorf("toInt64(%T %v) = %d, want error", in, in, got)
+		}
+	}
+}
+
+func TestMetadataTypedGetters(t *testing.T) {
+	m := track(map[string]any{
+		"mpris:trackid":    dbus.ObjectPath("/org/mpd/Tracks/1"),
+		"mpris:length":     uint64(1_500_000),
+		"xesam:title":      "Title",
+		"xesam:artist":     "Single Artist",
+		"xesam:useCount":   int32(9),
+		"xesam:autoRating": 0.5,
+		"vendor:flag":      true,
+		"vendor:broken":    []string{"a", "b"},
+	})
+
+	if got, err := m.GetString("xesam:title"); err != nil || got != "Title" {
+		t.Errorf("GetString = %q, %v", got, err)
+	}
+	got, err := m.GetStringSlice("xesam:artist")
+	if err != nil || !slices.Equal(got, []string{"Single Artist"}) {
+		t.Errorf("GetStringSlice = %q, %v", got, err)
+	}
+	if got, err := m.GetInt64("xesam:useCount"); err != nil || got != 9 {
+		t.Errorf("GetInt64 = %d, %v", got, err)
+	}
+	if got, err := m.GetFloat64("xesam:autoRating"); err != nil || got != 0.5 {
+		t.Errorf("GetFloat64 = %v, %v", got, err)
+	}
+	if got, err := m.GetBool("vendor:flag"); err != nil || !got {
+		t.Errorf("GetBool = %v, %v", got, err)
+	}
+	d, err := m.GetDuration("mpris:length")
+	if err != nil || d != 1500*time.Millisecond {
+		t.Errorf("GetDuration = %v, %v", d, err)
+	}
+	p, err := m.GetObjectPath("mpris:trackid")
+	if err != nil || p != "/org/mpd/Tracks/1" {
+		t.Errorf("GetObjectPath = %q, %v", p, err)
+	}
+
+	if _, err := m.GetString("xesam:album"); err == nil {
+		t.Error("GetString of a missing key returned no error")
+	}
+	_, err = m.GetInt64("vendor:broken")
+	if err == nil || !strings.Contains(err.Error(), "vendor:broken") {
+		t.Errorf("GetInt64 error %v doesn't name the key", err)
+	}
+}
+
+func TestToStringsAnySlice(t *testing.T) {
+	got, err := toStrings([]any{"A", dbus.MakeVariant("B")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, []string{"A", "B"}) {
+		t.Errorf("toStrings() = %q, want [A B]", got)
+	}
+	if _, err := toStrings(int32(1)); err == nil {
+		t.Error("toStrings accepted an integer")
+	}
+}
+
+func TestMetadataKeysAndNamespace(t *testing.T) {
+	m := track(map[string]any{
+		"mpris:trackid":    dbus.ObjectPath("spotify:track:4uLU6hMCjMI75M1A2tKUQC"),
+		"xesam:title":      "Title",
+		"xesam:artist":     []string{"A"},
+		"mpv:chapter":      int64(2),
+		"xesam:autoRating": 0.5,
+	})
+	m["vendor:nil"] = dbus.Variant{}
+
+	want := []string{"mpris:trackid", "mpv:chapter", "vendor:nil", "xesam:artist", "xesam:autoRating", "xesam:title"}
+	if got := m.Keys(); !slices.Equal(got, want) {
+		t.Errorf("Keys() = %q, want %q", got, want)
+	}
+
+	if !m.Has("mpv:chapter") || m.Has("xesam:album") || m.Has("vendor:nil") {
+		t.Error("Has() reports the wrong keys")
+	}
+
+	xesam := m.Namespace("xesam:")
+	if got := xesam.Keys(); !slices.Equal(got, []string{"xesam:artist", "xesam:autoRating", "xesam:title"}) {
+		t.Errorf("Namespace(xesam:) keys = %q", got)
+	}
+	xesam["xesam:artist"].Value().([]string)[0] = "changed"
+	if artist, _ := m.GetStringSlice("xesam:artist"); artist[0] != "A" {
+		t.Error("Namespace shares values with the original")
+	}
+	if got := m.Namespace("spotify:"); len(got) != 0 {
+		t.Errorf("Namespace(spotify:) = %v, want empty", got)
+	}
+}
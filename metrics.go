@@ -0,0 +1,67 @@
+package mpris
+
+import (
+	"context"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// Metrics is notified of every D-Bus call a Player makes and every signal it
+// handles, for instrumentation such as Prometheus counters. Property reads and
+// writes are the Get, GetAll and Set calls of org.freedesktop.DBus.Properties.
+// The methods are called from the goroutine of the call or of the
+// subscription handling the signal, without any lock of the package held, so
+// they must be safe for concurrent use.
+type Metrics interface {
+	// ObserveCall is called once a call of member of iface returns, having
+	// taken d, retries included, with the error of the call, translated like
+	// the errors the Player returns, or nil.
+	ObserveCall(iface, member string, d time.Duration, err error)
+	// ObserveSignal is called before the signal member of iface is handled.
+	ObserveSignal(iface, member string)
+}
+
+// noMetrics is the Metrics of players created without WithMetrics.
+type noMetrics struct{}
+
+func (noMetrics) ObserveCall(string, string, time.Duration, error) {}
+func (noMetrics) ObserveSignal(string, string)                     {}
+
+// WithMetrics makes the player report its calls and signals to m.
+func WithMetrics(m Metrics) Option {
+	return func(p *Player) {
+		if m == nil {
+			m = noMetrics{}
+		}
+		p.metrics = m
+	}
+}
+
+// observeCall reports a call of method, which took d and failed with err, if
+// not nil, to the logger and the metrics of the player.
+func (i *Player) observeCall(
+	ctx context.Context,
+	method string,
+	args []any,
+	d time.Duration,
+	attempts int,
+	err error,
+) {
+	i.logCall(ctx, method, args, d, attempts, err)
+	iface, member := splitMember(method)
+	i.metrics.ObserveCall(iface, member, d, translateError(err))
+}
+
+// observeSignals wraps handle to report every signal it gets to the logger
+// and the metrics of the player first.
+func (i *Player) observeSignals(
+	handle func(context.Context, *dbus.Signal),
+) func(context.Context, *dbus.Signal) {
+	return func(ctx context.Context, sig *dbus.Signal) {
+		i.logSignal(ctx, sig)
+		iface, member := splitMember(sig.Name)
+		i.metrics.ObserveSignal(iface, member)
+		handle(ctx, sig)
+	}
+}
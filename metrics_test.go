@@ -0,0 +1,109 @@
+package mpris
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// observedCall is a call reported to recordingMetrics.
+type observedCall struct {
+	member string
+	d      time.Duration
+	err    error
+}
+
+// recordingMetrics is a Metrics keeping what it observes.
+type recordingMetrics struct {
+	mu      sync.Mutex
+	calls   []observedCall
+	signals []string
+}
+
+func (m *recordingMetrics) ObserveCall(iface, member string, d time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, observedCall{iface + "." + member, d, err})
+}
+
+func (m *recordingMetrics) ObserveSignal(iface, member string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.signals = append(m.signals, iface+"."+member)
+}
+
+// call returns the first observed call of member.
+func (m *recordingMetrics) call(member string) (observedCall, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, c := range m.calls {
+		if c.member == member {
+			return c, true
+		}
+	}
+	return observedCall{}, false
+}
+
+func TestWithMetrics(t *testing.T) {
+	m := &recordingMetrics{}
+	addr := testBusAddress(t)
+	server := addTestPlayer(t, addr, testPlayerName, map[string]map[string]any{
+		PlayerInterface: {"PlaybackStatus": "Playing", "Volume": 0.5},
+	})
+	player := New(testConn(t, addr), testPlayerName, WithMetrics(m))
+
+	if _, err := player.GetPlaybackStatus(); err != nil {
+		t.Fatal(err)
+	}
+	if err := player.SetPlayerProperty("Volume", 0.25); err != nil {
+		t.Fatal(err)
+	}
+	if err := player.Next(); err == nil {
+		t.Fatal("Next() succeeded on a player without the method")
+	}
+	for _, member := range []string{GetPropertyMethod, SetPropertyMethod} {
+		if c, ok := m.call(member); !ok || c.err != nil || c.d <= 0 {
+			t.Errorf("call of %s = %+v, %v, want a successful one", member, c, ok)
+		}
+	}
+	c, ok := m.call(PlayerInterface + ".Next")
+	if !ok || !errors.Is(c.err, ErrNotSupported) {
+		t.Errorf("call of Next = %+v, %v, want ErrNotSupported", c, ok)
+	}
+
+	seeked := make(chan time.Duration, 1)
+	sub, err := player.WatchSeeked(seeked)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+	if err := server.Emit(DBusObjectPath, PlayerInterface+".Seeked", int64(1)); err != nil {
+		t.Fatal(err)
+	}
+	<-seeked
+	m.mu.Lock()
+	signals := m.signals
+	m.mu.Unlock()
+	if len(signals) != 1 || signals[0] != PlayerInterface+".Seeked" {
+		t.Errorf("signals = %q, want Seeked", signals)
+	}
+}
+
+func TestNoMetricsAllocations(t *testing.T) {
+	player := New(nil, testPlayerName)
+	ctx := context.Background()
+	args := []any{PlayerInterface, "Volume"}
+	sig := &dbus.Signal{Name: PlayerInterface + ".Seeked"}
+	handle := player.observeSignals(func(context.Context, *dbus.Signal) {})
+	allocs := testing.AllocsPerRun(100, func() {
+		player.observeCall(ctx, GetPropertyMethod, args, time.Millisecond, 1, nil)
+		handle(ctx, sig)
+	})
+	if allocs != 0 {
+		t.Errorf("observing without logger and metrics allocates %v times", allocs)
+	}
+}
@@ -1,7 +1,12 @@
 package mpris
 
 import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/godbus/dbus/v5"
@@ -35,32 +40,166 @@ const (
 	// of a writable property on an interface that implements
 	// org.freedesktop.DBus.Properties.
 	SetPropertyMethod = "org.freedesktop.DBus.Properties.Set"
+	// GetAllPropertiesMethod is the standard D-Bus method used to retrieve
+	// all properties of an interface in a single call.
+	GetAllPropertiesMethod = "org.freedesktop.DBus.Properties.GetAll"
+	// NoTrack is the track ID the spec reserves for "no track", reported as
+	// mpris:trackid when nothing is loaded.
+	NoTrack = "/org/mpris/MediaPlayer2/TrackList/NoTrack"
 )
 
-// List lists the available players.
-func List(conn *dbus.Conn) ([]string, error) {
+// ListOption customizes which players List returns.
+type ListOption func(*listOptions)
+
+// listOptions holds the settings of a List call.
+type listOptions struct {
+	playerctld bool
+	// exclude holds the name patterns of ExcludeNames.
+	exclude []string
+	// identity, onlyPlaying and responsive are only applied by ListFiltered
+	// and ListPlayers.
+	identity    string
+	onlyPlaying bool
+	responsive  bool
+	// attempts and backoff are set by ListRetry.
+	attempts int
+	backoff  time.Duration
+}
+
+// IncludePlayerctld sets whether List returns PlayerctldName. playerctld
+// proxies another player that List already returns, so the name shows up as
+// a duplicate; it is included by default.
+func IncludePlayerctld(include bool) ListOption {
+	return func(o *listOptions) {
+		o.playerctld = include
+	}
+}
+
+// List lists the available players. Only the options looking at bus names
+// alone, IncludePlayerctld, ExcludePlayerctld and ExcludeNames, and ListRetry
+// are applied; use ListFiltered for the others.
+func List(conn *dbus.Conn, opts ...ListOption) ([]string, error) {
+	o := listOptions{playerctld: true}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	var names []string
-	err := conn.BusObject().
-		Call("org.freedesktop.DBus.ListNames", 0).
-		Store(&names)
-	if err != nil {
+	call, _ := retryCall(
+		context.Background(),
+		o.attempts,
+		o.backoff,
+		func(ctx context.Context) *dbus.Call {
+			return conn.BusObject().
+				CallWithContext(ctx, "org.freedesktop.DBus.ListNames", 0)
+		},
+	)
+	if err := call.Store(&names); err != nil {
 		return nil, err
 	}
 
 	var mprisNames []string
 	for _, name := range names {
-		if strings.HasPrefix(name, BaseInterface) {
-			mprisNames = append(mprisNames, name)
+		if !strings.HasPrefix(name, BaseInterface) {
+			continue
+		}
+		if name == PlayerctldName && !o.playerctld {
+			continue
 		}
+		if slices.ContainsFunc(o.exclude, func(p string) bool {
+			return nameMatches(name, p)
+		}) {
+			continue
+		}
+		mprisNames = append(mprisNames, name)
 	}
 	return mprisNames, nil
 }
 
 // Player represents a mpris player.
+//
+// A Player is safe for concurrent use by multiple goroutines. The settings
+// given to New never change afterwards, and the state that does is guarded
+// by an internal mutex.
 type Player struct {
 	conn *dbus.Conn
-	obj  *dbus.Object
+	tr   transport
 	name string
+
+	// path is the object path the player exports MPRIS at.
+	path dbus.ObjectPath
+	// flags are passed to every method call.
+	flags dbus.Flags
+	// callTimeout bounds every call made by the player. Zero means no bound.
+	callTimeout time.Duration
+	// attempts is how many times an idempotent call is tried while it fails
+	// with a transient error, waiting backoff before the first retry and
+	// doubling it after each one.
+	attempts int
+	backoff  time.Duration
+	// trackOwner is set by WithOwnerTracking.
+	trackOwner bool
+	// cacheProperties is set by WithPropertyCache.
+	cacheProperties bool
+	// serializeCalls and queueDepth are set by WithSerializedCalls and
+	// coalesceSetters by WithCoalescedSetters. They configure queue.
+	serializeCalls  bool
+	queueDepth      int
+	coalesceSetters bool
+	// queue sends the method calls and property writes of serialized
+	// players.
+	queue *callQueue
+	// noQuirks is set by WithoutQuirks.
+	noQuirks bool
+	// maxVolume is the highest volume AdjustVolume sets, 1 unless changed by
+	// WithVolumeLimit, which also sets clampVolume.
+	maxVolume   float64
+	clampVolume bool
+	// logger is set by WithLogger.
+	logger *slog.Logger
+	// metrics is set by WithMetrics, noMetrics otherwise.
+	metrics Metrics
+	// echoWindow is set by WithEchoSuppression.
+	echoWindow time.Duration
+
+	// mu guards the fields below, which may change after New returns.
+	mu sync.Mutex
+	// owner is the unique name owning name when the player was created by
+	// NewChecked, empty otherwise.
+	owner string
+	// tracker follows the owner of name once WithOwnerTracking started it.
+	tracker *ownerTracker
+	// cache holds the properties read once WithPropertyCache started it.
+	cache *propertyCache
+	// quirks are the quirks picked by the desktop entry or identity of the
+	// player, once read.
+	quirks *Quirks
+	// estimate follows the position of players whose Position property
+	// can't be trusted, once needed.
+	estimate *positionEstimate
+	// displayName is cached by DisplayName once read from the player.
+	displayName string
+	// introspection is kept by Introspect. noIntrospection is set when the
+	// player turned out not to support introspection.
+	introspection   *Introspection
+	noIntrospection bool
+	// unmuteVolume is the volume Mute replaced, restored by Unmute when
+	// hasUnmuteVolume is set.
+	unmuteVolume    float64
+	hasUnmuteVolume bool
+	// writes holds the values the player wrote, by qualified property name,
+	// once WithEchoSuppression needs them.
+	writes map[string]ownWrite
+	// lastErr is the error of the latest Or getter, see LastError.
+	lastErr error
+}
+
+// state returns the owner captured by NewChecked and the owner tracker of
+// the player, either of which may be unset.
+func (i *Player) state() (string, *ownerTracker) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.owner, i.tracker
 }
 
 // GetName gets the player full name.
@@ -70,50 +209,248 @@ func (i *Player) GetName() string {
 
 // CanEditTracks returns if player can edit track list
 func (i *Player) CanEditTracks() (bool, error) {
-	return getTrackListPropertyCast(i, "CanEditTracks", cast.ToBoolE)
+	return i.CanEditTracksContext(context.Background())
+}
+
+// CanEditTracksContext is like CanEditTracks but takes a context.
+func (i *Player) CanEditTracksContext(ctx context.Context) (bool, error) {
+	return getTrackListPropertyCast(ctx, i, "CanEditTracks", cast.ToBoolE)
 }
 
 // GetLength returns the current track length.
 func (i *Player) GetLength() (time.Duration, error) {
-	micro, err := getMetadataCast(i, "mpris:length", cast.ToInt64E)
-	return time.Duration(micro) * time.Microsecond, err
+	return getMetadataValue(i, "mpris:length", Metadata.getLength)
 }
 
-// GetTrackID returns track id for player as dbus.ObjectPath
+// GetTrackID returns track id for player as dbus.ObjectPath. Some players,
+// like mpv, send IDs that aren't valid object paths, which SetPositionFor
+// rejects with ErrInvalidTrackID; GetTrackIDString returns them for display.
 func (i *Player) GetTrackID() (dbus.ObjectPath, error) {
-	trackIDStr, err := getMetadataCast(i, "mpris:trackid", cast.ToStringE)
-	return dbus.ObjectPath(trackIDStr), err
+	return getMetadataValue(i, "mpris:trackid", Metadata.GetObjectPath)
+}
+
+// GetTrackIDString returns the track id exactly as the player sent it, valid
+// object path or not.
+func (i *Player) GetTrackIDString() (string, error) {
+	return getMetadataValue(i, "mpris:trackid", Metadata.GetString)
+}
+
+// GetTrackMetadata returns the decoded metadata of the current track. See
+// Metadata.Decode.
+func (i *Player) GetTrackMetadata() (TrackMetadata, error) {
+	return i.GetTrackMetadataContext(context.Background())
+}
+
+// GetTrackMetadataContext is like GetTrackMetadata but takes a context.
+func (i *Player) GetTrackMetadataContext(
+	ctx context.Context,
+) (TrackMetadata, error) {
+	m, err := i.GetMetadataContext(ctx)
+	if err != nil {
+		return TrackMetadata{}, err
+	}
+	return m.decode(m)
 }
 
 // GetTitle returns the current track title.
 func (i *Player) GetTitle() (string, error) {
-	return getMetadataCast(i, "xesam:title", cast.ToStringE)
+	return getMetadataValue(i, "xesam:title", Metadata.GetString)
 }
 
-// GetArtist returns the current track artist(s).
+// GetArtist returns the current track artist(s). Besides the spec's array of
+// strings, players sending a plain string or an array of variants are handled,
+// and a plain string is never split.
 func (i *Player) GetArtist() ([]string, error) {
-	return getMetadataCast(i, "xesam:artist", cast.ToStringSliceE)
+	return getMetadataValue(i, "xesam:artist", Metadata.GetStringSlice)
+}
+
+// GetArtistString returns the current track artist(s) joined by sep.
+func (i *Player) GetArtistString(sep string) (string, error) {
+	artists, err := i.GetArtist()
+	return strings.Join(artists, sep), err
+}
+
+// GetAlbumArtists returns the current track album artist(s). A single string
+// is accepted as well as the list the spec asks for.
+func (i *Player) GetAlbumArtists() ([]string, error) {
+	return getMetadataValue(i, "xesam:albumArtist", Metadata.GetStringSlice)
+}
+
+// GetGenres returns the genre(s) of the current track. A single string is
+// accepted as well as the list the spec asks for.
+func (i *Player) GetGenres() ([]string, error) {
+	return getMetadataValue(i, "xesam:genre", Metadata.GetStringSlice)
+}
+
+// GetComposer returns the composer(s) of the current track, or an empty slice
+// when the player doesn't report any.
+func (i *Player) GetComposer() ([]string, error) {
+	return getOptionalMetadataStrings(i, "xesam:composer")
+}
+
+// GetLyricist returns the lyricist(s) of the current track, or an empty slice
+// when the player doesn't report any.
+func (i *Player) GetLyricist() ([]string, error) {
+	return getOptionalMetadataStrings(i, "xesam:lyricist")
+}
+
+// GetComment returns the comment(s) of the current track, or an empty slice
+// when the player doesn't report any.
+func (i *Player) GetComment() ([]string, error) {
+	return getOptionalMetadataStrings(i, "xesam:comment")
+}
+
+// GetLyrics returns the lyrics of the current track from xesam:asText. The
+// error wraps ErrMetadataKeyMissing when the player doesn't report any.
+func (i *Player) GetLyrics() (string, error) {
+	return getMetadataValue(i, "xesam:asText", Metadata.getLyrics)
+}
+
+// GetUserRating returns the user-specified rating of the current track in the
+// range 0.0 to 1.0. See toRating for how integer star ratings are handled.
+func (i *Player) GetUserRating() (float64, error) {
+	return getMetadataValue(i, "xesam:userRating", Metadata.GetRating)
+}
+
+// GetAutoRating returns the automatically generated rating of the current
+// track in the range 0.0 to 1.0. See toRating for how integer star ratings are
+// handled.
+func (i *Player) GetAutoRating() (float64, error) {
+	return getMetadataValue(i, "xesam:autoRating", Metadata.GetRating)
+}
+
+// GetAudioBPM returns the beats per minute of the current track.
+func (i *Player) GetAudioBPM() (int, error) {
+	return getMetadataValue(i, "xesam:audioBPM", Metadata.getInt)
+}
+
+// GetContentCreated returns when the current track was created, usually the
+// recording date. See toTime for the accepted formats.
+func (i *Player) GetContentCreated() (time.Time, error) {
+	return getMetadataValue(i, "xesam:contentCreated", Metadata.GetTime)
+}
+
+// GetFirstUsed returns when the current track was first played.
+func (i *Player) GetFirstUsed() (time.Time, error) {
+	return getMetadataValue(i, "xesam:firstUsed", Metadata.GetTime)
+}
+
+// GetLastUsed returns when the current track was last played.
+func (i *Player) GetLastUsed() (time.Time, error) {
+	return getMetadataValue(i, "xesam:lastUsed", Metadata.GetTime)
+}
+
+// GetUseCount returns how many times the current track has been played.
+func (i *Player) GetUseCount() (int, error) {
+	return getMetadataValue(i, "xesam:useCount", Metadata.getInt)
 }
 
 // GetAlbum returns the current track album.
 func (i *Player) GetAlbum() (string, error) {
-	return getMetadataCast(i, "xesam:album", cast.ToStringE)
+	return getMetadataValue(i, "xesam:album", Metadata.GetString)
 }
 
 // GetURL returns the URL of the current track.
 func (i *Player) GetURL() (string, error) {
-	return getMetadataCast(i, "xesam:url", cast.ToStringE)
+	return getMetadataValue(i, "xesam:url", Metadata.GetString)
 }
 
 // GetCoverURL returns the cover art URL of the current track.
 func (i *Player) GetCoverURL() (string, error) {
-	return getMetadataCast(i, "mpris:artUrl", cast.ToStringE)
+	return getMetadataValue(i, "mpris:artUrl", Metadata.GetString)
+}
+
+// New connects the the player with the name in the connection conn. The
+// options customize the behavior of the returned Player. When the owner
+// tracking of WithOwnerTracking or the cache of WithPropertyCache can't be
+// started, the player works without them.
+func New(conn *dbus.Conn, name string, opts ...Option) *Player {
+	p, _ := newPlayer(conn, name, opts...)
+	return p
+}
+
+// newPlayer is like New but also returns the error starting the owner
+// tracking or the property cache failed with.
+func newPlayer(conn *dbus.Conn, name string, opts ...Option) (*Player, error) {
+	p, err := newTransportPlayer(connTransport{conn}, name, opts...)
+	p.conn = conn
+	return p, err
+}
+
+// newTransportPlayer is like newPlayer but talks to the player through tr.
+func newTransportPlayer(
+	tr transport,
+	name string,
+	opts ...Option,
+) (*Player, error) {
+	p := &Player{
+		tr:        tr,
+		name:      name,
+		path:      DBusObjectPath,
+		maxVolume: 1,
+		metrics:   noMetrics{},
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.serializeCalls {
+		p.queue = &callQueue{
+			depth:    p.queueDepth,
+			coalesce: p.coalesceSetters,
+			call:     p.doNow,
+		}
+	}
+	if p.trackOwner {
+		if err := p.startOwnerTracking(); err != nil {
+			return p, err
+		}
+	}
+	if p.cacheProperties {
+		return p, p.startPropertyCache()
+	}
+	return p, nil
+}
+
+// NewChecked is like New, but fails with an error wrapping ErrPlayerGone when
+// nobody owns name. The unique name of the owner is captured and returned by
+// Owner; signal subscriptions of the player only accept signals from it, so
+// the Player stays bound to this instance of the application unless
+// WithOwnerTracking is given. Failing to start owner tracking is an error too.
+func NewChecked(conn *dbus.Conn, name string, opts ...Option) (*Player, error) {
+	p, err := newPlayer(conn, name, opts...)
+	if err != nil {
+		return nil, err
+	}
+	hasOwner, err := p.Exists()
+	if err != nil {
+		p.Close()
+		return nil, err
+	}
+	if !hasOwner {
+		p.Close()
+		return nil, fmt.Errorf("%w: %s has no owner", ErrPlayerGone, name)
+	}
+	owner, err := p.getOwner()
+	if err != nil {
+		p.Close()
+		return nil, err
+	}
+	p.mu.Lock()
+	p.owner = owner
+	p.mu.Unlock()
+	return p, nil
 }
 
-// New connects the the player with the name in the connection conn.
-func New(conn *dbus.Conn, name string) *Player {
-	obj := conn.Object(name, DBusObjectPath).(*dbus.Object)
-	return &Player{conn, obj, name}
+// Owner returns the unique bus name, such as ":1.42", that owned the player's
+// name when it was created by NewChecked. It is empty for players created by
+// New. With WithOwnerTracking it is the current owner instead, or empty while
+// nobody owns the name.
+func (i *Player) Owner() string {
+	owner, tracker := i.state()
+	if tracker != nil {
+		return tracker.current()
+	}
+	return owner
 }
 
 // OnSignal adds a handler to the player's properties change signal.
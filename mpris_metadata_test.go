@@ -0,0 +1,216 @@
+package mpris
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// testMetadataPlayer returns a Player whose Metadata property is m.
+func testMetadataPlayer(t *testing.T, m map[string]any) *Player {
+	t.Helper()
+	server, player := testBus(t)
+	exportTestProperties(t, server, map[string]map[string]any{
+		PlayerInterface: {
+			"Metadata": map[string]dbus.Variant(track(m)),
+		},
+	})
+	return player
+}
+
+func TestGetStringListAccessors(t *testing.T) {
+	player := testMetadataPlayer(t, map[string]any{
+		"xesam:genre":       "Rock",
+		"xesam:albumArtist": []string{"A", "B"},
+	})
+
+	genres, err := player.GetGenres()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(genres, []string{"Rock"}) {
+		t.Errorf("GetGenres() = %q, want [Rock]", genres)
+	}
+
+	artists, err := player.GetAlbumArtists()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(artists, []string{"A", "B"}) {
+		t.Errorf("GetAlbumArtists() = %q, want [A B]", artists)
+	}
+}
+
+func TestGetOptionalStringListAccessors(t *testing.T) {
+	player := testMetadataPlayer(t, map[string]any{
+		"xesam:composer": "J. S. Bach",
+		"xesam:comment":  []string{"first", "second"},
+	})
+
+	tests := []struct {
+		name string
+		get  func() ([]string, error)
+		want []string
+	}{
+		{"GetComposer", player.GetComposer, []string{"J. S. Bach"}},
+		{"GetLyricist", player.GetLyricist, []string{}},
+		{"GetComment", player.GetComment, []string{"first", "second"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.get()
+			if err != nil {
+				t.Fatalf("%s returned error: %v", tt.name, err)
+			}
+			if got == nil || !slices.Equal(got, tt.want) {
+				t.Errorf("%s() = %#v, want %#v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetRatingsAndBPM(t *testing.T) {
+	player := testMetadataPlayer(t, map[string]any{
+		"xesam:userRating": int32(4),
+		"xesam:autoRating": 0.25,
+		"xesam:audioBPM":   uint32(128),
+	})
+
+	user, err := player.GetUserRating()
+	if err != nil || user != 0.8 {
+		t.Errorf("GetUserRating() = %v, %v, want 0.8", user, err)
+	}
+	auto, err := player.GetAutoRating()
+	if err != nil || auto != 0.25 {
+		t.Errorf("GetAutoRating() = %v, %v, want 0.25", auto, err)
+	}
+	bpm, err := player.GetAudioBPM()
+	if err != nil || bpm != 128 {
+		t.Errorf("GetAudioBPM() = %v, %v, want 128", bpm, err)
+	}
+}
+
+func TestGetUsageTimestamps(t *testing.T) {
+	player := testMetadataPlayer(t, map[string]any{
+		"xesam:contentCreated": "1999-12-31",
+		"xesam:firstUsed":      int64(946684800),
+		"xesam:lastUsed":       "2000-01-02T00:00:00Z",
+		"xesam:useCount":       uint32(7),
+	})
+
+	tests := []struct {
+		name string
+		get  func() (time.Time, error)
+		want time.Time
+	}{
+		{"GetContentCreated", player.GetContentCreated, time.Date(1999, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{"GetFirstUsed", player.GetFirstUsed, time.Unix(946684800, 0)},
+		{"GetLastUsed", player.GetLastUsed, time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := tt.get()
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("%s() = %v, %v, want %v", tt.name, got, err, tt.want)
+		}
+	}
+
+	count, err := player.GetUseCount()
+	if err != nil || count != 7 {
+		t.Errorf("GetUseCount() = %v, %v, want 7", count, err)
+	}
+}
+
+func TestGetArtistPayloads(t *testing.T) {
+	tests := []struct {
+		name   string
+		artist any
+		want   []string
+	}{
+		{"string", "Simon & Garfunkel", []string{"Simon & Garfunkel"}},
+		{"strings", []string{"A", "B C"}, []string{"A", "B C"}},
+		{"variants", []dbus.Variant{dbus.MakeVariant("A"), dbus.MakeVariant("B")}, []string{"A", "B"}},
+		{"empty", []string{}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			player := testMetadataPlayer(t, map[string]any{"xesam:artist": tt.artist})
+
+			got, err := player.GetArtist()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("GetArtist() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetArtistString(t *testing.T) {
+	player := testMetadataPlayer(t, map[string]any{
+		"xesam:artist": []string{"Daft Punk", "Pharrell Williams"},
+	})
+
+	got, err := player.GetArtistString(", ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "Daft Punk, Pharrell Williams" {
+		t.Errorf("GetArtistString() = %q", got)
+	}
+}
+
+func TestGetLyrics(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"string", "line one\nline two", "line one\nline two"},
+		{"lines", []string{"line one", "line two"}, "line one\nline two"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			player := testMetadataPlayer(t, map[string]any{"xesam:asText": tt.value})
+			got, err := player.GetLyrics()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("GetLyrics() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetLyricsMissing(t *testing.T) {
+	player := testMetadataPlayer(t, map[string]any{"xesam:title": "Title"})
+	if _, err := player.GetLyrics(); !errors.Is(err, ErrMetadataKeyMissing) {
+		t.Errorf("GetLyrics() error = %v, want ErrMetadataKeyMissing", err)
+	}
+}
+
+func TestGetMetadataInto(t *testing.T) {
+	player := testMetadataPlayer(t, map[string]any{"xesam:title": "Title"})
+	m := Metadata{"xesam:album": dbus.MakeVariant("stale")}
+	if err := player.GetMetadataInto(m); err != nil {
+		t.Fatal(err)
+	}
+	if title, _ := m.GetString("xesam:title"); title != "Title" {
+		t.Errorf("title = %q, want Title", title)
+	}
+	if m.Has("xesam:album") {
+		t.Error("stale key kept")
+	}
+
+	player.path = "/nowhere"
+	if err := player.GetMetadataInto(m); err == nil {
+		t.Fatal("GetMetadataInto succeeded without a player")
+	}
+	if len(m) != 0 {
+		t.Errorf("m = %v after a failed read, want it empty", m)
+	}
+}
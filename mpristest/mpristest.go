@@ -0,0 +1,476 @@
+// Package mpristest provides a fake MPRIS player for the tests of code using
+// the mpris package. The fake runs on a private dbus-daemon, so tests don't
+// depend on the players running on the developer's session bus.
+package mpristest
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"maps"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Nadim147c/go-mpris"
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+	"github.com/godbus/dbus/v5/prop"
+)
+
+// Name is the bus name owned by a FakePlayer.
+const Name = mpris.BaseInterface + ".fake"
+
+// Call is a method call received by a FakePlayer. Method is the name of the
+// D-Bus method, e.g. "Seek", and Args its arguments as sent by the client.
+// Writes of properties are recorded with the Method "Set" and the Args
+// interface, name and value.
+type Call struct {
+	Method string
+	Args   []any
+}
+
+// Handler scripts the answer of a FakePlayer to a method call. An error
+// returned by the handler is sent to the client; a *dbus.Error is sent as
+// is, other errors as org.freedesktop.DBus.Error.Failed.
+type Handler func(f *FakePlayer, args []any) error
+
+// FakePlayer is an MPRIS player whose properties are set by the test and
+// which records the method calls it receives. Methods succeed without doing
+// anything unless a Handler is set with Handle. A FakePlayer is safe for
+// concurrent use.
+type FakePlayer struct {
+	t    testing.TB
+	addr string
+	conn *dbus.Conn
+
+	mu       sync.Mutex
+	props    map[string]map[string]dbus.Variant
+	calls    []Call
+	handlers map[string]Handler
+	quirks   map[Quirk]bool
+	delays   map[string]time.Duration
+	dropped  map[string]bool
+	readOnly map[string]bool
+}
+
+// defaults returns the properties of a new FakePlayer: a stopped player that
+// can be fully controlled.
+func defaults() map[string]map[string]any {
+	return map[string]map[string]any{
+		mpris.BaseInterface: {
+			"CanQuit":             true,
+			"CanRaise":            true,
+			"HasTrackList":        false,
+			"Identity":            "Fake Player",
+			"DesktopEntry":        "fake",
+			"SupportedUriSchemes": []string{"file"},
+			"SupportedMimeTypes":  []string{"audio/mpeg"},
+		},
+		mpris.PlayerInterface: {
+			"PlaybackStatus": string(mpris.PlaybackStopped),
+			"LoopStatus":     string(mpris.LoopNone),
+			"Rate":           1.0,
+			"Shuffle":        false,
+			"Metadata":       map[string]dbus.Variant{},
+			"Volume":         1.0,
+			"Position":       int64(0),
+			"MinimumRate":    1.0,
+			"MaximumRate":    1.0,
+			"CanGoNext":      true,
+			"CanGoPrevious":  true,
+			"CanPlay":        true,
+			"CanPause":       true,
+			"CanSeek":        true,
+			"CanControl":     true,
+		},
+	}
+}
+
+// NewFakePlayer starts a private bus, exports a FakePlayer on it under Name
+// and returns the fake with a Player connected to it from another
+// connection. Everything is torn down when the test ends. The test is
+// skipped when dbus-daemon isn't installed.
+func NewFakePlayer(t testing.TB) (*FakePlayer, *mpris.Player) {
+	t.Helper()
+	f := &FakePlayer{
+		t:        t,
+		addr:     startBus(t),
+		props:    map[string]map[string]dbus.Variant{},
+		handlers: map[string]Handler{},
+		quirks:   map[Quirk]bool{},
+		delays:   map[string]time.Duration{},
+		dropped:  map[string]bool{},
+		readOnly: map[string]bool{},
+	}
+	for iface, values := range defaults() {
+		f.props[iface] = map[string]dbus.Variant{}
+		for name, v := range values {
+			f.props[iface][name] = dbus.MakeVariant(v)
+		}
+	}
+
+	f.conn = f.Connect()
+	path := dbus.ObjectPath(mpris.DBusObjectPath)
+	exports := []struct {
+		v       any
+		methods map[string]string
+		iface   string
+	}{
+		{rootObject{f}, nil, mpris.BaseInterface},
+		{playerObject{f}, map[string]string{"SeekBy": "Seek"}, mpris.PlayerInterface},
+		{properties{f}, nil, "org.freedesktop.DBus.Properties"},
+		{introspectable{f}, nil, "org.freedesktop.DBus.Introspectable"},
+	}
+	for _, e := range exports {
+		err := f.conn.ExportWithMap(e.v, e.methods, path, e.iface)
+		if err != nil {
+			t.Fatalf("Could not export %s: %v", e.iface, err)
+		}
+	}
+	reply, err := f.conn.RequestName(Name, dbus.NameFlagDoNotQueue)
+	if err != nil || reply != dbus.RequestNameReplyPrimaryOwner {
+		t.Fatalf("Could not claim %s: %v", Name, err)
+	}
+	return f, mpris.New(f.Connect(), Name)
+}
+
+// startBus starts a dbus-daemon for the duration of the test and returns its
+// address.
+func startBus(t testing.TB) string {
+	t.Helper()
+	bin, err := exec.LookPath("dbus-daemon")
+	if err != nil {
+		t.Skip("dbus-daemon not found")
+	}
+	cmd := exec.Command(bin, "--session", "--nofork", "--print-address=1")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Skipf("Could not start dbus-daemon: %v", err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+	addr, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		t.Fatalf("Could not read dbus-daemon address: %v", err)
+	}
+	return strings.TrimSpace(addr)
+}
+
+// Address returns the address of the private bus of the fake.
+func (f *FakePlayer) Address() string {
+	return f.addr
+}
+
+// Connect opens another connection to the private bus of the fake, which is
+// closed when the test ends.
+func (f *FakePlayer) Connect() *dbus.Conn {
+	f.t.Helper()
+	conn, err := dbus.Connect(f.addr)
+	if err != nil {
+		f.t.Fatalf("Could not connect to the fake's bus: %v", err)
+	}
+	f.t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// Set changes the value of the property iface.name and announces it with
+// PropertiesChanged. v is sent with its Go type, so tests can give
+// properties the wrong type on purpose.
+func (f *FakePlayer) Set(iface, name string, v any) {
+	f.t.Helper()
+	variant, ok := v.(dbus.Variant)
+	if !ok {
+		variant = dbus.MakeVariant(v)
+	}
+	if err := f.set(iface, name, variant); err != nil {
+		f.t.Errorf("Could not announce %s.%s: %v", iface, name, err)
+	}
+}
+
+// set is like Set, but returns the error instead of failing the test, for
+// the goroutines of godbus, which may outlive it.
+func (f *FakePlayer) set(iface, name string, v dbus.Variant) error {
+	f.mu.Lock()
+	if f.dropped[name] {
+		f.mu.Unlock()
+		return nil
+	}
+	if f.props[iface] == nil {
+		f.props[iface] = map[string]dbus.Variant{}
+	}
+	f.props[iface][name] = v
+	changed := map[string]dbus.Variant{name: f.quirky(name, v)}
+	invalidated := []string{}
+	if f.hasQuirk(InvalidatesOnly) {
+		changed, invalidated = map[string]dbus.Variant{}, []string{name}
+	}
+	f.mu.Unlock()
+
+	return f.conn.Emit(
+		mpris.DBusObjectPath,
+		mpris.PropertiesChangedSignal,
+		iface,
+		changed,
+		invalidated,
+	)
+}
+
+// SetPlayer is like Set for a property of the player interface.
+func (f *FakePlayer) SetPlayer(name string, v any) {
+	f.t.Helper()
+	f.Set(mpris.PlayerInterface, name, v)
+}
+
+// SetMetadata changes the metadata of the current track.
+func (f *FakePlayer) SetMetadata(m mpris.Metadata) {
+	f.t.Helper()
+	f.SetPlayer("Metadata", map[string]dbus.Variant(m))
+}
+
+// SetPosition changes the Position property, which, as the spec asks, isn't
+// announced.
+func (f *FakePlayer) SetPosition(position time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.dropped["Position"] {
+		return
+	}
+	f.props[mpris.PlayerInterface]["Position"] = dbus.MakeVariant(
+		position.Microseconds(),
+	)
+}
+
+// Get returns the value of the property iface.name as set, without the
+// quirks of the fake, and false when the fake doesn't have it.
+func (f *FakePlayer) Get(iface, name string) (dbus.Variant, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	v, ok := f.props[iface][name]
+	return v, ok
+}
+
+// EmitSeeked emits the Seeked signal with position.
+func (f *FakePlayer) EmitSeeked(position time.Duration) {
+	f.t.Helper()
+	var v any = position.Microseconds()
+	f.mu.Lock()
+	if f.hasQuirk(SeekedAsInt32) {
+		v = int32(position.Microseconds())
+	}
+	f.mu.Unlock()
+	err := f.conn.Emit(mpris.DBusObjectPath, mpris.PlayerInterface+".Seeked", v)
+	if err != nil {
+		f.t.Errorf("Could not emit Seeked: %v", err)
+	}
+}
+
+// Handle sets the handler answering calls of method, e.g. "Play" or "Set".
+// The call is recorded before the handler runs.
+func (f *FakePlayer) Handle(method string, h Handler) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.handlers[method] = h
+}
+
+// Calls returns the method calls received so far, oldest first.
+func (f *FakePlayer) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.calls)
+}
+
+// Methods returns the names of the methods called so far, oldest first.
+func (f *FakePlayer) Methods() []string {
+	var methods []string
+	for _, c := range f.Calls() {
+		methods = append(methods, c.Method)
+	}
+	return methods
+}
+
+// Reset forgets the calls received so far.
+func (f *FakePlayer) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = nil
+}
+
+// call records a call of method and runs its handler, after the delay of
+// method.
+func (f *FakePlayer) call(method string, args ...any) *dbus.Error {
+	f.mu.Lock()
+	f.calls = append(f.calls, Call{method, args})
+	h := f.handlers[method]
+	f.mu.Unlock()
+	f.wait(method)
+	if h == nil {
+		return nil
+	}
+	return dbusError(h(f, args))
+}
+
+// dbusError converts an error returned by a Handler to the D-Bus error sent
+// to the client.
+func dbusError(err error) *dbus.Error {
+	if err == nil {
+		return nil
+	}
+	if e, ok := err.(*dbus.Error); ok {
+		return e
+	}
+	return dbus.MakeFailedError(err)
+}
+
+// rootObject answers the methods of the base interface.
+type rootObject struct{ f *FakePlayer }
+
+func (o rootObject) Raise() *dbus.Error { return o.f.call("Raise") }
+func (o rootObject) Quit() *dbus.Error  { return o.f.call("Quit") }
+
+// playerObject answers the methods of the player interface.
+type playerObject struct{ f *FakePlayer }
+
+func (o playerObject) Next() *dbus.Error      { return o.f.call("Next") }
+func (o playerObject) Previous() *dbus.Error  { return o.f.call("Previous") }
+func (o playerObject) Pause() *dbus.Error     { return o.f.call("Pause") }
+func (o playerObject) PlayPause() *dbus.Error { return o.f.call("PlayPause") }
+func (o playerObject) Stop() *dbus.Error      { return o.f.call("Stop") }
+func (o playerObject) Play() *dbus.Error      { return o.f.call("Play") }
+
+// SeekBy answers Seek, which can't be its name because of io.Seeker.
+func (o playerObject) SeekBy(offset int64) *dbus.Error {
+	return o.f.call("Seek", offset)
+}
+
+func (o playerObject) SetPosition(trackID dbus.ObjectPath, position int64) *dbus.Error {
+	return o.f.call("SetPosition", trackID, position)
+}
+
+// OpenUri answers OpenUri.
+//
+//revive:disable-next-line:var-naming
+func (o playerObject) OpenUri(uri string) *dbus.Error {
+	return o.f.call("OpenUri", uri)
+}
+
+// properties answers org.freedesktop.DBus.Properties for the fake.
+type properties struct{ f *FakePlayer }
+
+// Get implements org.freedesktop.DBus.Properties.Get.
+func (p properties) Get(iface, name string) (dbus.Variant, *dbus.Error) {
+	p.f.wait(name)
+	p.f.mu.Lock()
+	defer p.f.mu.Unlock()
+	v, ok := p.f.props[iface][name]
+	if !ok {
+		return dbus.Variant{}, unknownProperty(iface, name)
+	}
+	return p.f.quirky(name, v), nil
+}
+
+// unknownProperty returns the error for reading or setting a property the
+// fake doesn't have.
+func unknownProperty(iface, name string) *dbus.Error {
+	return dbus.NewError(
+		"org.freedesktop.DBus.Error.UnknownProperty",
+		[]any{"Unknown property " + iface + "." + name},
+	)
+}
+
+// GetAll implements org.freedesktop.DBus.Properties.GetAll.
+func (p properties) GetAll(iface string) (map[string]dbus.Variant, *dbus.Error) {
+	p.f.mu.Lock()
+	names := slices.Collect(maps.Keys(p.f.props[iface]))
+	p.f.mu.Unlock()
+	p.f.wait(names...)
+
+	p.f.mu.Lock()
+	defer p.f.mu.Unlock()
+	if p.f.hasQuirk(FailingGetAll) {
+		return nil, dbus.MakeFailedError(errors.New("GetAll is not supported"))
+	}
+	values := map[string]dbus.Variant{}
+	for name, v := range p.f.props[iface] {
+		values[name] = p.f.quirky(name, v)
+	}
+	return values, nil
+}
+
+// Set implements org.freedesktop.DBus.Properties.Set. The value is stored and
+// announced unless the property is dropped or read-only, or the handler of
+// "Set" fails.
+func (p properties) Set(iface, name string, v dbus.Variant) *dbus.Error {
+	p.f.mu.Lock()
+	dropped, readOnly := p.f.dropped[name], p.f.readOnly[name]
+	p.f.mu.Unlock()
+	switch {
+	case dropped:
+		return unknownProperty(iface, name)
+	case readOnly:
+		return dbus.NewError(
+			"org.freedesktop.DBus.Error.PropertyReadOnly",
+			[]any{"Property " + iface + "." + name + " is read-only"},
+		)
+	}
+	if err := p.f.call("Set", iface, name, v.Value()); err != nil {
+		return err
+	}
+	return dbusError(p.f.set(iface, name, v))
+}
+
+// methods lists the methods the fake answers by interface.
+var methods = map[string][]string{
+	mpris.BaseInterface: {"Raise", "Quit"},
+	mpris.PlayerInterface: {
+		"Next", "Previous", "Pause", "PlayPause", "Stop", "Play",
+		"Seek", "SetPosition", "OpenUri",
+	},
+}
+
+// introspectable answers org.freedesktop.DBus.Introspectable for the fake,
+// describing the interfaces and properties it currently has, so dropped
+// properties aren't listed.
+type introspectable struct{ f *FakePlayer }
+
+// Introspect implements org.freedesktop.DBus.Introspectable.Introspect.
+func (o introspectable) Introspect() (string, *dbus.Error) {
+	node := introspect.Node{Interfaces: []introspect.Interface{
+		introspect.IntrospectData,
+		prop.IntrospectData,
+	}}
+	o.f.mu.Lock()
+	for _, name := range slices.Sorted(maps.Keys(o.f.props)) {
+		iface := introspect.Interface{Name: name}
+		for _, method := range methods[name] {
+			iface.Methods = append(iface.Methods, introspect.Method{Name: method})
+		}
+		for _, property := range slices.Sorted(maps.Keys(o.f.props[name])) {
+			access := "readwrite"
+			if o.f.readOnly[property] {
+				access = "read"
+			}
+			iface.Properties = append(iface.Properties, introspect.Property{
+				Name:   property,
+				Type:   o.f.props[name][property].Signature().String(),
+				Access: access,
+			})
+		}
+		node.Interfaces = append(node.Interfaces, iface)
+	}
+	o.f.mu.Unlock()
+
+	data, err := xml.Marshal(node)
+	if err != nil {
+		return "", dbus.MakeFailedError(err)
+	}
+	return string(data), nil
+}
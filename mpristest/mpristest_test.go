@@ -0,0 +1,75 @@
+package mpristest
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Nadim147c/go-mpris"
+)
+
+func TestFakePlayerCalls(t *testing.T) {
+	fake, player := NewFakePlayer(t)
+	fake.Handle("Play", func(f *FakePlayer, _ []any) error {
+		f.SetPlayer("PlaybackStatus", "Playing")
+		return nil
+	})
+	fake.Handle("Next", func(*FakePlayer, []any) error {
+		return errors.New("end of playlist")
+	})
+
+	if err := player.Play(); err != nil {
+		t.Fatal(err)
+	}
+	if status, _ := player.GetPlaybackStatus(); status != mpris.PlaybackPlaying {
+		t.Errorf("status = %q after Play, want Playing", status)
+	}
+	if err := player.Seek(2 * time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := player.Next(); err == nil {
+		t.Error("Next() succeeded although its handler fails")
+	}
+	if err := player.SetVolume(0.5); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"Play", "Seek", "Next", "Set"}
+	if got := fake.Methods(); !slices.Equal(got, want) {
+		t.Errorf("Methods() = %q, want %q", got, want)
+	}
+	if seek := fake.Calls()[1]; seek.Args[0] != int64(2_000_000) {
+		t.Errorf("Seek args = %v, want the offset in microseconds", seek.Args)
+	}
+	if v, _ := fake.Get(mpris.PlayerInterface, "Volume"); v.Value() != 0.5 {
+		t.Errorf("Volume = %v after SetVolume(0.5)", v)
+	}
+
+	fake.Reset()
+	if calls := fake.Calls(); len(calls) != 0 {
+		t.Errorf("Calls() = %v after Reset", calls)
+	}
+}
+
+func TestFakePlayerAnnounces(t *testing.T) {
+	fake, player := NewFakePlayer(t)
+	changed := make(chan float64, 1)
+	sub, err := player.Subscribe(t.Context(), mpris.VolumeChanged(
+		func(v float64) { changed <- v },
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	fake.SetPlayer("Volume", 0.75)
+	select {
+	case v := <-changed:
+		if v != 0.75 {
+			t.Errorf("announced volume = %v, want 0.75", v)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Volume change not announced")
+	}
+}
@@ -0,0 +1,188 @@
+package mpristest
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Nadim147c/go-mpris"
+	"github.com/godbus/dbus/v5"
+)
+
+// Quirk is a way in which real players deviate from the MPRIS spec, which a
+// FakePlayer imitates once it is set with SetQuirk.
+type Quirk int
+
+//revive:disable:exported
+
+const (
+	// LengthAsUint64 sends mpris:length as a uint64 instead of an int64.
+	LengthAsUint64 Quirk = iota + 1
+	// ArtistAsString sends xesam:artist and xesam:albumArtist as a single
+	// comma separated string instead of a list.
+	ArtistAsString
+	// TrackIDAsString sends mpris:trackid as a string instead of an object
+	// path.
+	TrackIDAsString
+	// SeekedAsInt32 sends the position of Seeked as an int32.
+	SeekedAsInt32
+	// InvalidatesOnly announces changed properties in the invalidated list
+	// of PropertiesChanged, without their value.
+	InvalidatesOnly
+	// FailingGetAll fails GetAll with org.freedesktop.DBus.Error.Failed, so
+	// the properties can only be read one by one with Get.
+	FailingGetAll
+)
+
+//revive:enable:exported
+
+// Preset is the set of quirks of a real player.
+type Preset struct {
+	Identity     string
+	DesktopEntry string
+	Quirks       []Quirk
+	// Dropped lists the properties the player doesn't implement.
+	Dropped []string
+	// ReadOnly lists the properties the player doesn't allow to be set.
+	ReadOnly []string
+}
+
+// Presets holds the presets of the players the quirks were seen in, by the
+// name of the player.
+var Presets = map[string]Preset{
+	"spotify": {
+		Identity:     "Spotify",
+		DesktopEntry: "spotify",
+		Quirks:       []Quirk{LengthAsUint64, TrackIDAsString},
+	},
+	"chromium": {
+		Identity:     "Chromium",
+		DesktopEntry: "chromium-browser",
+		Dropped:      []string{"LoopStatus", "Shuffle", "Rate"},
+	},
+	"firefox": {
+		Identity:     "Mozilla Firefox",
+		DesktopEntry: "firefox",
+		Dropped:      []string{"LoopStatus", "Shuffle"},
+		ReadOnly:     []string{"Rate"},
+	},
+	"vlc": {
+		Identity:     "VLC media player",
+		DesktopEntry: "vlc",
+		Quirks:       []Quirk{SeekedAsInt32},
+	},
+	"mpv": {
+		Identity:     "mpv Media Player",
+		DesktopEntry: "mpv",
+		Quirks:       []Quirk{ArtistAsString, TrackIDAsString},
+	},
+}
+
+// SetQuirk makes the fake imitate quirk, including for properties set
+// before.
+func (f *FakePlayer) SetQuirk(quirk Quirk) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.quirks[quirk] = true
+}
+
+// ClearQuirk stops imitating quirk.
+func (f *FakePlayer) ClearQuirk(quirk Quirk) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.quirks, quirk)
+}
+
+// hasQuirk reports whether the fake imitates quirk. f.mu must be held.
+func (f *FakePlayer) hasQuirk(quirk Quirk) bool {
+	return f.quirks[quirk]
+}
+
+// Delay delays the answers to calls of the method name, or reads of the
+// property name, by d. A zero d removes the delay.
+func (f *FakePlayer) Delay(name string, d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if d == 0 {
+		delete(f.delays, name)
+		return
+	}
+	f.delays[name] = d
+}
+
+// wait sleeps for the longest delay of names.
+func (f *FakePlayer) wait(names ...string) {
+	var d time.Duration
+	f.mu.Lock()
+	for _, name := range names {
+		d = max(d, f.delays[name])
+	}
+	f.mu.Unlock()
+	time.Sleep(d)
+}
+
+// DropProperty removes the property name from every interface, so reading or
+// setting it fails with org.freedesktop.DBus.Error.UnknownProperty. Setting
+// it with Set later has no effect.
+func (f *FakePlayer) DropProperty(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.dropped[name] = true
+	for _, values := range f.props {
+		delete(values, name)
+	}
+}
+
+// SetReadOnly makes the property name of every interface read-only, so
+// setting it fails with org.freedesktop.DBus.Error.PropertyReadOnly. The test
+// can still change it with Set.
+func (f *FakePlayer) SetReadOnly(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.readOnly[name] = true
+}
+
+// Imitate sets the identity, quirks, missing and read-only properties of
+// preset.
+func (f *FakePlayer) Imitate(preset Preset) {
+	f.t.Helper()
+	for _, quirk := range preset.Quirks {
+		f.SetQuirk(quirk)
+	}
+	for _, name := range preset.Dropped {
+		f.DropProperty(name)
+	}
+	for _, name := range preset.ReadOnly {
+		f.SetReadOnly(name)
+	}
+	f.Set(mpris.BaseInterface, "Identity", preset.Identity)
+	f.Set(mpris.BaseInterface, "DesktopEntry", preset.DesktopEntry)
+}
+
+// quirky returns v, the value of the property name, as sent by a player with
+// the quirks of the fake. f.mu must be held.
+func (f *FakePlayer) quirky(name string, v dbus.Variant) dbus.Variant {
+	m, ok := v.Value().(map[string]dbus.Variant)
+	if name != "Metadata" || !ok {
+		return v
+	}
+	quirked := make(map[string]dbus.Variant, len(m))
+	for key, value := range m {
+		quirked[key] = value
+		switch x := value.Value().(type) {
+		case int64:
+			if key == "mpris:length" && f.hasQuirk(LengthAsUint64) {
+				quirked[key] = dbus.MakeVariant(uint64(x))
+			}
+		case []string:
+			isArtist := key == "xesam:artist" || key == "xesam:albumArtist"
+			if isArtist && f.hasQuirk(ArtistAsString) {
+				quirked[key] = dbus.MakeVariant(strings.Join(x, ", "))
+			}
+		case dbus.ObjectPath:
+			if key == "mpris:trackid" && f.hasQuirk(TrackIDAsString) {
+				quirked[key] = dbus.MakeVariant(string(x))
+			}
+		}
+	}
+	return dbus.MakeVariant(quirked)
+}
@@ -0,0 +1,253 @@
+package mpris
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// EventKind is the kind of an Event.
+type EventKind int
+
+//revive:disable:exported
+
+const (
+	EventPlayerAdded EventKind = iota
+	EventPlayerRemoved
+	EventPropertiesChanged
+	EventSeeked
+)
+
+//revive:enable:exported
+
+// String returns the name of the event kind.
+func (k EventKind) String() string {
+	switch k {
+	case EventPlayerAdded:
+		return "PlayerAdded"
+	case EventPlayerRemoved:
+		return "PlayerRemoved"
+	case EventPropertiesChanged:
+		return "PropertiesChanged"
+	case EventSeeked:
+		return "Seeked"
+	default:
+		return "Unknown"
+	}
+}
+
+// Event is something that happened to one of the players on the bus.
+type Event struct {
+	// Player is the bus name of the player the event belongs to.
+	Player string
+	// Kind is the kind of the event.
+	Kind EventKind
+	// Interface is the interface whose properties changed. It is only set for
+	// EventPropertiesChanged.
+	Interface string
+	// Changed holds the new values of the changed properties. Properties the
+	// player only reported as invalidated are fetched and included. It is
+	// only set for EventPropertiesChanged and owned by the receiver.
+	Changed map[string]dbus.Variant
+	// Position is the new playback position. It is only set for EventSeeked.
+	Position time.Duration
+	// Stamp tells when and in which order the signal behind the event was
+	// observed.
+	Stamp
+}
+
+const (
+	busName                = "org.freedesktop.DBus"
+	nameOwnerChangedSignal = busName + ".NameOwnerChanged"
+	seekedSignal           = PlayerInterface + ".Seeked"
+)
+
+// playerRules are the match rules used to watch every player on the bus. There
+// is one rule per signal type, no matter how many players there are.
+var playerRules = [][]dbus.MatchOption{
+	{
+		dbus.WithMatchSender(busName),
+		dbus.WithMatchInterface(busName),
+		dbus.WithMatchMember("NameOwnerChanged"),
+		dbus.WithMatchArg0Namespace(BaseInterface),
+	},
+	{
+		dbus.WithMatchObjectPath(DBusObjectPath),
+		dbus.WithMatchInterface("org.freedesktop.DBus.Properties"),
+		dbus.WithMatchMember("PropertiesChanged"),
+	},
+	{
+		dbus.WithMatchObjectPath(DBusObjectPath),
+		dbus.WithMatchInterface(PlayerInterface),
+		dbus.WithMatchMember("Seeked"),
+	},
+}
+
+// multiplexer merges the signals of every player on a connection into one
+// stream of events. Its state is only touched from the subscription goroutine.
+type multiplexer struct {
+	conn *dbus.Conn
+	ch   chan<- Event
+	// owners maps the bus name of every known player to its unique name.
+	owners map[string]string
+}
+
+// isPlayerName returns whether name is an MPRIS bus name.
+func isPlayerName(name string) bool {
+	return strings.HasPrefix(name, BaseInterface+".")
+}
+
+func (m *multiplexer) filter(sig *dbus.Signal) bool {
+	switch sig.Name {
+	case nameOwnerChangedSignal:
+		return sig.Sender == busName
+	case PropertiesChangedSignal, seekedSignal:
+		return sig.Path == DBusObjectPath
+	default:
+		return false
+	}
+}
+
+func (m *multiplexer) send(ctx context.Context, e Event) {
+	select {
+	case m.ch <- e:
+	case <-ctx.Done():
+	}
+}
+
+func (m *multiplexer) handle(ctx context.Context, sig *dbus.Signal) {
+	switch sig.Name {
+	case nameOwnerChangedSignal:
+		m.ownerChanged(ctx, sig)
+	case PropertiesChangedSignal:
+		pc, ok := parsePropertiesChanged(sig)
+		if !ok {
+			return
+		}
+		for _, name := range m.namesOf(sig.Sender) {
+			player := New(m.conn, name)
+			resolved := player.resolveInvalidated(ctx, pc, pc.invalidated)
+			changed := make(map[string]dbus.Variant, len(resolved.changed))
+			for k, v := range resolved.changed {
+				changed[k] = cloneVariant(v)
+			}
+			m.send(ctx, Event{
+				Player:    name,
+				Kind:      EventPropertiesChanged,
+				Interface: pc.iface,
+				Changed:   changed,
+				Stamp:     stampOf(ctx),
+			})
+		}
+	case seekedSignal:
+		position, ok := decodeSeeked(sig)
+		if !ok {
+			return
+		}
+		for _, name := range m.namesOf(sig.Sender) {
+			m.send(ctx, Event{
+				Player:   name,
+				Kind:     EventSeeked,
+				Position: position,
+				Stamp:    stampOf(ctx),
+			})
+		}
+	}
+}
+
+func (m *multiplexer) ownerChanged(ctx context.Context, sig *dbus.Signal) {
+	var name, oldOwner, newOwner string
+	if err := dbus.Store(sig.Body, &name, &oldOwner, &newOwner); err != nil {
+		return
+	}
+	if !isPlayerName(name) {
+		return
+	}
+
+	known, ok := m.owners[name]
+	switch {
+	case newOwner == "":
+		if !ok {
+			return
+		}
+		delete(m.owners, name)
+		m.send(ctx, Event{
+			Player: name,
+			Kind:   EventPlayerRemoved,
+			Stamp:  stampOf(ctx),
+		})
+	case ok && known == newOwner:
+		// Already known, e.g. both listed and announced on startup.
+	default:
+		m.owners[name] = newOwner
+		if !ok {
+			m.send(ctx, Event{
+				Player: name,
+				Kind:   EventPlayerAdded,
+				Stamp:  stampOf(ctx),
+			})
+		}
+	}
+}
+
+// namesOf returns the player names owned by the unique name owner, sorted.
+func (m *multiplexer) namesOf(owner string) []string {
+	var names []string
+	for name, o := range m.owners {
+		if o == owner {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// WatchPlayers tracks every MPRIS player on the bus, including the ones that
+// appear later, and sends the events of all of them to ch until the returned
+// Subscription is closed. Every event is tagged with the bus name of its
+// player. Players already on the bus are reported with EventPlayerAdded first.
+//
+// No matter how many players there are, WatchPlayers adds one match rule per
+// signal type to the bus.
+func WatchPlayers(conn *dbus.Conn, ch chan<- Event) (*Subscription, error) {
+	m := &multiplexer{conn: conn, ch: ch, owners: map[string]string{}}
+	sub := newSubscription(m.filter, m.handle)
+	// Subscribe before listing the names, so a player appearing in between
+	// is not missed. Duplicates are ignored by ownerChanged.
+	if err := sub.start(connTransport{conn}, playerRules...); err != nil {
+		return nil, err
+	}
+
+	names, err := List(conn)
+	if err != nil {
+		sub.Close()
+		return nil, err
+	}
+	for _, name := range names {
+		owner, err := New(conn, name).getOwner()
+		if err != nil {
+			continue
+		}
+		// Report existing players through the subscription goroutine, so
+		// they are ordered with the signals that follow.
+		sub.push(&dbus.Signal{
+			Sender: busName,
+			Name:   nameOwnerChangedSignal,
+			Body:   []any{name, "", owner},
+		})
+	}
+	return sub, nil
+}
+
+// OnPlayers tracks every MPRIS player on the bus and sends the events of all of
+// them to ch until ctx is canceled. See WatchPlayers.
+func OnPlayers(ctx context.Context, conn *dbus.Conn, ch chan<- Event) error {
+	sub, err := WatchPlayers(conn, ch)
+	if err != nil {
+		return err
+	}
+	return sub.wait(ctx)
+}
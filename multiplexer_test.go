@@ -0,0 +1,75 @@
+package mpris
+
+import (
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// claimName opens a new connection to addr owning name.
+func claimName(t *testing.T, addr, name string) *dbus.Conn {
+	t.Helper()
+	conn := testConn(t, addr)
+	reply, err := conn.RequestName(name, dbus.NameFlagDoNotQueue)
+	if err != nil || reply != dbus.RequestNameReplyPrimaryOwner {
+		t.Fatalf("Could not claim %s: %v", name, err)
+	}
+	return conn
+}
+
+func TestWatchPlayers(t *testing.T) {
+	addr := testBusAddress(t)
+	first := claimName(t, addr, BaseInterface+".first")
+	client := testConn(t, addr)
+
+	events := make(chan Event, 10)
+	sub, err := WatchPlayers(client, events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	e := receive(t, events)
+	if e.Kind != EventPlayerAdded || e.Player != BaseInterface+".first" {
+		t.Fatalf("first event = %v %s, want PlayerAdded first", e.Kind, e.Player)
+	}
+
+	second := claimName(t, addr, BaseInterface+".second")
+	e = receive(t, events)
+	if e.Kind != EventPlayerAdded || e.Player != BaseInterface+".second" {
+		t.Fatalf("event = %v %s, want PlayerAdded second", e.Kind, e.Player)
+	}
+
+	emitPropertiesChanged(t, second, PlayerInterface, map[string]dbus.Variant{
+		"Volume": dbus.MakeVariant(0.25),
+	})
+	e = receive(t, events)
+	if e.Kind != EventPropertiesChanged || e.Player != BaseInterface+".second" {
+		t.Fatalf("event = %v %s, want PropertiesChanged second", e.Kind, e.Player)
+	}
+	if e.Interface != PlayerInterface || e.Changed["Volume"].Value() != 0.25 {
+		t.Errorf("event carries %s %v", e.Interface, e.Changed)
+	}
+
+	err = first.Emit(DBusObjectPath, PlayerInterface+".Seeked", int64(1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e = receive(t, events)
+	if e.Kind != EventSeeked || e.Player != BaseInterface+".first" {
+		t.Fatalf("event = %v %s, want Seeked first", e.Kind, e.Player)
+	}
+
+	// Signals from connections that don't own a player name are ignored.
+	stranger := testConn(t, addr)
+	emitPropertiesChanged(t, stranger, PlayerInterface, map[string]dbus.Variant{
+		"Volume": dbus.MakeVariant(0.5),
+	})
+	expectNothing(t, events)
+
+	second.Close()
+	e = receive(t, events)
+	if e.Kind != EventPlayerRemoved || e.Player != BaseInterface+".second" {
+		t.Fatalf("event = %v %s, want PlayerRemoved second", e.Kind, e.Player)
+	}
+}
@@ -0,0 +1,179 @@
+package mpris
+
+import (
+	"context"
+	"path"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/spf13/cast"
+)
+
+// splitInstance splits the part of busName after the MPRIS prefix into the
+// application name and the instance suffix. Two suffixes are recognized: a
+// last element starting with "instance", as in
+// org.mpris.MediaPlayer2.chromium.instance1234, and a "-<pid>" suffix as VLC
+// sometimes uses.
+func splitInstance(busName string) (base, instance string, ok bool) {
+	base = strings.TrimPrefix(busName, BaseInterface+".")
+	if i := strings.LastIndexByte(base, '.'); i >= 0 &&
+		strings.HasPrefix(base[i+1:], "instance") {
+		return base[:i], base[i+1:], true
+	}
+	if i := strings.LastIndexByte(base, '-'); i >= 0 && isDigits(base[i+1:]) {
+		return base[:i], base[i+1:], true
+	}
+	return base, "", false
+}
+
+// isDigits returns whether s is a non-empty string of ASCII digits.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// BaseName returns the application part of an MPRIS bus name, without the
+// org.mpris.MediaPlayer2 prefix and any instance suffix. Both
+// org.mpris.MediaPlayer2.chromium.instance1234 and org.mpris.MediaPlayer2.vlc-42
+// give the application name, "chromium" and "vlc".
+func BaseName(busName string) string {
+	base, _, _ := splitInstance(busName)
+	return base
+}
+
+// InstanceID returns the instance suffix of an MPRIS bus name, such as
+// "instance1234" for org.mpris.MediaPlayer2.chromium.instance1234 or "42" for
+// org.mpris.MediaPlayer2.vlc-42, and whether there is one.
+func InstanceID(busName string) (string, bool) {
+	_, instance, ok := splitInstance(busName)
+	return instance, ok
+}
+
+// ShortName returns the application part of the player's bus name. See
+// BaseName.
+func (i *Player) ShortName() string {
+	return BaseName(i.name)
+}
+
+// Instance returns the instance suffix of the player's bus name and whether
+// there is one. See InstanceID.
+func (i *Player) Instance() (string, bool) {
+	return InstanceID(i.name)
+}
+
+// displayNameTimeout bounds reading the properties DisplayName is made of, so
+// a hung player doesn't hang its caller too.
+const displayNameTimeout = time.Second
+
+// DisplayName returns a human-readable name for the player: its Identity, or
+// else its DesktopEntry made readable, such as "Chromium Browser" for
+// chromium-browser, or else its bus name without prefix and instance suffix,
+// made readable the same way. The name read from the player is cached, so
+// only the first call goes to the bus.
+func (i *Player) DisplayName() string {
+	i.mu.Lock()
+	name := i.displayName
+	i.mu.Unlock()
+	if name != "" {
+		return name
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), displayNameTimeout)
+	defer cancel()
+	base, err := i.GetAllPropertiesContext(ctx, BaseInterface)
+	if err != nil {
+		return prettyName(i.ShortName())
+	}
+	name, _ = cast.ToStringE(base["Identity"].Value())
+	name = strings.TrimSpace(name)
+	if name == "" {
+		entry, _ := cast.ToStringE(base["DesktopEntry"].Value())
+		name = prettyName(entry)
+	}
+	if name == "" {
+		name = prettyName(i.ShortName())
+	}
+	i.mu.Lock()
+	i.displayName = name
+	i.mu.Unlock()
+	return name
+}
+
+// String returns the display name and the bus name of the player, such as
+// "VLC media player (org.mpris.MediaPlayer2.vlc)", for logs. It never goes to
+// the bus, so logging a hung player doesn't block: until DisplayName read the
+// name from the player, the bus name is made readable instead, such as "Vlc".
+func (i *Player) String() string {
+	i.mu.Lock()
+	name := i.displayName
+	i.mu.Unlock()
+	if name == "" {
+		name = prettyName(i.ShortName())
+	}
+	return name + " (" + i.name + ")"
+}
+
+// prettyName makes a desktop entry or application name readable: the path
+// and .desktop suffix of an entry and the reverse domain of an ID such as
+// org.gnome.Rhythmbox3 are dropped, dashes and underscores become spaces and
+// every word is capitalized.
+func prettyName(name string) string {
+	name = strings.TrimSuffix(path.Base(strings.TrimSpace(name)), ".desktop")
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return r == '-' || r == '_' || unicode.IsSpace(r)
+	})
+	for n, word := range words {
+		r, size := utf8.DecodeRuneInString(word)
+		words[n] = string(unicode.ToUpper(r)) + word[size:]
+	}
+	return strings.Join(words, " ")
+}
+
+// ListUnique is like List, but returns a single bus name per application, as
+// grouped by BaseName. A name without instance suffix is preferred, otherwise
+// the first one in sorted order is used. The result is sorted.
+func ListUnique(conn *dbus.Conn) ([]string, error) {
+	names, err := List(conn)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		_, _, aInstance := splitInstance(a)
+		_, _, bInstance := splitInstance(b)
+		switch {
+		case aInstance == bInstance:
+			return strings.Compare(a, b)
+		case aInstance:
+			return 1
+		default:
+			return -1
+		}
+	})
+
+	seen := map[string]bool{}
+	var unique []string
+	for _, name := range names {
+		base := BaseName(name)
+		if seen[base] {
+			continue
+		}
+		seen[base] = true
+		unique = append(unique, name)
+	}
+	slices.Sort(unique)
+	return unique, nil
+}
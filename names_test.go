@@ -0,0 +1,127 @@
+package mpris
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBaseNameAndInstanceID(t *testing.T) {
+	tests := []struct {
+		busName  string
+		base     string
+		instance string
+	}{
+		{BaseInterface + ".spotify", "spotify", ""},
+		{BaseInterface + ".chromium.instance1234", "chromium", "instance1234"},
+		{BaseInterface + ".firefox.instance_1_88", "firefox", "instance_1_88"},
+		{BaseInterface + ".vlc-5678", "vlc", "5678"},
+		{BaseInterface + ".io.github.quodlibet.QuodLibet", "io.github.quodlibet.QuodLibet", ""},
+		{BaseInterface + ".kde-connect", "kde-connect", ""},
+		{"spotify", "spotify", ""},
+	}
+	for _, tt := range tests {
+		if got := BaseName(tt.busName); got != tt.base {
+			t.Errorf("BaseName(%q) = %q, want %q", tt.busName, got, tt.base)
+		}
+		instance, ok := InstanceID(tt.busName)
+		if instance != tt.instance || ok != (tt.instance != "") {
+			t.Errorf("InstanceID(%q) = %q, %v, want %q", tt.busName, instance, ok, tt.instance)
+		}
+
+		player := &Player{name: tt.busName}
+		if got := player.ShortName(); got != tt.base {
+			t.Errorf("ShortName() of %q = %q, want %q", tt.busName, got, tt.base)
+		}
+		if got, _ := player.Instance(); got != tt.instance {
+			t.Errorf("Instance() of %q = %q, want %q", tt.busName, got, tt.instance)
+		}
+	}
+}
+
+func TestListUnique(t *testing.T) {
+	addr := testBusAddress(t)
+	for _, name := range []string{
+		".chromium.instance2",
+		".chromium.instance1",
+		".vlc",
+		".vlc-42",
+		".spotify",
+	} {
+		claimName(t, addr, BaseInterface+name)
+	}
+
+	got, err := ListUnique(testConn(t, addr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		BaseInterface + ".chromium.instance1",
+		BaseInterface + ".spotify",
+		BaseInterface + ".vlc",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("ListUnique() = %q, want %q", got, want)
+	}
+}
+
+func TestDisplayName(t *testing.T) {
+	addr := testBusAddress(t)
+	client := testConn(t, addr)
+	vlc := addTestPlayer(t, addr, BaseInterface+".vlc", map[string]map[string]any{
+		BaseInterface: {"Identity": "VLC media player", "DesktopEntry": "vlc"},
+	})
+	addTestPlayer(t, addr, BaseInterface+".chromium.instance1", map[string]map[string]any{
+		BaseInterface: {"Identity": " ", "DesktopEntry": "chromium-browser"},
+	})
+	addTestPlayer(t, addr, BaseInterface+".rhythmbox", map[string]map[string]any{
+		BaseInterface: {"DesktopEntry": "org.gnome.Rhythmbox3"},
+	})
+	claimName(t, addr, BaseInterface+".kde_connect.instance42")
+
+	tests := []struct {
+		busName string
+		want    string
+	}{
+		{BaseInterface + ".vlc", "VLC media player"},
+		{BaseInterface + ".chromium.instance1", "Chromium Browser"},
+		{BaseInterface + ".rhythmbox", "Rhythmbox3"},
+		{BaseInterface + ".kde_connect.instance42", "Kde Connect"},
+	}
+	for _, tt := range tests {
+		player := New(client, tt.busName)
+		want := prettyName(player.ShortName()) + " (" + tt.busName + ")"
+		if got := player.String(); got != want {
+			t.Errorf("String() of %q before DisplayName = %q, want %q", tt.busName, got, want)
+		}
+		if got := player.DisplayName(); got != tt.want {
+			t.Errorf("DisplayName() of %q = %q, want %q", tt.busName, got, tt.want)
+		}
+		want = tt.want + " (" + tt.busName + ")"
+		if got := player.String(); got != want {
+			t.Errorf("String() of %q = %q, want %q", tt.busName, got, want)
+		}
+	}
+
+	player := New(client, BaseInterface+".vlc")
+	player.DisplayName()
+	vlc.Close()
+	if got := player.DisplayName(); got != "VLC media player" {
+		t.Errorf("DisplayName() after the player left = %q, want the cached name", got)
+	}
+}
+
+func TestPrettyName(t *testing.T) {
+	tests := map[string]string{
+		"chromium-browser":                      "Chromium Browser",
+		"org.gnome.Rhythmbox3":                  "Rhythmbox3",
+		"/usr/share/applications/vlc.desktop":   "Vlc",
+		"io.github.quodlibet.QuodLibet.desktop": "QuodLibet",
+		"kde_connect":                           "Kde Connect",
+		"":                                      "",
+	}
+	for name, want := range tests {
+		if got := prettyName(name); got != want {
+			t.Errorf("prettyName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
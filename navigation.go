@@ -0,0 +1,334 @@
+package mpris
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/spf13/cast"
+)
+
+// skipStepTimeout bounds how long NextN and PreviousN wait for the track to
+// change after each Next or Previous.
+const skipStepTimeout = 2 * time.Second
+
+// restartable returns the ID of the current track when the player can move
+// back to its start, that is when CanSeek is true and a track is loaded. A
+// player without CanSeek can't.
+func (i *Player) restartable(ctx context.Context) (dbus.ObjectPath, bool, error) {
+	can, err := i.CanSeekContext(ctx)
+	if errors.Is(missingAsNotSupported(err), ErrNotSupported) {
+		return "", false, nil
+	}
+	if err != nil || !can {
+		return "", false, err
+	}
+	m, err := i.GetMetadataContext(ctx)
+	if err != nil {
+		return "", false, err
+	}
+	trackID, err := m.GetObjectPath("mpris:trackid")
+	if err != nil || trackID == NoTrack {
+		return "", false, nil
+	}
+	return trackID, true, nil
+}
+
+// RestartTrack moves the playback position to the start of the current track.
+// Players that can't seek, or that have no track loaded, are sent to the
+// previous track instead, which is the closest they can get.
+func (i *Player) RestartTrack() error {
+	return i.RestartTrackContext(context.Background())
+}
+
+// RestartTrackContext is like RestartTrack but takes a context.
+func (i *Player) RestartTrackContext(ctx context.Context) error {
+	trackID, ok, err := i.restartable(ctx)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return i.PreviousContext(ctx)
+	}
+	return i.SetTrackPositionContext(ctx, &trackID, 0)
+}
+
+// PreviousOrRestart behaves like the previous button of most players: it
+// restarts the current track when more than threshold of it has been played,
+// and goes to the previous track otherwise. Players that can't seek, or that
+// have no track loaded, always go to the previous track.
+func (i *Player) PreviousOrRestart(threshold time.Duration) error {
+	return i.PreviousOrRestartContext(context.Background(), threshold)
+}
+
+// PreviousOrRestartContext is like PreviousOrRestart but takes a context.
+func (i *Player) PreviousOrRestartContext(
+	ctx context.Context,
+	threshold time.Duration,
+) error {
+	trackID, ok, err := i.restartable(ctx)
+	if err != nil {
+		return err
+	}
+	if ok {
+		position, err := i.GetPositionContext(ctx)
+		if err != nil {
+			return err
+		}
+		if position > threshold {
+			return i.SetTrackPositionContext(ctx, &trackID, 0)
+		}
+	}
+	return i.PreviousContext(ctx)
+}
+
+// SkipError is returned by NextN and PreviousN when they stop before skipping
+// all the tracks they were asked to.
+type SkipError struct {
+	// Skipped is the number of tracks skipped before stopping.
+	Skipped int
+	// Requested is the number of tracks that were to be skipped.
+	Requested int
+	// Err is the reason for stopping.
+	Err error
+}
+
+func (e *SkipError) Error() string {
+	return fmt.Sprintf("skipped %d of %d tracks: %v", e.Skipped, e.Requested, e.Err)
+}
+
+func (e *SkipError) Unwrap() error {
+	return e.Err
+}
+
+// NextN skips forward n tracks. Calling Next in a loop races with the player
+// switching tracks, so after every Next the track ID is waited for to change,
+// up to two seconds, before going on. When CanGoNext becomes false or the
+// track doesn't change in time, NextN stops with a *SkipError holding the
+// number of tracks skipped so far. The error wraps ErrNotAllowed in the first
+// case.
+func (i *Player) NextN(n int) error {
+	return i.NextNContext(context.Background(), n)
+}
+
+// NextNContext is like NextN but takes a context.
+func (i *Player) NextNContext(ctx context.Context, n int) error {
+	return i.skipN(ctx, n, "CanGoNext", i.NextContext)
+}
+
+// PreviousN is like NextN but skips back n tracks with Previous, stopping when
+// CanGoPrevious becomes false.
+func (i *Player) PreviousN(n int) error {
+	return i.PreviousNContext(context.Background(), n)
+}
+
+// PreviousNContext is like PreviousN but takes a context.
+func (i *Player) PreviousNContext(ctx context.Context, n int) error {
+	return i.skipN(ctx, n, "CanGoPrevious", i.PreviousContext)
+}
+
+// skipState is the part of the player state skipN follows.
+type skipState struct {
+	mu      sync.Mutex
+	trackID dbus.ObjectPath
+	allowed bool
+	// changed is signalled whenever trackID or allowed change.
+	changed chan struct{}
+}
+
+func (s *skipState) update(fn func()) {
+	s.mu.Lock()
+	fn()
+	s.mu.Unlock()
+	select {
+	case s.changed <- struct{}{}:
+	default:
+	}
+}
+
+func (s *skipState) get() (dbus.ObjectPath, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.trackID, s.allowed
+}
+
+// skipN calls skip n times, waiting for the track to change in between, as
+// long as the capability property is true.
+func (i *Player) skipN(
+	ctx context.Context,
+	n int,
+	capability string,
+	skip func(context.Context) error,
+) error {
+	if n < 0 {
+		return fmt.Errorf("%w: can't skip %d tracks", ErrOutOfRange, n)
+	}
+	if n == 0 {
+		return nil
+	}
+
+	state := &skipState{changed: make(chan struct{}, 1)}
+	// Subscribe before reading the state, so changes in between aren't
+	// missed.
+	sub, err := i.Subscribe(ctx,
+		MetadataChanged(func(m Metadata) {
+			trackID, _ := m.GetObjectPath("mpris:trackid")
+			state.update(func() { state.trackID = trackID })
+		}),
+		propertyChanged(PlayerInterface, capability, cast.ToBoolE,
+			func(_ context.Context, allowed bool) {
+				state.update(func() { state.allowed = allowed })
+			}),
+	)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	m, err := i.GetMetadataContext(ctx)
+	if err != nil {
+		return err
+	}
+	allowed, err := getPlayerPropertyCast(ctx, i, capability, cast.ToBoolE)
+	if err != nil {
+		return err
+	}
+	trackID, _ := m.GetObjectPath("mpris:trackid")
+	state.update(func() {
+		state.trackID = trackID
+		state.allowed = allowed
+	})
+
+	notAllowed := fmt.Errorf(
+		"%w: %s.%s is false",
+		ErrNotAllowed,
+		PlayerInterface,
+		capability,
+	)
+	for skipped := range n {
+		previous, allowed := state.get()
+		if !allowed {
+			return &SkipError{skipped, n, notAllowed}
+		}
+		if err := skip(ctx); err != nil {
+			return &SkipError{skipped, n, err}
+		}
+		if err := state.waitChange(ctx, previous); err != nil {
+			if _, allowed := state.get(); !allowed {
+				err = notAllowed
+			}
+			return &SkipError{skipped, n, err}
+		}
+	}
+	return nil
+}
+
+// waitChange waits up to skipStepTimeout for the track ID to differ from
+// previous.
+func (s *skipState) waitChange(
+	ctx context.Context,
+	previous dbus.ObjectPath,
+) error {
+	timer := time.NewTimer(skipStepTimeout)
+	defer timer.Stop()
+	for {
+		if trackID, _ := s.get(); trackID != previous {
+			return nil
+		}
+		select {
+		case <-s.changed:
+		case <-timer.C:
+			return fmt.Errorf("track didn't change within %v", skipStepTimeout)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+//revive:disable:exported
+
+// StopAction is what StopAfterCurrentWith does when the next track starts.
+type StopAction string
+
+const (
+	StopActionPause StopAction = "Pause"
+	StopActionStop  StopAction = "Stop"
+)
+
+//revive:enable:exported
+
+// StopAfterCurrent emulates the "single" mode of mpd: it waits for the current
+// track to end and pauses the player as soon as the next one starts. It
+// returns without acting when the player is paused or stopped before that,
+// and with ctx.Err() when ctx is done, which disarms it.
+func (i *Player) StopAfterCurrent(ctx context.Context) error {
+	return i.StopAfterCurrentWith(ctx, StopActionPause)
+}
+
+// StopAfterCurrentWith is like StopAfterCurrent but does action instead of
+// pausing.
+func (i *Player) StopAfterCurrentWith(ctx context.Context, action StopAction) error {
+	if action != StopActionPause && action != StopActionStop {
+		return fmt.Errorf("%w: stop action %q", ErrOutOfRange, action)
+	}
+
+	trackIDs := make(chan dbus.ObjectPath, 1)
+	halted := make(chan struct{}, 1)
+	// Subscribe before reading the current track, so a change in between
+	// isn't missed.
+	sub, err := i.Subscribe(ctx,
+		MetadataChanged(func(m Metadata) {
+			trackID, _ := m.GetObjectPath("mpris:trackid")
+			// Keep only the latest track ID.
+			select {
+			case <-trackIDs:
+			default:
+			}
+			trackIDs <- trackID
+		}),
+		PlaybackStatusChanged(func(s PlaybackStatus) {
+			if s == PlaybackPlaying {
+				return
+			}
+			select {
+			case halted <- struct{}{}:
+			default:
+			}
+		}),
+	)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	m, err := i.GetMetadataContext(ctx)
+	if err != nil {
+		return err
+	}
+	current, _ := m.GetObjectPath("mpris:trackid")
+	if playing, err := i.IsPlayingContext(ctx); err != nil || !playing {
+		return err
+	}
+
+	for {
+		select {
+		case trackID := <-trackIDs:
+			if trackID == current {
+				continue
+			}
+			return i.callContext(ctx, PlayerInterface+"."+string(action))
+		case <-halted:
+			return nil
+		case <-sub.Done():
+			if err := sub.Err(); err != nil {
+				return err
+			}
+			return ctx.Err()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
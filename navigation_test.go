@@ -0,0 +1,276 @@
+package mpris
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// navigatingPlayer implements SetPosition and Previous, counting the calls of
+// Previous.
+type navigatingPlayer struct {
+	props    *testProperties
+	previous atomic.Int32
+}
+
+func (p *navigatingPlayer) SetPosition(_ dbus.ObjectPath, position int64) *dbus.Error {
+	p.props.set(PlayerInterface, "Position", position)
+	return nil
+}
+
+func (p *navigatingPlayer) Previous() *dbus.Error {
+	p.previous.Add(1)
+	return nil
+}
+
+func TestRestartTrack(t *testing.T) {
+	tests := []struct {
+		name     string
+		canSeek  bool
+		trackID  dbus.ObjectPath
+		position time.Duration
+		// threshold is passed to PreviousOrRestart.
+		threshold time.Duration
+		// restart is whether PreviousOrRestart restarts the track.
+		restart bool
+	}{
+		{"seekable", true, "/track/1", 10 * time.Second, 3 * time.Second, true},
+		{"near start", true, "/track/1", time.Second, 3 * time.Second, false},
+		{"unseekable stream", false, "/track/1", 10 * time.Second, 3 * time.Second, false},
+		{"no track", true, NoTrack, 10 * time.Second, 3 * time.Second, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, player := testBus(t)
+			props := exportTestProperties(t, server, map[string]map[string]any{
+				PlayerInterface: {
+					"CanSeek":  tt.canSeek,
+					"Position": tt.position.Microseconds(),
+					"Metadata": map[string]dbus.Variant{
+						"mpris:trackid": dbus.MakeVariant(tt.trackID),
+					},
+				},
+			})
+			fake := &navigatingPlayer{props: props}
+			if err := server.Export(fake, DBusObjectPath, PlayerInterface); err != nil {
+				t.Fatal(err)
+			}
+
+			check := func(call string, restart bool) {
+				t.Helper()
+				position, _ := player.GetPosition()
+				previous := fake.previous.Swap(0)
+				if restart && (position != 0 || previous != 0) {
+					t.Errorf("%s: position = %v, Previous calls = %d, want restart",
+						call, position, previous)
+				}
+				if !restart && (position != tt.position || previous != 1) {
+					t.Errorf("%s: position = %v, Previous calls = %d, want Previous",
+						call, position, previous)
+				}
+				props.set(PlayerInterface, "Position", tt.position.Microseconds())
+			}
+
+			if err := player.PreviousOrRestart(tt.threshold); err != nil {
+				t.Fatal(err)
+			}
+			check("PreviousOrRestart()", tt.restart)
+
+			if err := player.RestartTrack(); err != nil {
+				t.Fatal(err)
+			}
+			check("RestartTrack()", tt.canSeek && tt.trackID != NoTrack)
+		})
+	}
+}
+
+// skippingPlayer implements Next and Previous over a list of tracks, updating
+// Metadata, CanGoNext and CanGoPrevious like a player at the end of a
+// playlist.
+type skippingPlayer struct {
+	props  *testProperties
+	tracks int
+
+	mu    sync.Mutex
+	index int
+}
+
+func exportSkippingPlayer(t *testing.T, server *dbus.Conn, tracks int) *skippingPlayer {
+	t.Helper()
+	p := &skippingPlayer{
+		props:  exportTestProperties(t, server, nil),
+		tracks: tracks,
+	}
+	p.move(0)
+	if err := server.Export(p, DBusObjectPath, PlayerInterface); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// move goes by delta tracks.
+func (p *skippingPlayer) move(delta int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.index += delta
+	p.props.set(PlayerInterface, "CanGoNext", p.index < p.tracks-1)
+	p.props.set(PlayerInterface, "CanGoPrevious", p.index > 0)
+	trackID := dbus.ObjectPath(fmt.Sprintf("/track/%d", p.index))
+	p.props.set(PlayerInterface, "Metadata", map[string]dbus.Variant{
+		"mpris:trackid": dbus.MakeVariant(trackID),
+	})
+}
+
+func (p *skippingPlayer) Next() *dbus.Error {
+	p.move(1)
+	return nil
+}
+
+func (p *skippingPlayer) Previous() *dbus.Error {
+	p.move(-1)
+	return nil
+}
+
+func (p *skippingPlayer) current() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.index
+}
+
+func TestSkipN(t *testing.T) {
+	server, player := testBus(t)
+	fake := exportSkippingPlayer(t, server, 4)
+
+	if err := player.NextN(2); err != nil {
+		t.Fatal(err)
+	}
+	if got := fake.current(); got != 2 {
+		t.Errorf("track after NextN(2) = %d, want 2", got)
+	}
+
+	err := player.NextN(3)
+	var skipErr *SkipError
+	if !errors.As(err, &skipErr) || !errors.Is(err, ErrNotAllowed) {
+		t.Fatalf("NextN(3) error = %v, want SkipError wrapping ErrNotAllowed", err)
+	}
+	if skipErr.Skipped != 1 || skipErr.Requested != 3 {
+		t.Errorf("SkipError = %+v, want 1 of 3 skipped", skipErr)
+	}
+	if got := fake.current(); got != 3 {
+		t.Errorf("track after NextN(3) = %d, want 3", got)
+	}
+
+	if err := player.PreviousN(3); err != nil {
+		t.Fatal(err)
+	}
+	if got := fake.current(); got != 0 {
+		t.Errorf("track after PreviousN(3) = %d, want 0", got)
+	}
+	if err := player.PreviousN(-1); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("PreviousN(-1) error = %v, want ErrOutOfRange", err)
+	}
+}
+
+// haltingPlayer is a skippingPlayer that can be paused and stopped.
+type haltingPlayer struct {
+	*skippingPlayer
+	halted chan string
+}
+
+func (p *haltingPlayer) Pause() *dbus.Error {
+	p.props.set(PlayerInterface, "PlaybackStatus", string(PlaybackPaused))
+	p.halted <- "Pause"
+	return nil
+}
+
+func (p *haltingPlayer) Stop() *dbus.Error {
+	p.props.set(PlayerInterface, "PlaybackStatus", string(PlaybackStopped))
+	p.halted <- "Stop"
+	return nil
+}
+
+// readNotifyingProperties reports the names of the properties read.
+type readNotifyingProperties struct {
+	*testProperties
+	read chan string
+}
+
+func (p *readNotifyingProperties) Get(iface, name string) (dbus.Variant, *dbus.Error) {
+	v, err := p.testProperties.Get(iface, name)
+	select {
+	case p.read <- name:
+	default:
+	}
+	return v, err
+}
+
+func TestStopAfterCurrent(t *testing.T) {
+	tests := []struct {
+		name   string
+		action StopAction
+		// interfere happens while StopAfterCurrent is armed.
+		interfere func(*haltingPlayer)
+		// want is the method called by StopAfterCurrent, if any.
+		want string
+	}{
+		{"pause", StopActionPause, func(p *haltingPlayer) { p.move(1) }, "Pause"},
+		{"stop", StopActionStop, func(p *haltingPlayer) { p.move(1) }, "Stop"},
+		{"paused manually", StopActionPause, func(p *haltingPlayer) {
+			p.props.set(PlayerInterface, "PlaybackStatus", string(PlaybackPaused))
+		}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, player := testBus(t)
+			fake := &haltingPlayer{
+				skippingPlayer: exportSkippingPlayer(t, server, 3),
+				halted:         make(chan string, 1),
+			}
+			fake.props.set(PlayerInterface, "PlaybackStatus", string(PlaybackPlaying))
+			if err := server.Export(fake, DBusObjectPath, PlayerInterface); err != nil {
+				t.Fatal(err)
+			}
+			props := &readNotifyingProperties{fake.props, make(chan string, 8)}
+			err := server.Export(props, DBusObjectPath, "org.freedesktop.DBus.Properties")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			done := make(chan error, 1)
+			go func() {
+				done <- player.StopAfterCurrentWith(context.Background(), tt.action)
+			}()
+			// PlaybackStatus is read last, once StopAfterCurrent is armed.
+			for receive(t, props.read) != "PlaybackStatus" {
+			}
+			tt.interfere(fake)
+
+			if err := receive(t, done); err != nil {
+				t.Fatal(err)
+			}
+			if tt.want == "" {
+				expectNothing(t, fake.halted)
+			} else if got := receive(t, fake.halted); got != tt.want {
+				t.Errorf("StopAfterCurrent() called %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStopAfterCurrentCancel(t *testing.T) {
+	server, player := testBus(t)
+	exportSkippingPlayer(t, server, 3).props.set(
+		PlayerInterface, "PlaybackStatus", string(PlaybackPlaying))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := player.StopAfterCurrent(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("StopAfterCurrent() error = %v, want DeadlineExceeded", err)
+	}
+}
@@ -0,0 +1,188 @@
+package mpris
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// Option configures a Player created by New.
+type Option func(*Player)
+
+// WithCallTimeout bounds every D-Bus call made by the player to timeout, so a
+// hung player can't block the caller until the D-Bus default timeout. A call
+// that times out fails with an error wrapping context.DeadlineExceeded and
+// naming the method or property. Contexts passed to the Context variants are
+// still honored; the earlier deadline wins. A zero timeout disables the bound.
+func WithCallTimeout(timeout time.Duration) Option {
+	return func(p *Player) {
+		p.callTimeout = timeout
+	}
+}
+
+// callCtx returns ctx bounded by the call timeout of the player.
+func (i *Player) callCtx(ctx context.Context) (context.Context, context.CancelFunc) {
+	if i.callTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, i.callTimeout)
+}
+
+// WithNoAutoStart makes the player pass dbus.FlagNoAutoStart on every call, so
+// calling a player that isn't running fails with ErrServiceUnknown instead of
+// D-Bus activating, i.e. launching, the application.
+func WithNoAutoStart() Option {
+	return func(p *Player) {
+		p.flags |= dbus.FlagNoAutoStart
+	}
+}
+
+// WithObjectPath makes the player talk to the MPRIS interfaces at path instead
+// of DBusObjectPath, for applications exporting them elsewhere.
+func WithObjectPath(path dbus.ObjectPath) Option {
+	return func(p *Player) {
+		p.path = path
+	}
+}
+
+// WithRetry makes the player try its idempotent operations, the property
+// reads, GetAll and Introspect, up to maxAttempts times when they fail with a
+// transient error, org.freedesktop.DBus.Error.NoReply or LimitsExceeded, as a
+// loaded session bus returns. It waits backoff before the second attempt and
+// doubles the wait after each one. Method calls, such as Next or Play, and
+// property writes are never retried, since they may have taken effect
+// although they failed. Every attempt shares the call timeout and the context
+// of the operation, so retries never make it take longer. An operation that
+// took more than one attempt fails with a RetryError. maxAttempts includes
+// the first attempt, so 1 or less disables retrying.
+func WithRetry(maxAttempts int, backoff time.Duration) Option {
+	return func(p *Player) {
+		p.attempts = maxAttempts
+		p.backoff = backoff
+	}
+}
+
+// noReplyError is the D-Bus error name of a call that got no reply in time.
+const noReplyError = "org.freedesktop.DBus.Error.NoReply"
+
+// limitsExceededError is the D-Bus error name of a call the bus refused for
+// lack of resources.
+const limitsExceededError = "org.freedesktop.DBus.Error.LimitsExceeded"
+
+// RetryError is the error of an operation that failed after more than one
+// attempt, see WithRetry and ListRetry. It unwraps to the error of the last
+// attempt, so it still matches ErrPlayerGone and the like.
+type RetryError struct {
+	// Err is the error of the last attempt.
+	Err error
+	// Attempts is the number of attempts made.
+	Attempts int
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("%v (after %d attempts)", e.Err, e.Attempts)
+}
+
+func (e *RetryError) Unwrap() error { return e.Err }
+
+// transient reports whether err is worth retrying an idempotent call for.
+func transient(err error) bool {
+	name, _ := dbusErrorName(err)
+	return name == noReplyError || name == limitsExceededError
+}
+
+// idempotent reports whether method may be retried.
+func idempotent(method string) bool {
+	switch method {
+	case GetPropertyMethod, GetAllPropertiesMethod, IntrospectMethod:
+		return true
+	}
+	return false
+}
+
+// retryCall makes call, passing it ctx, up to maxAttempts times while it
+// fails with a transient error, waiting backoff before the second attempt and
+// doubling the wait after each one. No attempt starts once ctx is done or
+// when the wait would outlast its deadline. It returns the last call, whose
+// error is a RetryError after more than one attempt, and the number of
+// attempts.
+func retryCall(
+	ctx context.Context,
+	maxAttempts int,
+	backoff time.Duration,
+	call func(context.Context) *dbus.Call,
+) (*dbus.Call, int) {
+	for attempt := 1; ; attempt++ {
+		c := call(ctx)
+		if attempt >= maxAttempts || !transient(c.Err) ||
+			!waitBackoff(ctx, backoff) {
+			if attempt > 1 && c.Err != nil {
+				c.Err = &RetryError{Err: c.Err, Attempts: attempt}
+			}
+			return c, attempt
+		}
+		backoff *= 2
+	}
+}
+
+// waitBackoff waits backoff and reports whether ctx leaves time for another
+// attempt afterwards.
+func waitBackoff(ctx context.Context, backoff time.Duration) bool {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= backoff {
+		return false
+	}
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// do calls method on the player object with the flags, call timeout and
+// retries of the player, through its call queue if it has one. The call is
+// logged and observed from the goroutine of the caller, so a logger or
+// Metrics calling back into the Player can't block the call queue.
+func (i *Player) do(ctx context.Context, method string, args ...any) *dbus.Call {
+	start := time.Now()
+	var (
+		call     *dbus.Call
+		attempts int
+	)
+	if i.queue != nil && queued(method) {
+		call = i.queue.submit(ctx, method, args...)
+	} else {
+		call, attempts = i.retry(ctx, method, args...)
+	}
+	i.observeCall(ctx, method, args, time.Since(start), attempts, call.Err)
+	return call
+}
+
+// doNow is like do but bypasses the call queue and isn't observed.
+func (i *Player) doNow(ctx context.Context, method string, args ...any) *dbus.Call {
+	call, _ := i.retry(ctx, method, args...)
+	return call
+}
+
+// retry calls method within the call timeout of the player, retrying it as
+// set by WithRetry when it is idempotent, and returns the last call and the
+// number of attempts.
+func (i *Player) retry(
+	ctx context.Context,
+	method string,
+	args ...any,
+) (*dbus.Call, int) {
+	ctx, cancel := i.callCtx(ctx)
+	defer cancel()
+	attempts := 1
+	if idempotent(method) {
+		attempts = i.attempts
+	}
+	return retryCall(ctx, attempts, i.backoff, func(ctx context.Context) *dbus.Call {
+		return i.tr.call(ctx, i.name, i.path, method, i.flags, args...)
+	})
+}
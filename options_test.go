@@ -0,0 +1,268 @@
+package mpris
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestWithCallTimeout(t *testing.T) {
+	server, client := testBus(t)
+	exportHungPlayer(t, server)
+	player := New(client.conn, testPlayerName, WithCallTimeout(50*time.Millisecond))
+
+	tests := []struct {
+		name string
+		call func() error
+		want string
+	}{
+		{"Play", player.Play, PlayerInterface + ".Play"},
+		{"GetPosition", func() error {
+			_, err := player.GetPosition()
+			return err
+		}, PlayerInterface + ".Position"},
+		{"SetVolume", func() error { return player.SetVolume(0.5) }, PlayerInterface + ".Volume"},
+	}
+	for _, tt := range tests {
+		err := tt.call()
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("%s() error = %v, want context.DeadlineExceeded", tt.name, err)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s() error %q doesn't name %s", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestWithCallTimeoutKeepsEarlierDeadline(t *testing.T) {
+	server, client := testBus(t)
+	exportHungPlayer(t, server)
+	player := New(client.conn, testPlayerName, WithCallTimeout(time.Hour))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := player.PlayContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("PlayContext() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestZeroCallTimeout(t *testing.T) {
+	server, client := testBus(t)
+	exportTestProperties(t, server, map[string]map[string]any{
+		PlayerInterface: {"Volume": 0.5},
+	})
+	player := New(client.conn, testPlayerName, WithCallTimeout(0))
+
+	if got, err := player.GetVolume(); err != nil || got != 0.5 {
+		t.Errorf("GetVolume() = %v, %v", got, err)
+	}
+}
+
+// writeService writes a D-Bus service file activating name by running a
+// script that creates the file marker.
+func writeService(t *testing.T, dir, name, marker string) {
+	t.Helper()
+	script := filepath.Join(dir, "activate.sh")
+	err := os.WriteFile(script, []byte("#!/bin/sh\ntouch '"+marker+"'\n"), 0o700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := "[D-BUS Service]\nName=" + name + "\nExec=" + script + "\n"
+	err = os.WriteFile(filepath.Join(dir, name+".service"), []byte(service), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWithNoAutoStart(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "activated")
+	name := BaseInterface + ".activatable"
+	writeService(t, dir, name, marker)
+	conn := testConn(t, testBusAddressWithServices(t, dir))
+
+	player := New(conn, name, WithNoAutoStart(), WithCallTimeout(time.Second))
+	if err := player.Play(); !errors.Is(err, ErrServiceUnknown) {
+		t.Errorf("Play() error = %v, want ErrServiceUnknown", err)
+	}
+	if _, err := player.GetVolume(); !errors.Is(err, ErrServiceUnknown) {
+		t.Errorf("GetVolume() error = %v, want ErrServiceUnknown", err)
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Fatal("the player was activated despite WithNoAutoStart")
+	}
+
+	// Without the option the same call launches the service.
+	New(conn, name, WithCallTimeout(100*time.Millisecond)).Play()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := os.Stat(marker); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the service file is not used by the test bus")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWithObjectPath(t *testing.T) {
+	server, client := testBus(t)
+	const path = dbus.ObjectPath("/org/example/Player")
+	props := &testProperties{
+		conn: server,
+		props: map[string]map[string]dbus.Variant{
+			PlayerInterface: {"Volume": dbus.MakeVariant(0.3)},
+		},
+		emit: map[string]emitMode{},
+	}
+	if err := server.Export(props, path, "org.freedesktop.DBus.Properties"); err != nil {
+		t.Fatal(err)
+	}
+	player := New(client.conn, testPlayerName, WithObjectPath(path))
+
+	if got, err := player.GetVolume(); err != nil || got != 0.3 {
+		t.Errorf("GetVolume() = %v, %v", got, err)
+	}
+
+	ch := make(chan Metadata, 1)
+	sub, err := player.WatchMetadataChanged(ch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	// Signals from the standard path belong to another object.
+	emitPropertiesChanged(t, server, PlayerInterface, metadataWithTitle("wrong path"))
+	err = server.Emit(path, PropertiesChangedSignal, PlayerInterface,
+		metadataWithTitle("custom path"), []string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if title, _ := receive(t, ch).GetString("xesam:title"); title != "custom path" {
+		t.Errorf("title = %q, want custom path", title)
+	}
+}
+
+// flakyPlayer fails the first failures calls to Play and to
+// Properties.Get with errName.
+type flakyPlayer struct {
+	errName  string
+	failures int
+	calls    atomic.Int32
+}
+
+func (p *flakyPlayer) fail() *dbus.Error {
+	if int(p.calls.Add(1)) <= p.failures {
+		return dbus.NewError(p.errName, []any{"try again"})
+	}
+	return nil
+}
+
+func (p *flakyPlayer) Play() *dbus.Error {
+	return p.fail()
+}
+
+func (p *flakyPlayer) Get(iface, name string) (dbus.Variant, *dbus.Error) {
+	if err := p.fail(); err != nil {
+		return dbus.Variant{}, err
+	}
+	return dbus.MakeVariant("Flaky"), nil
+}
+
+// exportFlakyPlayer exports a flakyPlayer failing failures times with errName
+// on the player connection.
+func exportFlakyPlayer(
+	t *testing.T,
+	server *dbus.Conn,
+	errName string,
+	failures int,
+) *flakyPlayer {
+	t.Helper()
+	flaky := &flakyPlayer{errName: errName, failures: failures}
+	for _, iface := range []string{PlayerInterface, "org.freedesktop.DBus.Properties"} {
+		if err := server.Export(flaky, DBusObjectPath, iface); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return flaky
+}
+
+func TestWithRetry(t *testing.T) {
+	for _, errName := range []string{noReplyError, limitsExceededError} {
+		server, client := testBus(t)
+		flaky := exportFlakyPlayer(t, server, errName, 2)
+
+		player := New(client.conn, testPlayerName, WithRetry(3, time.Millisecond))
+		if identity, err := player.GetIdentity(); err != nil || identity != "Flaky" {
+			t.Fatalf("GetIdentity() with retries = %q, %v", identity, err)
+		}
+		if got := flaky.calls.Load(); got != 3 {
+			t.Errorf("GetIdentity() made %d calls after %s, want 3", got, errName)
+		}
+
+		flaky.calls.Store(0)
+		player = New(client.conn, testPlayerName, WithRetry(2, time.Millisecond))
+		_, err := player.GetIdentity()
+		var retryErr *RetryError
+		if !errors.As(err, &retryErr) || retryErr.Attempts != 2 {
+			t.Errorf("GetIdentity() with too few attempts = %v, want a RetryError", err)
+		}
+		if got := flaky.calls.Load(); got != 2 {
+			t.Errorf("GetIdentity() made %d calls, want 2", got)
+		}
+
+		// Method calls aren't idempotent.
+		flaky.calls.Store(0)
+		if err := player.Play(); errors.As(err, &retryErr) || err == nil {
+			t.Errorf("Play() = %v, want the error of a single attempt", err)
+		}
+		if got := flaky.calls.Load(); got != 1 {
+			t.Errorf("Play() made %d calls, want 1", got)
+		}
+	}
+}
+
+func TestWithRetryOtherErrors(t *testing.T) {
+	server, client := testBus(t)
+	flaky := exportFlakyPlayer(t, server, "org.freedesktop.DBus.Error.Failed", 2)
+
+	player := New(client.conn, testPlayerName, WithRetry(3, time.Millisecond))
+	if _, err := player.GetIdentity(); err == nil {
+		t.Error("GetIdentity() succeeded")
+	}
+	if got := flaky.calls.Load(); got != 1 {
+		t.Errorf("GetIdentity() made %d calls, want 1", got)
+	}
+}
+
+func TestWithRetryBudget(t *testing.T) {
+	server, client := testBus(t)
+	flaky := exportFlakyPlayer(t, server, noReplyError, 100)
+
+	player := New(client.conn, testPlayerName,
+		WithCallTimeout(100*time.Millisecond),
+		WithRetry(100, 40*time.Millisecond),
+	)
+	start := time.Now()
+	_, err := player.GetIdentity()
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+		t.Errorf("GetIdentity() took %v, past the call timeout", elapsed)
+	}
+	// The second wait, of 80ms, would outlast the timeout.
+	if got := flaky.calls.Load(); got != 2 {
+		t.Errorf("GetIdentity() made %d calls, want 2", got)
+	}
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) || retryErr.Attempts != 2 {
+		t.Errorf("GetIdentity() = %v, want a RetryError after 2 attempts", err)
+	}
+}
@@ -0,0 +1,180 @@
+package mpris
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// WithOwnerTracking makes the player follow its bus name across application
+// restarts. It watches NameOwnerChanged for the name and updates the unique
+// owner that signals are accepted from, so subscriptions keep delivering the
+// signals of a restarted player instead of waiting for the old owner forever.
+// Changes are announced on OwnerChanged. The player must be closed with Close
+// to stop tracking.
+func WithOwnerTracking() Option {
+	return func(p *Player) {
+		p.trackOwner = true
+	}
+}
+
+// ownerTracker follows the unique owner of a bus name.
+type ownerTracker struct {
+	name string
+	sub  *Subscription
+	ch   chan string
+	// changed is called when the owner changes.
+	changed func()
+
+	mu    sync.Mutex
+	owner string
+	// seen is set once a NameOwnerChanged signal was received, after which
+	// the owner read on startup is outdated.
+	seen bool
+}
+
+// startOwnerTracking starts following the owner of the player's name.
+func (i *Player) startOwnerTracking() error {
+	t := &ownerTracker{
+		name:    i.name,
+		ch:      make(chan string, 1),
+		changed: i.forgetOwnerState,
+	}
+	rule := []dbus.MatchOption{
+		dbus.WithMatchSender(busName),
+		dbus.WithMatchInterface(busName),
+		dbus.WithMatchMember("NameOwnerChanged"),
+		dbus.WithMatchArg(0, i.name),
+	}
+	sub, err := subscribe(i.tr, [][]dbus.MatchOption{rule}, t.filter, t.handle)
+	if err != nil {
+		return err
+	}
+	t.sub = sub
+
+	// Read the owner after subscribing, so a change in between is not lost.
+	owner, err := i.getOwner()
+	if err != nil && !errors.Is(err, ErrServiceUnknown) {
+		sub.Close()
+		return err
+	}
+	t.mu.Lock()
+	if !t.seen {
+		t.owner = owner
+	}
+	t.mu.Unlock()
+	i.mu.Lock()
+	i.tracker = t
+	i.mu.Unlock()
+	return nil
+}
+
+// filter records the new owner carried by a NameOwnerChanged signal. It runs
+// on the dispatcher while the signal is fanned out, so the new owner is known
+// before the signals the restarted player sends next reach any subscription.
+func (t *ownerTracker) filter(sig *dbus.Signal) bool {
+	if sig.Sender != busName || sig.Name != nameOwnerChangedSignal {
+		return false
+	}
+	var name, oldOwner, newOwner string
+	if err := dbus.Store(sig.Body, &name, &oldOwner, &newOwner); err != nil {
+		return false
+	}
+	if name != t.name {
+		return false
+	}
+	t.mu.Lock()
+	t.seen = true
+	if t.owner == newOwner {
+		t.mu.Unlock()
+		return false
+	}
+	t.owner = newOwner
+	t.mu.Unlock()
+	t.changed()
+	return true
+}
+
+// handle announces the new owner on ch, replacing an announcement that wasn't
+// received yet.
+func (t *ownerTracker) handle(_ context.Context, sig *dbus.Signal) {
+	owner, _ := sig.Body[2].(string)
+	select {
+	case <-t.ch:
+	default:
+	}
+	select {
+	case t.ch <- owner:
+	default:
+	}
+}
+
+// current returns the owner of the name, or an empty string when it has none.
+func (t *ownerTracker) current() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.owner
+}
+
+// OwnerChanged returns a channel receiving the new unique owner of the
+// player's name whenever it changes, or an empty string when the player goes
+// away. Only the latest change is kept for a slow receiver. It returns nil
+// for players created without WithOwnerTracking.
+func (i *Player) OwnerChanged() <-chan string {
+	_, tracker := i.state()
+	if tracker == nil {
+		return nil
+	}
+	return tracker.ch
+}
+
+// Close stops the owner tracking started by WithOwnerTracking, the cache
+// started by WithPropertyCache and the position estimate of the quirks that
+// need one. Players without them hold no resources, and closing them does
+// nothing.
+func (i *Player) Close() error {
+	var err error
+	if _, tracker := i.state(); tracker != nil {
+		err = tracker.sub.Close()
+	}
+	i.mu.Lock()
+	estimate := i.estimate
+	i.mu.Unlock()
+	if estimate != nil {
+		err = errors.Join(err, i.stopEstimate(estimate))
+	}
+	return errors.Join(err, i.closeCache())
+}
+
+// forgetOwnerState drops what the player learned about the previous owner of
+// its name, which the application may not share once restarted: the
+// introspection data kept by Introspect and the quirks, which may have been
+// given up on while the name had no owner.
+func (i *Player) forgetOwnerState() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.introspection = nil
+	i.noIntrospection = false
+	i.quirks = nil
+}
+
+// signalSender returns the match rule option and sender check subscriptions
+// use to only accept the signals of the player. Tracked players match the
+// well-known name, which the bus resolves to the current owner, and check
+// the sender against the tracked owner.
+func (i *Player) signalSender() (dbus.MatchOption, func(string) bool, error) {
+	if _, t := i.state(); t != nil {
+		return dbus.WithMatchSender(i.name), func(sender string) bool {
+			return sender != "" && sender == t.current()
+		}, nil
+	}
+	owner, err := i.signalOwner()
+	if err != nil {
+		return dbus.MatchOption{}, nil, err
+	}
+	return dbus.WithMatchSender(owner), func(sender string) bool {
+		return sender == owner
+	}, nil
+}
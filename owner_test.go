@@ -0,0 +1,142 @@
+package mpris
+
+import (
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+)
+
+func TestWithOwnerTracking(t *testing.T) {
+	addr := testBusAddress(t)
+	server := testConn(t, addr)
+	if _, err := server.RequestName(testPlayerName, dbus.NameFlagDoNotQueue); err != nil {
+		t.Fatal(err)
+	}
+	player, err := NewChecked(testConn(t, addr), testPlayerName, WithOwnerTracking())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer player.Close()
+	if player.Owner() != server.Names()[0] {
+		t.Errorf("Owner() = %q, want %q", player.Owner(), server.Names()[0])
+	}
+
+	metadata := make(chan Metadata, 1)
+	sub, err := player.WatchMetadataChanged(metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+	seeked := make(chan time.Duration, 1)
+	seekedSub, err := player.WatchSeeked(seeked)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer seekedSub.Close()
+
+	// The application restarts and takes the name back.
+	if _, err := server.ReleaseName(testPlayerName); err != nil {
+		t.Fatal(err)
+	}
+	if owner := receive(t, player.OwnerChanged()); owner != "" {
+		t.Errorf("owner after release = %q, want none", owner)
+	}
+	restarted := testConn(t, addr)
+	if _, err := restarted.RequestName(testPlayerName, dbus.NameFlagDoNotQueue); err != nil {
+		t.Fatal(err)
+	}
+	emitPropertiesChanged(t, restarted, PlayerInterface, metadataWithTitle("restarted"))
+	if title, _ := receive(t, metadata).GetString("xesam:title"); title != "restarted" {
+		t.Errorf("title = %q, want restarted", title)
+	}
+	err = restarted.Emit(DBusObjectPath, PlayerInterface+".Seeked", int64(5_000_000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if position := receive(t, seeked); position != 5*time.Second {
+		t.Errorf("position = %v, want 5s", position)
+	}
+	if owner := receive(t, player.OwnerChanged()); owner != restarted.Names()[0] {
+		t.Errorf("owner after restart = %q, want %q", owner, restarted.Names()[0])
+	}
+	if player.Owner() != restarted.Names()[0] {
+		t.Errorf("Owner() = %q, want %q", player.Owner(), restarted.Names()[0])
+	}
+
+	// The old instance is not listened to anymore.
+	emitPropertiesChanged(t, server, PlayerInterface, metadataWithTitle("old"))
+	expectNothing(t, metadata)
+}
+
+func TestOwnerTrackingWithoutOwner(t *testing.T) {
+	addr := testBusAddress(t)
+	player := New(testConn(t, addr), testPlayerName, WithOwnerTracking())
+	defer player.Close()
+	if player.Owner() != "" {
+		t.Errorf("Owner() = %q, want none", player.Owner())
+	}
+
+	ch := make(chan Metadata, 1)
+	sub, err := player.WatchMetadataChanged(ch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	server := claimName(t, addr, testPlayerName)
+	emitPropertiesChanged(t, server, PlayerInterface, metadataWithTitle("late"))
+	if title, _ := receive(t, ch).GetString("xesam:title"); title != "late" {
+		t.Errorf("title = %q, want late", title)
+	}
+	if New(testConn(t, addr), testPlayerName).OwnerChanged() != nil {
+		t.Error("OwnerChanged() of an untracked player is not nil")
+	}
+}
+
+// exportIntrospection makes conn answer Introspect on the player object with
+// a node exporting ifaces.
+func exportIntrospection(t *testing.T, conn *dbus.Conn, ifaces ...string) {
+	t.Helper()
+	node := &introspect.Node{}
+	for _, name := range ifaces {
+		node.Interfaces = append(node.Interfaces, introspect.Interface{Name: name})
+	}
+	err := conn.Export(
+		introspect.NewIntrospectable(node),
+		DBusObjectPath,
+		"org.freedesktop.DBus.Introspectable",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOwnerTrackingIntrospection(t *testing.T) {
+	addr := testBusAddress(t)
+	server := claimName(t, addr, testPlayerName)
+	exportIntrospection(t, server, BaseInterface, PlayerInterface)
+	player, err := NewChecked(testConn(t, addr), testPlayerName, WithOwnerTracking())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer player.Close()
+	if ok, err := player.HasInterface(TrackListInterface); err != nil || ok {
+		t.Fatalf("HasInterface(TrackList) = %v, %v, want false", ok, err)
+	}
+
+	// The application restarts with a TrackList.
+	if _, err := server.ReleaseName(testPlayerName); err != nil {
+		t.Fatal(err)
+	}
+	if owner := receive(t, player.OwnerChanged()); owner != "" {
+		t.Errorf("owner after release = %q, want none", owner)
+	}
+	restarted := claimName(t, addr, testPlayerName)
+	exportIntrospection(t, restarted, BaseInterface, PlayerInterface, TrackListInterface)
+	receive(t, player.OwnerChanged())
+	if ok, err := player.HasInterface(TrackListInterface); err != nil || !ok {
+		t.Errorf("HasInterface(TrackList) after the restart = %v, %v, want true", ok, err)
+	}
+}
@@ -0,0 +1,21 @@
+package mpris
+
+import "sync"
+
+// parallel runs tasks concurrently, at most limit at a time, and returns once
+// all of them are done. Tasks report their results and errors through the
+// variables they close over, so one failing doesn't stop the others.
+func parallel(limit int, tasks ...func()) {
+	sem := make(chan struct{}, max(limit, 1))
+	var wg sync.WaitGroup
+	for _, task := range tasks {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			task()
+			<-sem
+		}()
+	}
+	wg.Wait()
+}
@@ -2,7 +2,10 @@ package mpris
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"math"
 	"time"
 
 	"github.com/godbus/dbus/v5"
@@ -13,58 +16,216 @@ import (
 
 // Next skips to the next track in the tracklist.
 func (i *Player) Next() error {
-	return i.obj.Call(PlayerInterface+".Next", 0).Err
+	return i.NextContext(context.Background())
+}
+
+// NextContext is like Next but takes a context.
+func (i *Player) NextContext(ctx context.Context) error {
+	return i.callContext(ctx, PlayerInterface+".Next")
 }
 
 // Previous skips to the previous track in the tracklist.
 func (i *Player) Previous() error {
-	return i.obj.Call(PlayerInterface+".Previous", 0).Err
+	return i.PreviousContext(context.Background())
+}
+
+// PreviousContext is like Previous but takes a context.
+func (i *Player) PreviousContext(ctx context.Context) error {
+	return i.callContext(ctx, PlayerInterface+".Previous")
 }
 
 // Pause pauses the current track.
 func (i *Player) Pause() error {
-	return i.obj.Call(PlayerInterface+".Pause", 0).Err
+	return i.PauseContext(context.Background())
+}
+
+// PauseContext is like Pause but takes a context.
+func (i *Player) PauseContext(ctx context.Context) error {
+	return i.callContext(ctx, PlayerInterface+".Pause")
 }
 
 // PlayPause resumes the current track if it's paused and pauses it if it's
 // playing.
 func (i *Player) PlayPause() error {
-	return i.obj.Call(PlayerInterface+".PlayPause", 0).Err
+	return i.PlayPauseContext(context.Background())
+}
+
+// PlayPauseContext is like PlayPause but takes a context.
+func (i *Player) PlayPauseContext(ctx context.Context) error {
+	return i.callContext(ctx, PlayerInterface+".PlayPause")
 }
 
 // Stop stops the current track.
 func (i *Player) Stop() error {
-	return i.obj.Call(PlayerInterface+".Stop", 0).Err
+	return i.StopContext(context.Background())
+}
+
+// StopContext is like Stop but takes a context.
+func (i *Player) StopContext(ctx context.Context) error {
+	return i.callContext(ctx, PlayerInterface+".Stop")
 }
 
 // Play starts or resumes playback of the current track.
 func (i *Player) Play() error {
-	return i.obj.Call(PlayerInterface+".Play", 0).Err
+	return i.PlayContext(context.Background())
+}
+
+// PlayContext is like Play but takes a context.
+func (i *Player) PlayContext(ctx context.Context) error {
+	return i.callContext(ctx, PlayerInterface+".Play")
 }
 
 // Seek changes the current track position by the given offset.
 // If the offset is negative, the playback position moves backward.
 func (i *Player) Seek(offset time.Duration) error {
-	micro := offset.Microseconds()
-	return i.obj.Call(PlayerInterface+".Seek", 0, micro).Err
+	return i.SeekContext(context.Background(), offset)
 }
 
-// SetTrackPosition sets the playback position of a specific track.
+// SeekContext is like Seek but takes a context.
+func (i *Player) SeekContext(ctx context.Context, offset time.Duration) error {
+	micro := durationToMicroseconds(offset)
+	return i.callContext(ctx, PlayerInterface+".Seek", micro)
+}
+
+// SetTrackPosition sets the playback position of a specific track, see
+// SetPositionFor. A nil trackID is rejected with ErrInvalidTrackID.
+//
+// Deprecated: Use SetPositionFor, which takes the track ID by value.
 func (i *Player) SetTrackPosition(
 	trackID *dbus.ObjectPath,
 	position time.Duration,
 ) error {
-	oms := position.Microseconds()
-	return i.obj.Call(PlayerInterface+".SetPosition", 0, trackID, oms).Err
+	return i.SetTrackPositionContext(context.Background(), trackID, position)
+}
+
+// SetTrackPositionContext is like SetTrackPosition but takes a context.
+//
+// Deprecated: Use SetPositionForContext, which takes the track ID by value.
+func (i *Player) SetTrackPositionContext(
+	ctx context.Context,
+	trackID *dbus.ObjectPath,
+	position time.Duration,
+) error {
+	if trackID == nil {
+		return fmt.Errorf("%s.SetPosition: %w: nil", PlayerInterface, ErrInvalidTrackID)
+	}
+	return i.SetPositionForContext(ctx, *trackID, position)
 }
 
-// SetPosition sets the playback position of the current track.
+// SetPositionFor sets the playback position of the track trackID, which the
+// player ignores unless it is the current track. Track IDs that aren't valid
+// object paths are rejected with ErrInvalidTrackID and the NoTrack ID with
+// ErrNoTrack. Negative positions, and positions past the end of the current
+// track when it is trackID and the player reports its length, are rejected
+// with ErrOutOfRange, since players ignore them.
+func (i *Player) SetPositionFor(
+	trackID dbus.ObjectPath,
+	position time.Duration,
+) error {
+	return i.SetPositionForContext(context.Background(), trackID, position)
+}
+
+// SetPositionForContext is like SetPositionFor but takes a context.
+func (i *Player) SetPositionForContext(
+	ctx context.Context,
+	trackID dbus.ObjectPath,
+	position time.Duration,
+) error {
+	if err := checkTrackPosition(nil, trackID, position); err != nil {
+		return err
+	}
+	// Without metadata the length is unknown, and the player has the last
+	// word.
+	m, _ := i.GetMetadataContext(ctx)
+	return i.setTrackPosition(ctx, m, trackID, position)
+}
+
+// checkTrackPosition validates the arguments of SetPosition for the track
+// trackID, against the length in m when m describes that track.
+func checkTrackPosition(
+	m Metadata,
+	trackID dbus.ObjectPath,
+	position time.Duration,
+) error {
+	switch {
+	case position < 0:
+		return fmt.Errorf(
+			"%s.SetPosition: %w: negative position %v",
+			PlayerInterface,
+			ErrOutOfRange,
+			position,
+		)
+	case !trackID.IsValid():
+		return fmt.Errorf(
+			"%s.SetPosition: %w: %q",
+			PlayerInterface,
+			ErrInvalidTrackID,
+			trackID,
+		)
+	case trackID == NoTrack:
+		return fmt.Errorf("%s.SetPosition: %w", PlayerInterface, ErrNoTrack)
+	}
+	if current, err := m.GetObjectPath("mpris:trackid"); err != nil ||
+		current != trackID {
+		return nil
+	}
+	if length, err := m.getLength("mpris:length"); err == nil && length > 0 &&
+		position > length {
+		return fmt.Errorf(
+			"%s.SetPosition: %w: %v is after the end of the track at %v",
+			PlayerInterface,
+			ErrOutOfRange,
+			position,
+			length,
+		)
+	}
+	return nil
+}
+
+// setTrackPosition calls SetPosition for the track trackID once its arguments
+// are checked against m, the metadata of the current track, which may be nil.
+func (i *Player) setTrackPosition(
+	ctx context.Context,
+	m Metadata,
+	trackID dbus.ObjectPath,
+	position time.Duration,
+) error {
+	if err := checkTrackPosition(m, trackID, position); err != nil {
+		return err
+	}
+	oms := durationToMicroseconds(position)
+	return i.callContext(ctx, PlayerInterface+".SetPosition", trackID, oms)
+}
+
+// SetPosition sets the playback position of the current track. When the track
+// ID of the current track isn't a valid object path, or the player rejects it
+// and has the SeekFallback quirk, the position is moved with a relative Seek
+// from the current position instead; see Quirks.
 func (i *Player) SetPosition(position time.Duration) error {
-	trackID, err := i.GetTrackID()
+	return i.SetPositionContext(context.Background(), position)
+}
+
+// SetPositionContext is like SetPosition but takes a context.
+func (i *Player) SetPositionContext(
+	ctx context.Context,
+	position time.Duration,
+) error {
+	m, err := i.GetMetadataContext(ctx)
 	if err != nil {
 		return err
 	}
-	return i.SetTrackPosition(&trackID, position)
+	trackID, err := m.GetObjectPath("mpris:trackid")
+	if err != nil {
+		return err
+	}
+	err = i.setTrackPosition(ctx, m, trackID, position)
+	switch {
+	case errors.Is(err, ErrInvalidTrackID):
+		return i.seekFallback(ctx, position, err)
+	case rejectedTrackID(err) && i.QuirksContext(ctx).SeekFallback:
+		return i.seekFallback(ctx, position, err)
+	}
+	return err
 }
 
 //revive:disable:var-naming
@@ -80,54 +241,77 @@ func (i *Player) OpenUri(uri string) error {
 
 // OpenURI opens and plays the given URI if supported.
 func (i *Player) OpenURI(uri string) error {
-	return i.obj.Call(PlayerInterface+".OpenUri", 0, uri).Err
+	return i.OpenURIContext(context.Background(), uri)
 }
 
-// Signals
+// OpenURIContext is like OpenURI but takes a context.
+func (i *Player) OpenURIContext(ctx context.Context, uri string) error {
+	return i.callContext(ctx, PlayerInterface+".OpenUri", uri)
+}
 
-// OnSeeked listens for "Seeked" signal and sends the new position as
-// time.Duration to position until ctx is canceled.
-func (i *Player) OnSeeked(ctx context.Context, position chan<- time.Duration) error {
-	sigChan := make(chan *dbus.Signal, 10) // buffered to avoid blocking
-	defer close(sigChan)
+// Signals
 
-	var sender string
-	err := i.conn.BusObject().
-		Call("org.freedesktop.DBus.GetNameOwner", 0, i.name).
-		Store(&sender)
-	if err != nil {
-		return err
+// decodeSeeked returns the position carried by a Seeked signal. The spec
+// mandates an int64 in microseconds, but some players send uint64, and VLC
+// occasionally int32, so every integer width is accepted.
+func decodeSeeked(sig *dbus.Signal) (time.Duration, bool) {
+	if sig.Name != PlayerInterface+".Seeked" || len(sig.Body) == 0 {
+		return 0, false
 	}
-
-	err = i.conn.AddMatchSignal(
-		dbus.WithMatchInterface(PlayerInterface),
-		dbus.WithMatchMember("Seeked"),
-		dbus.WithMatchSender(sender),
-	)
-	if err != nil {
-		return err
+	switch sig.Body[0].(type) {
+	case int64, uint64, int32, uint32, int16, uint16, byte:
+	default:
+		return 0, false
 	}
+	position, err := microsecondsToDuration(sig.Body[0])
+	return position, err == nil
+}
 
-	i.conn.Signal(sigChan)
-
-	for {
+// seekedHandler sends the position carried by every Seeked signal to ch.
+func seekedHandler(ch chan<- time.Duration) func(context.Context, *dbus.Signal) {
+	return func(ctx context.Context, sig *dbus.Signal) {
+		position, ok := decodeSeeked(sig)
+		if !ok {
+			return
+		}
 		select {
+		case ch <- position:
 		case <-ctx.Done():
-			return nil
-		case signal, ok := <-sigChan:
-			if !ok {
-				return nil
-			}
+		}
+	}
+}
 
-			var dur time.Duration
-			err := dbus.Store(signal.Body, &dur)
-			if err != nil {
-				continue
-			}
+// WatchSeeked listens for "Seeked" signal and sends the new position as
+// time.Duration to position until the returned Subscription is closed. Use
+// Subscribe with SeekedContext for the Stamp of every signal.
+func (i *Player) WatchSeeked(position chan<- time.Duration) (*Subscription, error) {
+	sender, fromPlayer, err := i.signalSender()
+	if err != nil {
+		return nil, err
+	}
+	rule := []dbus.MatchOption{
+		dbus.WithMatchObjectPath(i.path),
+		dbus.WithMatchInterface(PlayerInterface),
+		dbus.WithMatchMember("Seeked"),
+		sender,
+	}
+	filter := func(sig *dbus.Signal) bool {
+		return fromPlayer(sig.Sender) &&
+			sig.Path == i.path &&
+			sig.Name == PlayerInterface+".Seeked"
+	}
+	handle := i.observeSignals(seekedHandler(position))
+	return subscribe(i.tr, [][]dbus.MatchOption{rule}, filter, handle)
+}
 
-			position <- dur * time.Microsecond
-		}
+// OnSeeked listens for "Seeked" signal and sends the new position as
+// time.Duration to position until ctx is canceled.
+func (i *Player) OnSeeked(ctx context.Context, position chan<- time.Duration) error {
+	sub, err := i.WatchSeeked(position)
+	if err != nil {
+		return err
 	}
+	return sub.wait(ctx)
 }
 
 // Properties
@@ -148,7 +332,14 @@ const (
 
 // GetPlaybackStatus returns the current playback status.
 func (i *Player) GetPlaybackStatus() (PlaybackStatus, error) {
-	str, err := getPlayerPropertyCast(i, "PlaybackStatus", cast.ToStringE)
+	return i.GetPlaybackStatusContext(context.Background())
+}
+
+// GetPlaybackStatusContext is like GetPlaybackStatus but takes a context.
+func (i *Player) GetPlaybackStatusContext(
+	ctx context.Context,
+) (PlaybackStatus, error) {
+	str, err := getPlayerPropertyCast(ctx, i, "PlaybackStatus", cast.ToStringE)
 	return PlaybackStatus(str), err
 }
 
@@ -167,119 +358,384 @@ const (
 
 // GetLoopStatus returns the current loop status.
 func (i *Player) GetLoopStatus() (LoopStatus, error) {
-	str, err := getPlayerPropertyCast(i, "LoopStatus", cast.ToStringE)
+	return i.GetLoopStatusContext(context.Background())
+}
+
+// GetLoopStatusContext is like GetLoopStatus but takes a context.
+func (i *Player) GetLoopStatusContext(ctx context.Context) (LoopStatus, error) {
+	str, err := getPlayerPropertyCast(ctx, i, "LoopStatus", cast.ToStringE)
 	return LoopStatus(str), err
 }
 
-// SetLoopStatus sets the loop status.
+// Valid returns whether s is one of the loop statuses the spec defines.
+func (s LoopStatus) Valid() bool {
+	switch s {
+	case LoopNone, LoopTrack, LoopPlaylist:
+		return true
+	default:
+		return false
+	}
+}
+
+// SetLoopStatus sets the loop status. It fails with an error wrapping
+// ErrOutOfRange, without calling the player, when loopStatus is not one of
+// LoopNone, LoopTrack and LoopPlaylist, and with one wrapping ErrNotSupported
+// when the player has no loop status or doesn't allow setting it.
 func (i *Player) SetLoopStatus(loopStatus LoopStatus) error {
-	return i.SetPlayerProperty("LoopStatus", loopStatus)
+	return i.SetLoopStatusContext(context.Background(), loopStatus)
+}
+
+// SetLoopStatusContext is like SetLoopStatus but takes a context.
+func (i *Player) SetLoopStatusContext(
+	ctx context.Context,
+	loopStatus LoopStatus,
+) error {
+	if !loopStatus.Valid() {
+		return fmt.Errorf(
+			"%w: loop status %q is not %s, %s or %s",
+			ErrOutOfRange,
+			loopStatus,
+			LoopNone,
+			LoopTrack,
+			LoopPlaylist,
+		)
+	}
+	return missingAsNotSupported(
+		i.SetPropertyContext(ctx, PlayerInterface, "LoopStatus", loopStatus),
+	)
+}
+
+// nextLoopStatus is the loop status CycleLoopStatus moves to from each one.
+var nextLoopStatus = map[LoopStatus]LoopStatus{
+	LoopNone:     LoopPlaylist,
+	LoopPlaylist: LoopTrack,
+	LoopTrack:    LoopNone,
+}
+
+// CycleLoopStatus moves the loop status from None to Playlist to Track and
+// back to None, like playerctl does, and returns the status read back from
+// the player afterwards, which stays the same when the player refuses the
+// change. The error wraps ErrNotSupported when the player has no loop status.
+func (i *Player) CycleLoopStatus() (LoopStatus, error) {
+	return i.CycleLoopStatusContext(context.Background())
+}
+
+// CycleLoopStatusContext is like CycleLoopStatus but takes a context.
+func (i *Player) CycleLoopStatusContext(ctx context.Context) (LoopStatus, error) {
+	status, err := i.GetLoopStatusContext(ctx)
+	if err != nil {
+		return "", missingAsNotSupported(err)
+	}
+	next, ok := nextLoopStatus[status]
+	if !ok {
+		next = LoopNone
+	}
+	if err := i.SetLoopStatusContext(ctx, next); err != nil {
+		return status, missingAsNotSupported(err)
+	}
+	status, err = i.GetLoopStatusContext(ctx)
+	return status, missingAsNotSupported(err)
 }
 
 // GetRate returns the current playback rate.
 func (i *Player) GetRate() (float64, error) {
-	return getPlayerPropertyCast(i, "Rate", cast.ToFloat64E)
+	return i.GetRateContext(context.Background())
+}
+
+// GetRateContext is like GetRate but takes a context.
+func (i *Player) GetRateContext(ctx context.Context) (float64, error) {
+	return getPlayerPropertyCast(ctx, i, "Rate", cast.ToFloat64E)
 }
 
-// SetRate sets the playback rate.
+// SetRate sets the playback rate. It fails with an error wrapping
+// ErrOutOfRange, without setting anything, when rate is 0 or outside the
+// range given by MinimumRate and MaximumRate. Bounds the player doesn't
+// report aren't checked. Use SetRateUnchecked to skip the validation. The
+// error wraps ErrNotSupported when the player doesn't allow setting the rate.
 func (i *Player) SetRate(rate float64) error {
-	return i.SetPlayerProperty("Rate", rate)
+	return i.SetRateContext(context.Background(), rate)
+}
+
+// SetRateContext is like SetRate but takes a context.
+func (i *Player) SetRateContext(ctx context.Context, rate float64) error {
+	if rate == 0 || math.IsNaN(rate) || math.IsInf(rate, 0) {
+		return fmt.Errorf("%w: rate %v is not a valid rate", ErrOutOfRange, rate)
+	}
+	lowest, err := i.GetMinimumRateContext(ctx)
+	if err != nil {
+		lowest = math.Inf(-1)
+	}
+	highest, err := i.GetMaximumRateContext(ctx)
+	if err != nil {
+		highest = math.Inf(1)
+	}
+	if rate < lowest || rate > highest {
+		return fmt.Errorf(
+			"%w: rate %v is outside [%v, %v]",
+			ErrOutOfRange,
+			rate,
+			lowest,
+			highest,
+		)
+	}
+	return i.SetRateUncheckedContext(ctx, rate)
+}
+
+// SetRateUnchecked sets the playback rate without validating it, leaving it
+// to the player to handle values SetRate would reject.
+func (i *Player) SetRateUnchecked(rate float64) error {
+	return i.SetRateUncheckedContext(context.Background(), rate)
+}
+
+// SetRateUncheckedContext is like SetRateUnchecked but takes a context.
+func (i *Player) SetRateUncheckedContext(ctx context.Context, rate float64) error {
+	return missingAsNotSupported(
+		i.SetPropertyContext(ctx, PlayerInterface, "Rate", rate),
+	)
 }
 
 // GetShuffle returns true if shuffle mode is enabled, false if playing linearly
 // through a playlist.
 func (i *Player) GetShuffle() (bool, error) {
-	return getPlayerPropertyCast(i, "Shuffle", cast.ToBoolE)
+	return i.GetShuffleContext(context.Background())
+}
+
+// GetShuffleContext is like GetShuffle but takes a context.
+func (i *Player) GetShuffleContext(ctx context.Context) (bool, error) {
+	return getPlayerPropertyCast(ctx, i, "Shuffle", cast.ToBoolE)
 }
 
-// SetShuffle sets the shuffle mode.
+// SetShuffle sets the shuffle mode. The error wraps ErrNotSupported when the
+// player has no shuffle mode or doesn't allow setting it.
 func (i *Player) SetShuffle(value bool) error {
-	return i.SetPlayerProperty("Shuffle", value)
+	return i.SetShuffleContext(context.Background(), value)
+}
+
+// SetShuffleContext is like SetShuffle but takes a context.
+func (i *Player) SetShuffleContext(ctx context.Context, value bool) error {
+	return missingAsNotSupported(
+		i.SetPropertyContext(ctx, PlayerInterface, "Shuffle", value),
+	)
 }
 
-// Metadata represents the metadata of the current track.
-type Metadata map[string]dbus.Variant
+// ToggleShuffle flips the shuffle mode and returns the mode read back from
+// the player afterwards, which stays the same when the player refuses the
+// change. The error wraps ErrNotSupported when the player has no shuffle mode.
+func (i *Player) ToggleShuffle() (bool, error) {
+	return i.ToggleShuffleContext(context.Background())
+}
 
-// Get returns the value for the given metadata key.
-func (m Metadata) Get(key string) (any, error) {
-	v, ok := m[key]
-	if !ok || v.Value() == nil {
-		return v, fmt.Errorf(
-			"%s.Metadata missing or nil for key %q",
-			PlayerInterface,
-			key,
-		)
+// ToggleShuffleContext is like ToggleShuffle but takes a context.
+func (i *Player) ToggleShuffleContext(ctx context.Context) (bool, error) {
+	shuffle, err := i.GetShuffleContext(ctx)
+	if err != nil {
+		return false, missingAsNotSupported(err)
+	}
+	if err := i.SetShuffleContext(ctx, !shuffle); err != nil {
+		return shuffle, missingAsNotSupported(err)
 	}
-	return v.Value(), nil
+	shuffle, err = i.GetShuffleContext(ctx)
+	return shuffle, missingAsNotSupported(err)
 }
 
 // GetMetadata returns the current track metadata.
 func (i *Player) GetMetadata() (Metadata, error) {
-	return getPlayerPropertyCast(i, "Metadata", func(a any) (Metadata, error) {
-		v, ok := a.(map[string]dbus.Variant)
-		if !ok {
-			return Metadata{}, fmt.Errorf(
-				"failed to cast %s.Metadata value (%v) to map[string]dbus.Variant",
-				PlayerInterface,
-				a,
-			)
-		}
-		return Metadata(v), nil
-	})
+	return i.GetMetadataContext(context.Background())
+}
+
+// GetMetadataContext is like GetMetadata but takes a context.
+func (i *Player) GetMetadataContext(ctx context.Context) (Metadata, error) {
+	return getPlayerPropertyCast(ctx, i, "Metadata", toMetadata)
+}
+
+// GetMetadataInto replaces the contents of m with the current track metadata,
+// reusing m instead of handing out a new map, for callers polling many
+// players. m must not be nil, and is left empty when the metadata can't be
+// read.
+func (i *Player) GetMetadataInto(m Metadata) error {
+	return i.GetMetadataIntoContext(context.Background(), m)
+}
+
+// GetMetadataIntoContext is like GetMetadataInto but takes a context.
+func (i *Player) GetMetadataIntoContext(ctx context.Context, m Metadata) error {
+	clear(m)
+	// The decoded reply is private to this call, so its entries are moved
+	// into m as they are instead of being cloned first.
+	_, err := getPlayerPropertyCast(
+		ctx,
+		i,
+		"Metadata",
+		func(a any) (Metadata, error) {
+			reply, err := toMetadata(a)
+			if err != nil {
+				return nil, err
+			}
+			maps.Copy(m, reply)
+			return m, nil
+		},
+	)
+	return err
 }
 
 // GetVolume returns the current volume.
 func (i *Player) GetVolume() (float64, error) {
-	return getPlayerPropertyCast(i, "Volume", cast.ToFloat64E)
+	return i.GetVolumeContext(context.Background())
+}
+
+// GetVolumeContext is like GetVolume but takes a context.
+func (i *Player) GetVolumeContext(ctx context.Context) (float64, error) {
+	return getPlayerPropertyCast(ctx, i, "Volume", cast.ToFloat64E)
 }
 
-// SetVolume sets the current volume.
+// SetVolume sets the current volume. With WithVolumeLimit, the volume is
+// clamped to the limit first.
 func (i *Player) SetVolume(volume float64) error {
-	return i.SetPlayerProperty("Volume", volume)
+	return i.SetVolumeContext(context.Background(), volume)
+}
+
+// SetVolumeContext is like SetVolume but takes a context.
+func (i *Player) SetVolumeContext(ctx context.Context, volume float64) error {
+	if i.clampVolume {
+		volume = i.clamp(volume)
+	}
+	return i.SetPropertyContext(ctx, PlayerInterface, "Volume", volume)
 }
 
-// GetPosition returns the current playback position.
+// GetPosition returns the current playback position. See
+// GetPositionWithSource for players that don't report it.
 func (i *Player) GetPosition() (time.Duration, error) {
-	micro, err := getPlayerPropertyCast(i, "Position", cast.ToInt64E)
-	return time.Duration(micro) * time.Microsecond, err
+	return i.GetPositionContext(context.Background())
+}
+
+// GetPositionContext is like GetPosition but takes a context.
+func (i *Player) GetPositionContext(
+	ctx context.Context,
+) (time.Duration, error) {
+	position, _, err := i.GetPositionWithSourceContext(ctx)
+	return position, err
+}
+
+// PositionSource tells where a position returned by GetPositionWithSource
+// comes from.
+type PositionSource string
+
+//revive:disable:exported
+
+const (
+	// PositionReported is the Position property of the player.
+	PositionReported PositionSource = "reported"
+	// PositionEstimated is estimated from the last Seeked signal or track
+	// change, advanced by the clock while playing.
+	PositionEstimated PositionSource = "estimated"
+)
+
+//revive:enable:exported
+
+// GetPositionWithSource is like GetPosition, but also tells whether the
+// position was reported by the player or estimated. It is estimated for
+// players with the SeekedPosition quirk, and for players that don't implement
+// the Position property at all, like spotifyd, which still announce seeks.
+// Such players are watched from the first call on, and until they seek or
+// change tracks the error wraps ErrUnknownProperty.
+func (i *Player) GetPositionWithSource() (time.Duration, PositionSource, error) {
+	return i.GetPositionWithSourceContext(context.Background())
+}
+
+// GetPositionWithSourceContext is like GetPositionWithSource but takes a
+// context.
+func (i *Player) GetPositionWithSourceContext(
+	ctx context.Context,
+) (time.Duration, PositionSource, error) {
+	if i.QuirksContext(ctx).SeekedPosition {
+		if position, ok := i.estimatedPosition(ctx); ok {
+			return position, PositionEstimated, nil
+		}
+	}
+	position, err := i.GetPropertyDurationContext(ctx, PlayerInterface, "Position")
+	if errors.Is(err, ErrUnknownProperty) {
+		if estimated, ok := i.estimatedPosition(ctx); ok {
+			return estimated, PositionEstimated, nil
+		}
+	}
+	return position, PositionReported, err
 }
 
 // GetMinimumRate returns the minimum playback rate.
 func (i *Player) GetMinimumRate() (float64, error) {
-	return getPlayerPropertyCast(i, "MinimumRate", cast.ToFloat64E)
+	return i.GetMinimumRateContext(context.Background())
+}
+
+// GetMinimumRateContext is like GetMinimumRate but takes a context.
+func (i *Player) GetMinimumRateContext(ctx context.Context) (float64, error) {
+	return getPlayerPropertyCast(ctx, i, "MinimumRate", cast.ToFloat64E)
 }
 
 // GetMaximumRate returns the maximum playback rate.
 func (i *Player) GetMaximumRate() (float64, error) {
-	return getPlayerPropertyCast(i, "MaximumRate", cast.ToFloat64E)
+	return i.GetMaximumRateContext(context.Background())
+}
+
+// GetMaximumRateContext is like GetMaximumRate but takes a context.
+func (i *Player) GetMaximumRateContext(ctx context.Context) (float64, error) {
+	return getPlayerPropertyCast(ctx, i, "MaximumRate", cast.ToFloat64E)
 }
 
 // CanGoNext returns whether the player can skip to the next track.
 func (i *Player) CanGoNext() (bool, error) {
-	return getPlayerPropertyCast(i, "CanGoNext", cast.ToBoolE)
+	return i.CanGoNextContext(context.Background())
+}
+
+// CanGoNextContext is like CanGoNext but takes a context.
+func (i *Player) CanGoNextContext(ctx context.Context) (bool, error) {
+	return getPlayerPropertyCast(ctx, i, "CanGoNext", cast.ToBoolE)
 }
 
 // CanGoPrevious returns whether the player can skip to the previous track.
 func (i *Player) CanGoPrevious() (bool, error) {
-	return getPlayerPropertyCast(i, "CanGoPrevious", cast.ToBoolE)
+	return i.CanGoPreviousContext(context.Background())
+}
+
+// CanGoPreviousContext is like CanGoPrevious but takes a context.
+func (i *Player) CanGoPreviousContext(ctx context.Context) (bool, error) {
+	return getPlayerPropertyCast(ctx, i, "CanGoPrevious", cast.ToBoolE)
 }
 
 // CanPlay returns whether the player can start or resume playback.
 func (i *Player) CanPlay() (bool, error) {
-	return getPlayerPropertyCast(i, "CanPlay", cast.ToBoolE)
+	return i.CanPlayContext(context.Background())
+}
+
+// CanPlayContext is like CanPlay but takes a context.
+func (i *Player) CanPlayContext(ctx context.Context) (bool, error) {
+	return getPlayerPropertyCast(ctx, i, "CanPlay", cast.ToBoolE)
 }
 
 // CanPause returns whether the player can pause playback.
 func (i *Player) CanPause() (bool, error) {
-	return getPlayerPropertyCast(i, "CanPause", cast.ToBoolE)
+	return i.CanPauseContext(context.Background())
+}
+
+// CanPauseContext is like CanPause but takes a context.
+func (i *Player) CanPauseContext(ctx context.Context) (bool, error) {
+	return getPlayerPropertyCast(ctx, i, "CanPause", cast.ToBoolE)
 }
 
 // CanSeek returns whether the player can seek within the current track.
 func (i *Player) CanSeek() (bool, error) {
-	return getPlayerPropertyCast(i, "CanSeek", cast.ToBoolE)
+	return i.CanSeekContext(context.Background())
+}
+
+// CanSeekContext is like CanSeek but takes a context.
+func (i *Player) CanSeekContext(ctx context.Context) (bool, error) {
+	return getPlayerPropertyCast(ctx, i, "CanSeek", cast.ToBoolE)
 }
 
 // CanControl returns whether the player can be controlled.
 func (i *Player) CanControl() (bool, error) {
-	return getPlayerPropertyCast(i, "CanControl", cast.ToBoolE)
+	return i.CanControlContext(context.Background())
+}
+
+// CanControlContext is like CanControl but takes a context.
+func (i *Player) CanControlContext(ctx context.Context) (bool, error) {
+	return getPlayerPropertyCast(ctx, i, "CanControl", cast.ToBoolE)
 }
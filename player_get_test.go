@@ -0,0 +1,161 @@
+package mpris_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Nadim147c/go-mpris"
+	"github.com/Nadim147c/go-mpris/mpristest"
+	"github.com/godbus/dbus/v5"
+)
+
+// TestPlayerGetMethods runs all get method tests as subtests against a fake
+// player with known properties.
+func TestPlayerGetMethods(t *testing.T) {
+	fake, player := mpristest.NewFakePlayer(t)
+	fake.SetPlayer("PlaybackStatus", "Playing")
+	fake.SetPlayer("LoopStatus", "Playlist")
+	fake.SetPlayer("Rate", 1.5)
+	fake.SetPlayer("Shuffle", true)
+	fake.SetPlayer("Volume", 0.25)
+	fake.SetPlayer("CanGoPrevious", false)
+	fake.SetMetadata(mpris.Metadata{
+		"mpris:trackid": dbus.MakeVariant(dbus.ObjectPath("/track/1")),
+		"mpris:length":  dbus.MakeVariant(int64(180_000_000)),
+		"xesam:title":   dbus.MakeVariant("Title"),
+		"xesam:artist":  dbus.MakeVariant([]string{"Artist"}),
+	})
+	fake.SetPosition(42 * time.Second)
+
+	t.Run("GetName", func(t *testing.T) {
+		if name := player.GetName(); name != mpristest.Name {
+			t.Errorf("GetName() = %q, want %q", name, mpristest.Name)
+		}
+	})
+
+	t.Run("GetSupportedUriSchemes", func(t *testing.T) {
+		schemes, err := player.GetSupportedUriSchemes()
+		if err != nil || !slices.Equal(schemes, []string{"file"}) {
+			t.Errorf("GetSupportedUriSchemes() = %q, %v", schemes, err)
+		}
+	})
+
+	t.Run("HasTrackList", func(t *testing.T) {
+		if b, err := player.HasTrackList(); err != nil || b {
+			t.Errorf("HasTrackList() = %v, %v, want false", b, err)
+		}
+	})
+
+	t.Run("CanPlay", func(t *testing.T) {
+		if b, err := player.CanPlay(); err != nil || !b {
+			t.Errorf("CanPlay() = %v, %v, want true", b, err)
+		}
+	})
+
+	t.Run("CanControl", func(t *testing.T) {
+		if b, err := player.CanControl(); err != nil || !b {
+			t.Errorf("CanControl() = %v, %v, want true", b, err)
+		}
+	})
+
+	t.Run("CanGoPrevious", func(t *testing.T) {
+		if b, err := player.CanGoPrevious(); err != nil || b {
+			t.Errorf("CanGoPrevious() = %v, %v, want false", b, err)
+		}
+	})
+
+	t.Run("CanEditTracks", func(t *testing.T) {
+		if _, err := player.CanEditTracks(); err == nil {
+			t.Error("CanEditTracks() succeeded without a track list")
+		}
+	})
+
+	t.Run("GetIdentity", func(t *testing.T) {
+		if identity, err := player.GetIdentity(); err != nil || identity != "Fake Player" {
+			t.Errorf("GetIdentity() = %q, %v", identity, err)
+		}
+	})
+
+	t.Run("GetPlaybackStatus", func(t *testing.T) {
+		status, err := player.GetPlaybackStatus()
+		if err != nil || status != mpris.PlaybackPlaying {
+			t.Errorf("GetPlaybackStatus() = %q, %v, want Playing", status, err)
+		}
+	})
+
+	t.Run("GetLoopStatus", func(t *testing.T) {
+		loop, err := player.GetLoopStatus()
+		if err != nil || loop != mpris.LoopPlaylist {
+			t.Errorf("GetLoopStatus() = %q, %v, want Playlist", loop, err)
+		}
+	})
+
+	t.Run("GetRate", func(t *testing.T) {
+		if rate, err := player.GetRate(); err != nil || rate != 1.5 {
+			t.Errorf("GetRate() = %v, %v, want 1.5", rate, err)
+		}
+	})
+
+	t.Run("GetShuffle", func(t *testing.T) {
+		if shuffle, err := player.GetShuffle(); err != nil || !shuffle {
+			t.Errorf("GetShuffle() = %v, %v, want true", shuffle, err)
+		}
+	})
+
+	t.Run("GetMetadata", func(t *testing.T) {
+		metadata, err := player.GetMetadata()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id, _ := metadata.GetObjectPath("mpris:trackid"); id != "/track/1" {
+			t.Errorf("trackid = %q, want /track/1", id)
+		}
+		if title, _ := metadata.GetString("xesam:title"); title != "Title" {
+			t.Errorf("title = %q, want Title", title)
+		}
+		if artist, _ := metadata.GetStringSlice("xesam:artist"); !slices.Equal(artist, []string{"Artist"}) {
+			t.Errorf("artist = %q, want [Artist]", artist)
+		}
+	})
+
+	t.Run("GetVolume", func(t *testing.T) {
+		if volume, err := player.GetVolume(); err != nil || volume != 0.25 {
+			t.Errorf("GetVolume() = %v, %v, want 0.25", volume, err)
+		}
+	})
+
+	t.Run("GetLength", func(t *testing.T) {
+		if length, err := player.GetLength(); err != nil || length != 3*time.Minute {
+			t.Errorf("GetLength() = %v, %v, want 3m", length, err)
+		}
+	})
+
+	t.Run("GetPosition", func(t *testing.T) {
+		if position, err := player.GetPosition(); err != nil || position != 42*time.Second {
+			t.Errorf("GetPosition() = %v, %v, want 42s", position, err)
+		}
+	})
+
+	t.Run("GetProperty", func(t *testing.T) {
+		v, err := player.GetProperty(mpris.BaseInterface, "Identity")
+		if err != nil || v.Value() != "Fake Player" {
+			t.Errorf("GetProperty(Identity) = %v, %v", v, err)
+		}
+	})
+
+	t.Run("GetPlayerProperty", func(t *testing.T) {
+		v, err := player.GetPlayerProperty("PlaybackStatus")
+		if err != nil || v.Value() != "Playing" {
+			t.Errorf("GetPlayerProperty(PlaybackStatus) = %v, %v", v, err)
+		}
+	})
+
+	t.Run("UnknownProperty", func(t *testing.T) {
+		_, err := player.GetPlayerProperty("Typo")
+		if err == nil || errors.Is(err, mpris.ErrPlayerGone) {
+			t.Errorf("GetPlayerProperty(Typo) error = %v", err)
+		}
+	})
+}
@@ -0,0 +1,220 @@
+package mpris_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Nadim147c/go-mpris"
+	"github.com/Nadim147c/go-mpris/mpristest"
+	"github.com/godbus/dbus/v5"
+)
+
+// spotifyPlayer returns a fake imitating Spotify, playing quirkyTrack, and a
+// Player connected to it with opts.
+func spotifyPlayer(
+	t *testing.T,
+	opts ...mpris.Option,
+) (*mpristest.FakePlayer, *mpris.Player) {
+	t.Helper()
+	fake, _ := mpristest.NewFakePlayer(t)
+	fake.Imitate(mpristest.Presets["spotify"])
+	fake.SetMetadata(quirkyTrack)
+	fake.SetPosition(10 * time.Second)
+	player := mpris.New(fake.Connect(), mpristest.Name, opts...)
+	t.Cleanup(func() { player.Close() })
+	return fake, player
+}
+
+// invalidArgs is the error players reply to arguments they reject with.
+var invalidArgs = dbus.NewError(
+	"org.freedesktop.DBus.Error.InvalidArgs",
+	[]any{"invalid track ID"},
+)
+
+// expectSeek fails t unless the only position change the fake got is a Seek
+// by offset.
+func expectSeek(
+	t *testing.T,
+	fake *mpristest.FakePlayer,
+	offset time.Duration,
+) {
+	t.Helper()
+	var seeks []any
+	for _, call := range fake.Calls() {
+		switch call.Method {
+		case "Seek":
+			seeks = append(seeks, call.Args...)
+		case "SetPosition":
+		default:
+			continue
+		}
+	}
+	want := offset.Microseconds()
+	if len(seeks) != 1 || seeks[0] != want {
+		t.Errorf("seeks = %v, want one by %d", seeks, want)
+	}
+}
+
+func TestPlayerQuirks(t *testing.T) {
+	_, plain := mpristest.NewFakePlayer(t)
+	if q := plain.Quirks(); q != (mpris.Quirks{}) {
+		t.Errorf("Quirks() of a plain player = %+v, want none", q)
+	}
+
+	_, spotify := spotifyPlayer(t)
+	if q := spotify.Quirks(); q.Name != "spotify" || !q.SeekFallback ||
+		!q.SeekedPosition {
+		t.Errorf("Quirks() of Spotify = %+v", q)
+	}
+
+	_, disabled := spotifyPlayer(t, mpris.WithoutQuirks())
+	if q := disabled.Quirks(); q != (mpris.Quirks{}) {
+		t.Errorf("Quirks() with WithoutQuirks = %+v, want none", q)
+	}
+}
+
+func TestQuirkSeekFallback(t *testing.T) {
+	fake, player := spotifyPlayer(t)
+	fake.Handle("SetPosition", func(*mpristest.FakePlayer, []any) error {
+		return invalidArgs
+	})
+	if err := player.SetPosition(30 * time.Second); err != nil {
+		t.Fatal(err)
+	}
+	expectSeek(t, fake, 20*time.Second)
+
+}
+
+func TestQuirkSeekFallbackInvalidTrackID(t *testing.T) {
+	fake, _ := mpristest.NewFakePlayer(t)
+	fake.Imitate(mpristest.Presets["spotify"])
+	fake.SetMetadata(mpris.Metadata{
+		"mpris:trackid": dbus.MakeVariant("spotify:track:4uLU6hMCjMI75M1A2tKUQC"),
+	})
+	fake.SetPosition(10 * time.Second)
+	player := mpris.New(fake.Connect(), mpristest.Name)
+	defer player.Close()
+
+	if err := player.SetPosition(5 * time.Second); err != nil {
+		t.Fatal(err)
+	}
+	expectSeek(t, fake, -5*time.Second)
+}
+
+func TestQuirkSeekFallbackDisabled(t *testing.T) {
+	fake, player := spotifyPlayer(t, mpris.WithoutQuirks())
+	fake.Handle("SetPosition", func(*mpristest.FakePlayer, []any) error {
+		return invalidArgs
+	})
+	if err := player.SetPosition(30 * time.Second); err == nil {
+		t.Fatal("SetPosition succeeded although the player rejected it")
+	}
+	for _, call := range fake.Calls() {
+		if call.Method == "Seek" {
+			t.Errorf("seeked by %v without quirks", call.Args)
+		}
+	}
+}
+
+func TestQuirkSeekedPosition(t *testing.T) {
+	fake, player := spotifyPlayer(t)
+	if position, err := player.GetPosition(); err != nil ||
+		position != 10*time.Second {
+		t.Fatalf("GetPosition() = %v, %v before Seeked, want 10s",
+			position, err)
+	}
+
+	// The lagging property keeps reporting the old position.
+	fake.EmitSeeked(time.Minute)
+	waitPosition(t, player.GetPosition, time.Minute)
+}
+
+func TestInvalidTrackID(t *testing.T) {
+	const raw = "mpv/playlist entry 1"
+	fake, _ := mpristest.NewFakePlayer(t)
+	fake.Imitate(mpristest.Presets["mpv"])
+	fake.SetMetadata(mpris.Metadata{"mpris:trackid": dbus.MakeVariant(raw)})
+	fake.SetPosition(10 * time.Second)
+	// Not a quirk: invalid IDs can't be sent to any player.
+	player := mpris.New(fake.Connect(), mpristest.Name, mpris.WithoutQuirks())
+
+	if id, err := player.GetTrackIDString(); err != nil || id != raw {
+		t.Errorf("GetTrackIDString() = %q, %v, want %q", id, err, raw)
+	}
+	id, err := player.GetTrackID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = player.SetPositionFor(id, time.Second)
+	if !errors.Is(err, mpris.ErrInvalidTrackID) {
+		t.Errorf("SetPositionFor() = %v, want ErrInvalidTrackID", err)
+	}
+
+	if err := player.SetPosition(25 * time.Second); err != nil {
+		t.Fatal(err)
+	}
+	expectSeek(t, fake, 15*time.Second)
+}
+
+// waitPosition polls get until it returns want, failing t after a second.
+func waitPosition(
+	t *testing.T,
+	get func() (time.Duration, error),
+	want time.Duration,
+) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		position, err := get()
+		if err == nil && position == want {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("position = %v, %v, want %v", position, err, want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestPositionWithoutProperty(t *testing.T) {
+	fake, player := mpristest.NewFakePlayer(t)
+	defer player.Close()
+	fake.DropProperty("Position")
+
+	_, _, err := player.GetPositionWithSource()
+	if !errors.Is(err, mpris.ErrUnknownProperty) {
+		t.Fatalf("GetPositionWithSource() error = %v before Seeked, "+
+			"want ErrUnknownProperty", err)
+	}
+
+	tracker, err := player.TrackPosition(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tracker.Close()
+	if position := tracker.Position(); position != 0 {
+		t.Errorf("tracker starts at %v, want 0", position)
+	}
+
+	fake.EmitSeeked(30 * time.Second)
+	waitPosition(t, player.GetPosition, 30*time.Second)
+	waitPosition(t, func() (time.Duration, error) {
+		return tracker.Position(), nil
+	}, 30*time.Second)
+	_, source, err := player.GetPositionWithSource()
+	if err != nil || source != mpris.PositionEstimated {
+		t.Errorf("source = %q, %v, want estimated", source, err)
+	}
+}
+
+func TestPositionReported(t *testing.T) {
+	fake, player := mpristest.NewFakePlayer(t)
+	fake.SetPosition(5 * time.Second)
+	position, source, err := player.GetPositionWithSource()
+	if err != nil || position != 5*time.Second ||
+		source != mpris.PositionReported {
+		t.Errorf("GetPositionWithSource() = %v, %q, %v, want 5s reported",
+			position, source, err)
+	}
+}
@@ -0,0 +1,109 @@
+package mpris
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSetRateValidation(t *testing.T) {
+	server, player := testBus(t)
+	exportTestProperties(t, server, map[string]map[string]any{
+		PlayerInterface: {"Rate": 1.0, "MinimumRate": 0.5, "MaximumRate": 2.0},
+	})
+
+	for _, rate := range []float64{0, 0.25, 2.5, -1} {
+		if err := player.SetRate(rate); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("SetRate(%v) error = %v, want ErrOutOfRange", rate, err)
+		}
+	}
+	if got, _ := player.GetRate(); got != 1 {
+		t.Errorf("rate = %v after rejected calls, want 1", got)
+	}
+
+	if err := player.SetRate(1.5); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := player.GetRate(); got != 1.5 {
+		t.Errorf("rate = %v, want 1.5", got)
+	}
+	if err := player.SetRateUnchecked(4); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := player.GetRate(); got != 4 {
+		t.Errorf("rate = %v, want 4", got)
+	}
+}
+
+func TestSetRateWithoutBounds(t *testing.T) {
+	server, player := testBus(t)
+	exportTestProperties(t, server, map[string]map[string]any{
+		PlayerInterface: {"Rate": 1.0},
+	})
+	if err := player.SetRate(8); err != nil {
+		t.Errorf("SetRate(8) error = %v", err)
+	}
+	if err := player.SetRate(0); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("SetRate(0) error = %v, want ErrOutOfRange", err)
+	}
+}
+
+func TestSetLoopStatusValidation(t *testing.T) {
+	server, player := testBus(t)
+	exportTestProperties(t, server, map[string]map[string]any{
+		PlayerInterface: {"LoopStatus": "None"},
+	})
+	if err := player.SetLoopStatus("track"); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("SetLoopStatus(track) error = %v, want ErrOutOfRange", err)
+	}
+	if err := player.SetLoopStatus(LoopPlaylist); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := player.GetLoopStatus(); got != LoopPlaylist {
+		t.Errorf("loop status = %v, want Playlist", got)
+	}
+}
+
+func TestToggleShuffle(t *testing.T) {
+	server, player := testBus(t)
+	exportTestProperties(t, server, map[string]map[string]any{
+		PlayerInterface: {"Shuffle": false},
+	})
+	for _, want := range []bool{true, false} {
+		got, err := player.ToggleShuffle()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("ToggleShuffle() = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestCycleLoopStatus(t *testing.T) {
+	server, player := testBus(t)
+	exportTestProperties(t, server, map[string]map[string]any{
+		PlayerInterface: {"LoopStatus": "None"},
+	})
+	for _, want := range []LoopStatus{LoopPlaylist, LoopTrack, LoopNone} {
+		got, err := player.CycleLoopStatus()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("CycleLoopStatus() = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestTogglesNotSupported(t *testing.T) {
+	server, player := testBus(t)
+	exportTestProperties(t, server, map[string]map[string]any{
+		PlayerInterface: {},
+	})
+	if _, err := player.ToggleShuffle(); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("ToggleShuffle() error = %v, want ErrNotSupported", err)
+	}
+	if _, err := player.CycleLoopStatus(); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("CycleLoopStatus() error = %v, want ErrNotSupported", err)
+	}
+}
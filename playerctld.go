@@ -0,0 +1,85 @@
+package mpris
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/spf13/cast"
+)
+
+const (
+	// PlayerctldName is the bus name playerctld proxies the most recently
+	// active player under.
+	PlayerctldName = BaseInterface + ".playerctld"
+	// PlayerctldInterface is the interface playerctld exposes its own methods
+	// and properties on, next to the MPRIS interfaces of the proxied player.
+	PlayerctldInterface = "com.github.altdesktop.playerctld"
+)
+
+// Playerctld is the player proxied by the playerctld daemon. Besides every
+// Player method, which act on the most recently active player, it exposes
+// the methods playerctld uses to pick that player.
+type Playerctld struct {
+	*Player
+}
+
+// NewPlayerctld connects to playerctld in the connection conn. The options
+// customize the behavior of the underlying Player.
+func NewPlayerctld(conn *dbus.Conn, opts ...Option) *Playerctld {
+	return &Playerctld{Player: New(conn, PlayerctldName, opts...)}
+}
+
+// Shift makes the next player the active one and returns its bus name.
+func (p *Playerctld) Shift() (string, error) {
+	return p.ShiftContext(context.Background())
+}
+
+// ShiftContext is like Shift but takes a context.
+func (p *Playerctld) ShiftContext(ctx context.Context) (string, error) {
+	return p.callName(ctx, PlayerctldInterface+".Shift")
+}
+
+// Unshift makes the previous player the active one and returns its bus name.
+func (p *Playerctld) Unshift() (string, error) {
+	return p.UnshiftContext(context.Background())
+}
+
+// UnshiftContext is like Unshift but takes a context.
+func (p *Playerctld) UnshiftContext(ctx context.Context) (string, error) {
+	return p.callName(ctx, PlayerctldInterface+".Unshift")
+}
+
+// callName calls method, which returns a bus name.
+func (p *Playerctld) callName(ctx context.Context, method string) (string, error) {
+	call := p.do(ctx, method)
+	if call.Err != nil {
+		return "", fmt.Errorf(
+			"failed to call %s: %w",
+			method,
+			translateError(call.Err),
+		)
+	}
+	var name string
+	if err := call.Store(&name); err != nil {
+		return "", fmt.Errorf("failed to store %s result: %w", method, err)
+	}
+	return name, nil
+}
+
+// PlayerNames returns the bus names of the players playerctld knows about,
+// the active one first.
+func (p *Playerctld) PlayerNames() ([]string, error) {
+	return p.PlayerNamesContext(context.Background())
+}
+
+// PlayerNamesContext is like PlayerNames but takes a context.
+func (p *Playerctld) PlayerNamesContext(ctx context.Context) ([]string, error) {
+	return getPropertyCast(
+		ctx,
+		p.Player,
+		PlayerctldInterface,
+		"PlayerNames",
+		cast.ToStringSliceE,
+	)
+}
@@ -0,0 +1,98 @@
+package mpris
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// fakePlayerctld implements the Shift and Unshift methods of playerctld over
+// a rotating list of names.
+type fakePlayerctld struct {
+	mu    sync.Mutex
+	names []string
+}
+
+func (f *fakePlayerctld) Shift() (string, *dbus.Error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.names = append(f.names[1:], f.names[0])
+	return f.names[0], nil
+}
+
+func (f *fakePlayerctld) Unshift() (string, *dbus.Error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	last := len(f.names) - 1
+	f.names = append([]string{f.names[last]}, f.names[:last]...)
+	return f.names[0], nil
+}
+
+func TestPlayerctld(t *testing.T) {
+	addr := testBusAddress(t)
+	conn := testConn(t, addr)
+
+	names := []string{BaseInterface + ".a", BaseInterface + ".b", BaseInterface + ".c"}
+	server := addTestPlayer(t, addr, PlayerctldName, map[string]map[string]any{
+		PlayerctldInterface: {"PlayerNames": names},
+	})
+	fake := &fakePlayerctld{names: slices.Clone(names)}
+	if err := server.Export(fake, DBusObjectPath, PlayerctldInterface); err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewPlayerctld(conn)
+	got, err := p.PlayerNames()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, names) {
+		t.Errorf("PlayerNames() = %q, want %q", got, names)
+	}
+
+	for _, tt := range []struct {
+		call func() (string, error)
+		want string
+	}{
+		{p.Shift, ".b"},
+		{p.Shift, ".c"},
+		{p.Unshift, ".b"},
+		{p.Unshift, ".a"},
+	} {
+		name, err := tt.call()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name != BaseInterface+tt.want {
+			t.Errorf("name = %s, want %s", name, tt.want)
+		}
+	}
+}
+
+func TestListPlayerctld(t *testing.T) {
+	addr := testBusAddress(t)
+	conn := testConn(t, addr)
+	claimName(t, addr, BaseInterface+".vlc")
+	claimName(t, addr, PlayerctldName)
+
+	tests := []struct {
+		opts []ListOption
+		want []string
+	}{
+		{nil, []string{BaseInterface + ".playerctld", BaseInterface + ".vlc"}},
+		{[]ListOption{IncludePlayerctld(true)}, []string{PlayerctldName, BaseInterface + ".vlc"}},
+		{[]ListOption{IncludePlayerctld(false)}, []string{BaseInterface + ".vlc"}},
+	}
+	for _, tt := range tests {
+		got, err := List(conn, tt.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("List() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,300 @@
+package mpris
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// PositionTracker estimates the playback position of a player without polling
+// it. The position is fetched once and then advanced locally using a monotonic
+// clock scaled by the playback rate. It is corrected whenever the player emits
+// "Seeked" or changes its playback status or rate.
+//
+// A PositionTracker is safe for concurrent use.
+type PositionTracker struct {
+	sub  *Subscription
+	now  func() time.Time
+	done <-chan struct{}
+
+	mu      sync.Mutex
+	base    time.Duration
+	at      time.Time
+	rate    float64
+	playing bool
+	// seekedSeen, statusSeen and rateSeen are set once a signal reported the
+	// position, playback status or rate, which is then newer than the state
+	// TrackPosition read when it started.
+	seekedSeen, statusSeen, rateSeen bool
+}
+
+// TrackPosition starts tracking the playback position until ctx is canceled or
+// the returned tracker is closed. Players that don't report their rate are
+// assumed to play at normal speed. For players without the Position
+// property, tracking starts from the position GetPosition estimates, or from
+// 0 until the first Seeked signal corrects it.
+func (i *Player) TrackPosition(ctx context.Context) (*PositionTracker, error) {
+	t := newPositionTracker(time.Now, 0, PlaybackStopped, 1)
+	// Subscribe before reading the state, so changes in between aren't
+	// missed.
+	sub, err := i.Subscribe(ctx,
+		Seeked(t.seeked),
+		PlaybackStatusChanged(t.setStatus),
+		RateChanged(t.setRate),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	position, err := i.GetPositionContext(ctx)
+	if err != nil && !errors.Is(err, ErrUnknownProperty) {
+		sub.Close()
+		return nil, err
+	}
+	status, err := i.GetPlaybackStatusContext(ctx)
+	if err != nil {
+		sub.Close()
+		return nil, err
+	}
+	rate, err := i.GetRateContext(ctx)
+	if err != nil {
+		rate = 1
+	}
+	t.start(position, status, rate)
+	t.sub = sub
+	t.done = sub.Done()
+	return t, nil
+}
+
+func newPositionTracker(
+	now func() time.Time,
+	position time.Duration,
+	status PlaybackStatus,
+	rate float64,
+) *PositionTracker {
+	if rate <= 0 {
+		rate = 1
+	}
+	return &PositionTracker{
+		now:     now,
+		base:    position,
+		at:      now(),
+		rate:    rate,
+		playing: status == PlaybackPlaying,
+	}
+}
+
+// start sets the state read when tracking started, except for the parts a
+// signal reported meanwhile. The status and rate read held since before the
+// subscription, so they apply to the time since a Seeked signal too.
+func (t *PositionTracker) start(
+	position time.Duration,
+	status PlaybackStatus,
+	rate float64,
+) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.seekedSeen {
+		t.base = position
+		t.at = t.now()
+	}
+	if !t.statusSeen {
+		t.playing = status == PlaybackPlaying
+	}
+	if !t.rateSeen && rate > 0 {
+		t.rate = rate
+	}
+}
+
+// position returns the estimated position at now. t.mu must be held.
+func (t *PositionTracker) position(now time.Time) time.Duration {
+	if !t.playing {
+		return t.base
+	}
+	elapsed := float64(now.Sub(t.at)) * t.rate
+	return t.base + time.Duration(elapsed)
+}
+
+// rebase moves the reference point of the clock to now. t.mu must be held.
+func (t *PositionTracker) rebase(now time.Time) {
+	t.base = t.position(now)
+	t.at = now
+}
+
+// Position returns the current estimated playback position.
+func (t *PositionTracker) Position() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.position(t.now())
+}
+
+func (t *PositionTracker) seeked(position time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.base = position
+	t.at = t.now()
+	t.seekedSeen = true
+}
+
+func (t *PositionTracker) setStatus(status PlaybackStatus) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rebase(t.now())
+	t.playing = status == PlaybackPlaying
+	t.statusSeen = true
+	if status == PlaybackStopped {
+		t.base = 0
+	}
+}
+
+func (t *PositionTracker) setRate(rate float64) {
+	if rate <= 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rebase(t.now())
+	t.rate = rate
+	t.rateSeen = true
+}
+
+// Ticker returns a channel receiving the estimated position every interval
+// until the tracker stops, after which the channel is closed. Ticks are dropped
+// while the receiver is not ready. An interval that isn't positive gives no
+// ticks: the channel is returned closed.
+func (t *PositionTracker) Ticker(interval time.Duration) <-chan time.Duration {
+	ch := make(chan time.Duration, 1)
+	if interval <= 0 {
+		close(ch)
+		return ch
+	}
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-t.done:
+				return
+			case <-ticker.C:
+				select {
+				case ch <- t.Position():
+				default:
+				}
+			}
+		}
+	}()
+	return ch
+}
+
+// Close stops tracking the position. Position keeps extrapolating from the
+// last known state afterwards, but is no longer corrected by the player.
+func (t *PositionTracker) Close() error {
+	if t.sub == nil {
+		return nil
+	}
+	return t.sub.Close()
+}
+
+// positionIdle is how long the position estimate of a player keeps listening
+// for signals after it was last used.
+const positionIdle = time.Minute
+
+// positionEstimate follows the Seeked signals of a player whose Position
+// property can't be trusted. It stops listening once unused for positionIdle,
+// so players that are never closed don't keep a subscription forever.
+type positionEstimate struct {
+	tracker *PositionTracker
+	idle    *time.Timer
+
+	mu sync.Mutex
+	// synced is set once a Seeked signal or a track change told the position.
+	synced bool
+}
+
+// estimatedPosition returns the position estimated from the Seeked signals of
+// the player, starting to listen for them if needed. ok is false until one
+// arrived.
+func (i *Player) estimatedPosition(ctx context.Context) (time.Duration, bool) {
+	i.mu.Lock()
+	e := i.estimate
+	i.mu.Unlock()
+	if e == nil {
+		var err error
+		if e, err = i.startEstimate(ctx); err != nil {
+			return 0, false
+		}
+	}
+	e.idle.Reset(positionIdle)
+	e.mu.Lock()
+	synced := e.synced
+	e.mu.Unlock()
+	return e.tracker.Position(), synced
+}
+
+// startEstimate starts the position estimate of the player, unless another
+// caller did meanwhile, and returns it.
+func (i *Player) startEstimate(ctx context.Context) (*positionEstimate, error) {
+	status, err := i.GetPlaybackStatusContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rate, err := i.GetRateContext(ctx)
+	if err != nil {
+		rate = 1
+	}
+	e := &positionEstimate{
+		tracker: newPositionTracker(time.Now, 0, status, rate),
+	}
+	current, _ := i.GetMetadataContext(ctx)
+	trackID := metadataString(current, "mpris:trackid")
+	sub, err := i.Subscribe(context.Background(),
+		Seeked(func(position time.Duration) {
+			e.tracker.seeked(position)
+			e.sync()
+		}),
+		MetadataChanged(func(m Metadata) {
+			id := metadataString(m, "mpris:trackid")
+			if id != trackID {
+				trackID = id
+				e.tracker.seeked(0)
+				e.sync()
+			}
+		}),
+		PlaybackStatusChanged(e.tracker.setStatus),
+		RateChanged(e.tracker.setRate),
+	)
+	if err != nil {
+		return nil, err
+	}
+	e.tracker.sub = sub
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.estimate != nil {
+		sub.Close()
+		return i.estimate, nil
+	}
+	i.estimate = e
+	e.idle = time.AfterFunc(positionIdle, func() { i.stopEstimate(e) })
+	return e, nil
+}
+
+// sync records that the estimate follows the position of the player.
+func (e *positionEstimate) sync() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.synced = true
+}
+
+// stopEstimate stops e, which the player drops if it is still its estimate.
+func (i *Player) stopEstimate(e *positionEstimate) error {
+	i.mu.Lock()
+	if i.estimate == e {
+		i.estimate = nil
+	}
+	i.mu.Unlock()
+	e.idle.Stop()
+	return e.tracker.Close()
+}
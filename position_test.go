@@ -0,0 +1,182 @@
+package mpris
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced clock.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func TestPositionTrackerInterpolation(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	tr := newPositionTracker(clock.now, 10*time.Second, PlaybackPlaying, 1)
+
+	clock.advance(2 * time.Second)
+	if got := tr.Position(); got != 12*time.Second {
+		t.Errorf("playing: position = %v, want 12s", got)
+	}
+
+	tr.setRate(2)
+	clock.advance(time.Second)
+	if got := tr.Position(); got != 14*time.Second {
+		t.Errorf("rate 2: position = %v, want 14s", got)
+	}
+
+	tr.setStatus(PlaybackPaused)
+	clock.advance(time.Minute)
+	if got := tr.Position(); got != 14*time.Second {
+		t.Errorf("paused: position = %v, want 14s", got)
+	}
+
+	tr.setStatus(PlaybackPlaying)
+	clock.advance(500 * time.Millisecond)
+	if got := tr.Position(); got != 15*time.Second {
+		t.Errorf("resumed: position = %v, want 15s", got)
+	}
+
+	tr.seeked(time.Second)
+	clock.advance(time.Second)
+	if got := tr.Position(); got != 3*time.Second {
+		t.Errorf("seeked: position = %v, want 3s", got)
+	}
+
+	tr.setRate(0)
+	clock.advance(time.Second)
+	if got := tr.Position(); got != 5*time.Second {
+		t.Errorf("ignored rate 0: position = %v, want 5s", got)
+	}
+
+	tr.setStatus(PlaybackStopped)
+	if got := tr.Position(); got != 0 {
+		t.Errorf("stopped: position = %v, want 0", got)
+	}
+}
+
+func TestPositionTrackerStart(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	tr := newPositionTracker(clock.now, 0, PlaybackStopped, 1)
+	tr.start(10*time.Second, PlaybackPlaying, 2)
+	clock.advance(time.Second)
+	if got := tr.Position(); got != 12*time.Second {
+		t.Errorf("started: position = %v, want 12s", got)
+	}
+
+	// Signals arriving between subscribing and reading the state are newer
+	// than what was read.
+	tr = newPositionTracker(clock.now, 0, PlaybackStopped, 1)
+	tr.seeked(30 * time.Second)
+	clock.advance(time.Second)
+	tr.start(10*time.Second, PlaybackPlaying, 1)
+	if got := tr.Position(); got != 31*time.Second {
+		t.Errorf("seeked first: position = %v, want 31s", got)
+	}
+
+	tr = newPositionTracker(clock.now, 0, PlaybackStopped, 1)
+	tr.setStatus(PlaybackPaused)
+	tr.setRate(2)
+	tr.start(10*time.Second, PlaybackPlaying, 1)
+	clock.advance(time.Second)
+	if got := tr.Position(); got != 10*time.Second {
+		t.Errorf("paused first: position = %v, want 10s", got)
+	}
+	tr.setStatus(PlaybackPlaying)
+	clock.advance(time.Second)
+	if got := tr.Position(); got != 12*time.Second {
+		t.Errorf("rate 2 first: position = %v, want 12s", got)
+	}
+}
+
+func TestTrackPosition(t *testing.T) {
+	server, player := testBus(t)
+	props := exportTestProperties(t, server, map[string]map[string]any{
+		PlayerInterface: {
+			"Position":       int64(0),
+			"PlaybackStatus": "Paused",
+			"Rate":           1.0,
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tr, err := player.TrackPosition(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tr.Close()
+
+	if got := tr.Position(); got != 0 {
+		t.Errorf("initial position = %v, want 0", got)
+	}
+
+	err = server.Emit(DBusObjectPath, PlayerInterface+".Seeked", int64(30e6))
+	if err != nil {
+		t.Fatal(err)
+	}
+	props.set(PlayerInterface, "PlaybackStatus", "Playing")
+
+	ticks := tr.Ticker(10 * time.Millisecond)
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case pos := <-ticks:
+			if pos > 30*time.Second {
+				return
+			}
+		case <-deadline:
+			t.Fatalf("position never advanced past 30s, at %v", tr.Position())
+		}
+	}
+}
+
+func TestPositionTrackerTickerInvalidInterval(t *testing.T) {
+	server, player := testBus(t)
+	exportTestProperties(t, server, map[string]map[string]any{
+		PlayerInterface: {"Position": int64(0), "PlaybackStatus": "Playing"},
+	})
+	tr, err := player.TrackPosition(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tr.Close()
+	for _, interval := range []time.Duration{0, -time.Second} {
+		if _, ok := <-tr.Ticker(interval); ok {
+			t.Errorf("Ticker(%v) ticked, want it closed", interval)
+		}
+	}
+}
+
+func TestPositionTrackerTickerClosesWithContext(t *testing.T) {
+	server, player := testBus(t)
+	exportTestProperties(t, server, map[string]map[string]any{
+		PlayerInterface: {
+			"Position":       int64(0),
+			"PlaybackStatus": "Playing",
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	tr, err := player.TrackPosition(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ticks := tr.Ticker(time.Millisecond)
+	cancel()
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case _, ok := <-ticks:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("ticker channel not closed after cancel")
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package mpris
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/godbus/dbus/v5"
@@ -8,22 +9,41 @@ import (
 
 // SetProperty sets the value of a property in the interface.
 func (i *Player) SetProperty(iface, property string, value any) error {
-	call := i.obj.Call(
+	return i.SetPropertyContext(context.Background(), iface, property, value)
+}
+
+// SetPropertyContext is like SetProperty but takes a context.
+func (i *Player) SetPropertyContext(
+	ctx context.Context,
+	iface, property string,
+	value any,
+) error {
+	var w ownWrite
+	if i.echoWindow > 0 {
+		w = i.recordWrite(iface, property, value)
+	}
+	call := i.do(
+		ctx,
 		SetPropertyMethod,
-		0,
 		iface,
 		property,
 		dbus.MakeVariant(value),
 	)
 	if call.Err != nil {
+		if i.echoWindow > 0 {
+			i.forgetWrite(iface, property, w)
+		}
 		return fmt.Errorf(
 			"failed to set property %s.%s to value (%v): %w",
 			iface,
 			property,
 			value,
-			call.Err,
+			translateError(call.Err),
 		)
 	}
+	if c := i.propertyCache(); c != nil {
+		c.invalidateProperty(iface, property)
+	}
 	return nil
 }
 
@@ -37,26 +57,66 @@ func (i *Player) SetPlayerProperty(property string, value any) error {
 	return i.SetProperty(PlayerInterface, property, value)
 }
 
-// SetTrackListProperty sets the propertyName from the tracklist interface.
+// SetTrackListProperty sets the propertyName from the tracklist interface. It
+// fails with ErrNoTrackList when the player doesn't export the interface.
 func (i *Player) SetTrackListProperty(property string, value any) error {
-	return i.SetProperty(TrackListInterface, property, value)
+	ctx := context.Background()
+	if err := i.requireInterface(ctx, TrackListInterface, ErrNoTrackList); err != nil {
+		return err
+	}
+	return i.SetPropertyContext(ctx, TrackListInterface, property, value)
 }
 
-// SetPlaylistsProperty sets the propertyName from the playlists interface.
+// SetPlaylistsProperty sets the propertyName from the playlists interface. It
+// fails with ErrNoPlaylists when the player doesn't export the interface.
 func (i *Player) SetPlaylistsProperty(property string, value any) error {
-	return i.SetProperty(PlaylistsInterface, property, value)
+	ctx := context.Background()
+	if err := i.requireInterface(ctx, PlaylistsInterface, ErrNoPlaylists); err != nil {
+		return err
+	}
+	return i.SetPropertyContext(ctx, PlaylistsInterface, property, value)
 }
 
 // GetProperty returns the prop in the iface.
 func (i *Player) GetProperty(iface, property string) (dbus.Variant, error) {
+	return i.GetPropertyContext(context.Background(), iface, property)
+}
+
+// GetPropertyContext is like GetProperty but takes a context.
+func (i *Player) GetPropertyContext(
+	ctx context.Context,
+	iface, property string,
+) (dbus.Variant, error) {
+	c := i.propertyCache()
+	if c == nil || !cacheable(iface, property) {
+		return i.readProperty(ctx, iface, property)
+	}
+	v, ok, generation := c.get(iface, property)
+	if ok {
+		return v, nil
+	}
+	v, err := i.readProperty(ctx, iface, property)
+	if err != nil {
+		return dbus.Variant{}, err
+	}
+	c.store(generation, iface, map[string]dbus.Variant{property: v})
+	return v, nil
+}
+
+// readProperty reads the value of iface.property from the player, bypassing
+// the property cache.
+func (i *Player) readProperty(
+	ctx context.Context,
+	iface, property string,
+) (dbus.Variant, error) {
 	result := dbus.Variant{}
-	call := i.obj.Call(GetPropertyMethod, 0, iface, property)
+	call := i.do(ctx, GetPropertyMethod, iface, property)
 	if call.Err != nil {
 		return dbus.Variant{}, fmt.Errorf(
 			"failed to get property %s.%s: %w",
 			iface,
 			property,
-			call.Err,
+			translateError(call.Err),
 		)
 	}
 	if err := call.Store(&result); err != nil {
@@ -80,25 +140,73 @@ func (i *Player) GetPlayerProperty(property string) (dbus.Variant, error) {
 	return i.GetProperty(PlayerInterface, property)
 }
 
-// GetTrackListProperty returns the prop from the tracklist interface.
+// GetTrackListProperty returns the prop from the tracklist interface. It fails
+// with ErrNoTrackList when the player doesn't export the interface.
 func (i *Player) GetTrackListProperty(property string) (dbus.Variant, error) {
-	return i.GetProperty(TrackListInterface, property)
+	ctx := context.Background()
+	if err := i.requireInterface(ctx, TrackListInterface, ErrNoTrackList); err != nil {
+		return dbus.Variant{}, err
+	}
+	return i.GetPropertyContext(ctx, TrackListInterface, property)
 }
 
-// GetPlaylistsProperty returns the prop from the playlists interface.
+// GetPlaylistsProperty returns the prop from the playlists interface. It fails
+// with ErrNoPlaylists when the player doesn't export the interface.
 func (i *Player) GetPlaylistsProperty(property string) (dbus.Variant, error) {
-	return i.GetProperty(PlaylistsInterface, property)
+	ctx := context.Background()
+	if err := i.requireInterface(ctx, PlaylistsInterface, ErrNoPlaylists); err != nil {
+		return dbus.Variant{}, err
+	}
+	return i.GetPropertyContext(ctx, PlaylistsInterface, property)
+}
+
+// GetAllProperties returns every property of iface in a single call.
+func (i *Player) GetAllProperties(iface string) (map[string]dbus.Variant, error) {
+	return i.GetAllPropertiesContext(context.Background(), iface)
+}
+
+// GetAllPropertiesContext is like GetAllProperties but takes a context.
+func (i *Player) GetAllPropertiesContext(
+	ctx context.Context,
+	iface string,
+) (map[string]dbus.Variant, error) {
+	c := i.propertyCache()
+	var generation uint64
+	if c != nil {
+		generation = c.currentGeneration()
+	}
+	result := map[string]dbus.Variant{}
+	call := i.do(ctx, GetAllPropertiesMethod, iface)
+	if call.Err != nil {
+		return nil, fmt.Errorf(
+			"failed to get all properties of %s: %w",
+			iface,
+			translateError(call.Err),
+		)
+	}
+	if err := call.Store(&result); err != nil {
+		return nil, fmt.Errorf(
+			"failed to store all properties of %s: %w",
+			iface,
+			err,
+		)
+	}
+	if c != nil {
+		c.store(generation, iface, result)
+	}
+	return result, nil
 }
 
 // getPropertyCast returns property and casts value using the provided caster
 // function.
 func getPropertyCast[T any](
+	ctx context.Context,
 	i *Player,
 	iface, property string,
 	caster func(any) (T, error),
 ) (T, error) {
 	var v T
-	variant, err := i.GetProperty(iface, property)
+	variant, err := i.GetPropertyContext(ctx, iface, property)
 	if err != nil {
 		return v, err
 	}
@@ -125,68 +233,81 @@ func getPropertyCast[T any](
 // getBasePropertyCast returns base interface property and casts value using the
 // provided caster function.
 func getBasePropertyCast[T any](
+	ctx context.Context,
 	i *Player,
 	property string,
 	caster func(any) (T, error),
 ) (T, error) {
-	return getPropertyCast(i, BaseInterface, property, caster)
+	return getPropertyCast(ctx, i, BaseInterface, property, caster)
 }
 
 // getPlayerPropertyCast returns player interface property and casts value using
 // the provided caster function.
 func getPlayerPropertyCast[T any](
+	ctx context.Context,
 	i *Player,
 	property string,
 	caster func(any) (T, error),
 ) (T, error) {
-	return getPropertyCast(i, PlayerInterface, property, caster)
+	return getPropertyCast(ctx, i, PlayerInterface, property, caster)
 }
 
 // getTrackListPropertyCast returns tracklist interface property and casts value
-// using the provided caster function.
+// using the provided caster function. It fails with ErrNoTrackList when the
+// player doesn't export the interface.
 func getTrackListPropertyCast[T any](
+	ctx context.Context,
 	i *Player,
 	property string,
 	caster func(any) (T, error),
 ) (T, error) {
-	return getPropertyCast(i, TrackListInterface, property, caster)
+	if err := i.requireInterface(ctx, TrackListInterface, ErrNoTrackList); err != nil {
+		var v T
+		return v, err
+	}
+	return getPropertyCast(ctx, i, TrackListInterface, property, caster)
 }
 
 // getPlaylistPropertyCast returns playlists interface property and casts value
-// using the provided caster function.
+// using the provided caster function. It fails with ErrNoPlaylists when the
+// player doesn't export the interface.
 func getPlaylistPropertyCast[T any](
+	ctx context.Context,
 	i *Player,
 	property string,
 	caster func(any) (T, error),
 ) (T, error) {
-	return getPropertyCast(i, PlaylistsInterface, property, caster)
+	if err := i.requireInterface(ctx, PlaylistsInterface, ErrNoPlaylists); err != nil {
+		var v T
+		return v, err
+	}
+	return getPropertyCast(ctx, i, PlaylistsInterface, property, caster)
 }
 
-// getMetadataCast returns metadata value for the given key and casts it using
-// the provided caster function.
-func getMetadataCast[T any](
+// getMetadataValue fetches the metadata and returns the value of key using
+// the provided Metadata getter, such as Metadata.GetString.
+func getMetadataValue[T any](
 	i *Player,
 	key string,
-	caster func(any) (T, error),
+	get func(Metadata, string) (T, error),
 ) (T, error) {
-	var v T
 	m, err := i.GetMetadata()
 	if err != nil {
+		var v T
 		return v, err
 	}
-	val, err := m.Get(key)
+	return get(m, key)
+}
+
+// getOptionalMetadataStrings returns the string list stored under key, or an
+// empty slice when the key is absent.
+func getOptionalMetadataStrings(i *Player, key string) ([]string, error) {
+	m, err := i.GetMetadata()
 	if err != nil {
-		return v, err
+		return nil, err
 	}
-	v, err = caster(val)
-	if err != nil {
-		return v, fmt.Errorf(
-			"%s.Metadata: failed to cast value (%v) of %q: %w",
-			PlayerInterface,
-			val,
-			key,
-			err,
-		)
+	if v, ok := m[key]; !ok || v.Value() == nil {
+		return []string{}, nil
 	}
-	return v, nil
+	return m.GetStringSlice(key)
 }
@@ -0,0 +1,124 @@
+package mpris
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/spf13/cast"
+)
+
+// GetPropertyAs reads iface.property and converts it to T, with the same
+// tolerance the typed getters of Player apply, for properties the Player has
+// no getter for, such as vendor extensions. Supported types are string, bool,
+// every integer and float width, []string, time.Duration, read as
+// microseconds like every MPRIS time, dbus.ObjectPath, dbus.Variant,
+// Metadata, PlaybackStatus and LoopStatus. Integers are accepted in any width
+// and converted unless they overflow T. Other types must match the value
+// exactly.
+func GetPropertyAs[T any](p *Player, iface, property string) (T, error) {
+	return GetPropertyAsContext[T](context.Background(), p, iface, property)
+}
+
+// GetPropertyAsContext is like GetPropertyAs but takes a context.
+func GetPropertyAsContext[T any](
+	ctx context.Context,
+	p *Player,
+	iface, property string,
+) (T, error) {
+	return getPropertyCast(ctx, p, iface, property, casterFor[T]())
+}
+
+// casterFor returns the caster GetPropertyAs converts values to T with.
+func casterFor[T any]() func(any) (T, error) {
+	var zero T
+	switch any(zero).(type) {
+	case string:
+		return castAs[T](cast.ToStringE)
+	case bool:
+		return castAs[T](cast.ToBoolE)
+	case int:
+		return castAs[T](toInteger[int])
+	case int8:
+		return castAs[T](toInteger[int8])
+	case int16:
+		return castAs[T](toInteger[int16])
+	case int32:
+		return castAs[T](toInteger[int32])
+	case int64:
+		return castAs[T](toInt64)
+	case uint:
+		return castAs[T](toInteger[uint])
+	case uint8:
+		return castAs[T](toInteger[uint8])
+	case uint16:
+		return castAs[T](toInteger[uint16])
+	case uint32:
+		return castAs[T](toInteger[uint32])
+	case uint64:
+		return castAs[T](toInteger[uint64])
+	case float32:
+		return castAs[T](cast.ToFloat32E)
+	case float64:
+		return castAs[T](cast.ToFloat64E)
+	case []string:
+		return castAs[T](toStrings)
+	case time.Duration:
+		return castAs[T](microsecondsToDuration)
+	case dbus.ObjectPath:
+		return castAs[T](toObjectPath)
+	case dbus.Variant:
+		return castAs[T](func(a any) (dbus.Variant, error) {
+			return dbus.MakeVariant(a), nil
+		})
+	case Metadata:
+		return castAs[T](toMetadata)
+	case PlaybackStatus:
+		return castAs[T](toPlaybackStatus)
+	case LoopStatus:
+		return castAs[T](toLoopStatus)
+	default:
+		return func(a any) (T, error) {
+			v, ok := a.(T)
+			if !ok {
+				return zero, fmt.Errorf("unable to cast %#v of type %T to %T", a, a, zero)
+			}
+			return v, nil
+		}
+	}
+}
+
+// castAs turns caster, whose result type U is T, into a caster returning T.
+func castAs[T, U any](caster func(any) (U, error)) func(any) (T, error) {
+	return func(a any) (T, error) {
+		v, err := caster(a)
+		return any(v).(T), err
+	}
+}
+
+// integer is the set of integer types toInteger converts to.
+type integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64
+}
+
+// toInteger is toInt64 for integers of type T, rejecting values that don't
+// fit in T.
+func toInteger[T integer](a any) (T, error) {
+	if u, ok := a.(uint64); ok {
+		if v := T(u); v >= 0 && uint64(v) == u {
+			return v, nil
+		}
+		return 0, fmt.Errorf("%d overflows %T", u, T(0))
+	}
+	i, err := toInt64(a)
+	if err != nil {
+		return 0, err
+	}
+	v := T(i)
+	if int64(v) != i || (v < 0) != (i < 0) {
+		return 0, fmt.Errorf("%d overflows %T", i, v)
+	}
+	return v, nil
+}
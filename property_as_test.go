@@ -0,0 +1,66 @@
+package mpris_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Nadim147c/go-mpris"
+	"github.com/Nadim147c/go-mpris/mpristest"
+	"github.com/godbus/dbus/v5"
+)
+
+// vendorInterface is a player-specific interface, like the ones mpv and
+// Spotify ship besides MPRIS.
+const vendorInterface = "org.example.Vendor"
+
+func TestGetPropertyAs(t *testing.T) {
+	fake, player := mpristest.NewFakePlayer(t)
+	fake.Set(vendorInterface, "Count", uint32(42))
+	fake.Set(vendorInterface, "Big", uint64(1<<40))
+	fake.Set(vendorInterface, "Speed", int64(2))
+	fake.Set(vendorInterface, "Tags", []dbus.Variant{dbus.MakeVariant("a"), dbus.MakeVariant("b")})
+	fake.Set(vendorInterface, "Cached", int64(90_000_000))
+	fake.Set(vendorInterface, "Track", "/track/1")
+	fake.Set(vendorInterface, "Enabled", "true")
+	fake.Set(vendorInterface, "Gains", map[string]float64{"a": 1})
+
+	if got, err := mpris.GetPropertyAs[int](player, vendorInterface, "Count"); err != nil || got != 42 {
+		t.Errorf("GetPropertyAs[int](Count) = %v, %v", got, err)
+	}
+	if got, err := mpris.GetPropertyAs[uint8](player, vendorInterface, "Count"); err != nil || got != 42 {
+		t.Errorf("GetPropertyAs[uint8](Count) = %v, %v", got, err)
+	}
+	if got, err := mpris.GetPropertyAs[int8](player, vendorInterface, "Big"); err == nil {
+		t.Errorf("GetPropertyAs[int8](Big) = %v, want an overflow error", got)
+	}
+	if got, err := mpris.GetPropertyAs[float64](player, vendorInterface, "Speed"); err != nil || got != 2 {
+		t.Errorf("GetPropertyAs[float64](Speed) = %v, %v", got, err)
+	}
+	tags, err := mpris.GetPropertyAs[[]string](player, vendorInterface, "Tags")
+	if err != nil || !slices.Equal(tags, []string{"a", "b"}) {
+		t.Errorf("GetPropertyAs[[]string](Tags) = %q, %v", tags, err)
+	}
+	cached, err := mpris.GetPropertyAs[time.Duration](player, vendorInterface, "Cached")
+	if err != nil || cached != 90*time.Second {
+		t.Errorf("GetPropertyAs[time.Duration](Cached) = %v, %v", cached, err)
+	}
+	track, err := mpris.GetPropertyAs[dbus.ObjectPath](player, vendorInterface, "Track")
+	if err != nil || track != "/track/1" {
+		t.Errorf("GetPropertyAs[dbus.ObjectPath](Track) = %v, %v", track, err)
+	}
+	if got, err := mpris.GetPropertyAs[bool](player, vendorInterface, "Enabled"); err != nil || !got {
+		t.Errorf("GetPropertyAs[bool](Enabled) = %v, %v", got, err)
+	}
+	gains, err := mpris.GetPropertyAs[map[string]float64](player, vendorInterface, "Gains")
+	if err != nil || gains["a"] != 1 {
+		t.Errorf("GetPropertyAs[map[string]float64](Gains) = %v, %v", gains, err)
+	}
+	if got, err := mpris.GetPropertyAs[map[string]string](player, vendorInterface, "Gains"); err == nil {
+		t.Errorf("GetPropertyAs[map[string]string](Gains) = %v, want an error", got)
+	}
+	status, err := mpris.GetPropertyAs[mpris.PlaybackStatus](player, mpris.PlayerInterface, "PlaybackStatus")
+	if err != nil || status != mpris.PlaybackStopped {
+		t.Errorf("GetPropertyAs[PlaybackStatus] = %v, %v", status, err)
+	}
+}
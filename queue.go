@@ -0,0 +1,193 @@
+package mpris
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// WithSerializedCalls makes the player send its method calls and property
+// writes one at a time, in the order they were made, so a burst of them,
+// like Next fired by a scroll wheel, can't make a slow player lock up or
+// reorder them. Up to depth calls wait behind the one in progress; more
+// fail with ErrQueueFull without being made. A depth below 1 means no limit.
+// Property reads aren't queued.
+func WithSerializedCalls(depth int) Option {
+	return func(p *Player) {
+		p.serializeCalls = true
+		p.queueDepth = depth
+	}
+}
+
+// WithCoalescedSetters makes a property write that is still waiting in the
+// queue of WithSerializedCalls take the value of a newer write of the same
+// property, instead of queuing that one too. Ten quick SetVolume calls thus
+// set the volume at most twice, the last time to the last value, and every
+// caller gets the result of the write that set their value or a newer one.
+// A caller canceling its context only gives up its own wait, not the write
+// the others are waiting for. Without WithSerializedCalls it has no effect.
+func WithCoalescedSetters() Option {
+	return func(p *Player) {
+		p.coalesceSetters = true
+	}
+}
+
+// queuedCall is a call waiting in a callQueue.
+type queuedCall struct {
+	method string
+	args   []any
+	// property is the interface and name written by property writes, the
+	// key setters are coalesced by.
+	property string
+	// ctxs are the contexts of the callers waiting for the call, and
+	// results receive its outcome, one per caller. They are buffered, so
+	// callers that gave up don't block the queue.
+	ctxs    []context.Context
+	results []chan *dbus.Call
+}
+
+// context returns the context to make c with: the context of its caller,
+// or, for writes coalesced from several callers, one that none of them can
+// cancel for the others, bounded by the earliest deadline among the callers
+// still waiting. It returns nil when every caller gave up.
+func (c *queuedCall) context() (context.Context, context.CancelFunc) {
+	var live []context.Context
+	for _, ctx := range c.ctxs {
+		if ctx.Err() == nil {
+			live = append(live, ctx)
+		}
+	}
+	switch len(live) {
+	case 0:
+		return nil, nil
+	case 1:
+		return live[0], func() {}
+	}
+	var deadline time.Time
+	for _, ctx := range live {
+		d, ok := ctx.Deadline()
+		if ok && (deadline.IsZero() || d.Before(deadline)) {
+			deadline = d
+		}
+	}
+	shared := context.WithoutCancel(live[0])
+	if deadline.IsZero() {
+		return shared, func() {}
+	}
+	return context.WithDeadline(shared, deadline)
+}
+
+// callQueue sends the calls of a player one at a time. Its worker only runs
+// while calls are queued, so a Player that is never closed leaks nothing.
+type callQueue struct {
+	depth    int
+	coalesce bool
+	call     func(ctx context.Context, method string, args ...any) *dbus.Call
+
+	mu sync.Mutex
+	// pending holds the calls waiting behind the one in progress, if
+	// running.
+	pending []*queuedCall
+	running bool
+}
+
+// queued reports whether calls of method go through the queue.
+func queued(method string) bool {
+	return method != GetPropertyMethod && method != GetAllPropertiesMethod
+}
+
+// submit queues method and waits for its result or for ctx to be done.
+func (q *callQueue) submit(
+	ctx context.Context,
+	method string,
+	args ...any,
+) *dbus.Call {
+	result := make(chan *dbus.Call, 1)
+	if err := q.add(ctx, method, args, result); err != nil {
+		return &dbus.Call{Method: method, Args: args, Err: err}
+	}
+	select {
+	case call := <-result:
+		return call
+	case <-ctx.Done():
+		return &dbus.Call{Method: method, Args: args, Err: ctx.Err()}
+	}
+}
+
+// add queues a call delivering its outcome to result, merging it into a
+// waiting write of the same property when setters are coalesced, and starts
+// the worker if it isn't running.
+func (q *callQueue) add(
+	ctx context.Context,
+	method string,
+	args []any,
+	result chan *dbus.Call,
+) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var property string
+	if method == SetPropertyMethod && len(args) >= 2 {
+		property = fmt.Sprint(args[0], ".", args[1])
+	}
+	if q.coalesce && property != "" {
+		for _, c := range q.pending {
+			if c.property == property {
+				c.args = args
+				c.ctxs = append(c.ctxs, ctx)
+				c.results = append(c.results, result)
+				return nil
+			}
+		}
+	}
+	c := &queuedCall{
+		method:   method,
+		args:     args,
+		property: property,
+		ctxs:     []context.Context{ctx},
+		results:  []chan *dbus.Call{result},
+	}
+	if !q.running {
+		q.running = true
+		go q.work(c)
+		return nil
+	}
+	if q.depth > 0 && len(q.pending) >= q.depth {
+		return ErrQueueFull
+	}
+	q.pending = append(q.pending, c)
+	return nil
+}
+
+// work makes c and then the queued calls until there are none left. Callers
+// whose context is done get its error instead of the outcome of the call,
+// which isn't made once all of them gave up.
+func (q *callQueue) work(c *queuedCall) {
+	for {
+		var call *dbus.Call
+		if ctx, cancel := c.context(); ctx != nil {
+			call = q.call(ctx, c.method, c.args...)
+			cancel()
+		}
+		for n, result := range c.results {
+			if err := c.ctxs[n].Err(); err != nil {
+				result <- &dbus.Call{Method: c.method, Args: c.args, Err: err}
+				continue
+			}
+			result <- call
+		}
+
+		q.mu.Lock()
+		if len(q.pending) == 0 {
+			q.running = false
+			q.mu.Unlock()
+			return
+		}
+		c = q.pending[0]
+		q.pending = q.pending[1:]
+		q.mu.Unlock()
+	}
+}
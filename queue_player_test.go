@@ -0,0 +1,87 @@
+package mpris_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Nadim147c/go-mpris"
+	"github.com/Nadim147c/go-mpris/mpristest"
+)
+
+// TestSerializedCalls fires a burst of Next at a slow player and checks it
+// gets them one at a time, while property reads don't wait behind them.
+func TestSerializedCalls(t *testing.T) {
+	const delay = 200 * time.Millisecond
+	fake, _ := mpristest.NewFakePlayer(t)
+	var running, overlapped atomic.Int32
+	fake.Handle("Next", func(*mpristest.FakePlayer, []any) error {
+		if running.Add(1) > 1 {
+			overlapped.Add(1)
+		}
+		time.Sleep(delay)
+		running.Add(-1)
+		return nil
+	})
+	player := mpris.New(
+		fake.Connect(),
+		mpristest.Name,
+		mpris.WithSerializedCalls(0),
+	)
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := player.Next(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	time.Sleep(delay / 4)
+	start := time.Now()
+	if _, err := player.GetIdentity(); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed >= delay {
+		t.Errorf("GetIdentity waited %v behind the queued calls", elapsed)
+	}
+
+	wg.Wait()
+	if n := overlapped.Load(); n > 0 {
+		t.Errorf("%d calls overlapped", n)
+	}
+	if got := len(fake.Calls()); got != 4 {
+		t.Errorf("player got %d calls, want 4", got)
+	}
+}
+
+func TestSerializedCallsQueueFull(t *testing.T) {
+	fake, _ := mpristest.NewFakePlayer(t)
+	fake.Delay("Next", 300*time.Millisecond)
+	player := mpris.New(
+		fake.Connect(),
+		mpristest.Name,
+		mpris.WithSerializedCalls(1),
+	)
+
+	errs := make(chan error, 3)
+	for range 3 {
+		go func() { errs <- player.Next() }()
+	}
+	var full int
+	for range 3 {
+		if err := <-errs; errors.Is(err, mpris.ErrQueueFull) {
+			full++
+		} else if err != nil {
+			t.Error(err)
+		}
+	}
+	if full != 1 {
+		t.Errorf("%d calls failed with ErrQueueFull, want 1", full)
+	}
+}
@@ -0,0 +1,171 @@
+package mpris
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// gatedCalls stands in for the calls of a player. Every call blocks until
+// release is closed, and is recorded as the method and its last argument.
+type gatedCalls struct {
+	started chan struct{}
+	release chan struct{}
+
+	mu    sync.Mutex
+	calls []string
+}
+
+// gatedQueue returns a queue making its calls through a new gatedCalls.
+func gatedQueue(depth int, coalesce bool) (*callQueue, *gatedCalls) {
+	g := &gatedCalls{
+		started: make(chan struct{}, 16),
+		release: make(chan struct{}),
+	}
+	return &callQueue{depth: depth, coalesce: coalesce, call: g.call}, g
+}
+
+func (g *gatedCalls) call(
+	_ context.Context,
+	method string,
+	args ...any,
+) *dbus.Call {
+	g.mu.Lock()
+	g.calls = append(g.calls, fmt.Sprint(method, args[len(args)-1:]))
+	g.mu.Unlock()
+	g.started <- struct{}{}
+	<-g.release
+	return &dbus.Call{Method: method, Args: args}
+}
+
+// made returns the calls made so far.
+func (g *gatedCalls) made() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return slices.Clone(g.calls)
+}
+
+// queueCall adds method with args to q and returns the channel receiving its
+// result.
+func queueCall(
+	t *testing.T,
+	q *callQueue,
+	method string,
+	args ...any,
+) chan *dbus.Call {
+	t.Helper()
+	result := make(chan *dbus.Call, 1)
+	if err := q.add(context.Background(), method, args, result); err != nil {
+		t.Fatalf("queuing %s: %v", method, err)
+	}
+	return result
+}
+
+func TestCallQueueFull(t *testing.T) {
+	q, g := gatedQueue(2, false)
+	first := queueCall(t, q, "Next", 1)
+	<-g.started
+	second := queueCall(t, q, "Next", 2)
+	third := queueCall(t, q, "Next", 3)
+
+	full := make(chan *dbus.Call, 1)
+	err := q.add(context.Background(), "Next", []any{4}, full)
+	if !errors.Is(err, ErrQueueFull) {
+		t.Errorf("add beyond the depth = %v, want ErrQueueFull", err)
+	}
+
+	close(g.release)
+	for _, result := range []chan *dbus.Call{first, second, third} {
+		if call := receive(t, result); call.Err != nil {
+			t.Error(call.Err)
+		}
+	}
+	want := []string{"Next[1]", "Next[2]", "Next[3]"}
+	if got := g.made(); !slices.Equal(got, want) {
+		t.Errorf("calls = %v, want %v", got, want)
+	}
+}
+
+func TestCallQueueCoalesce(t *testing.T) {
+	q, g := gatedQueue(1, true)
+	set := func(v float64) chan *dbus.Call {
+		return queueCall(t, q, SetPropertyMethod, PlayerInterface, "Volume", v)
+	}
+	results := []chan *dbus.Call{set(0.1)}
+	<-g.started
+	for _, v := range []float64{0.2, 0.3, 0.4} {
+		results = append(results, set(v))
+	}
+
+	close(g.release)
+	for _, result := range results {
+		receive(t, result)
+	}
+	want := []string{
+		SetPropertyMethod + "[0.1]",
+		SetPropertyMethod + "[0.4]",
+	}
+	if got := g.made(); !slices.Equal(got, want) {
+		t.Errorf("calls = %v, want %v", got, want)
+	}
+}
+
+func TestCallQueueCoalesceCanceled(t *testing.T) {
+	q, g := gatedQueue(0, true)
+	set := func(ctx context.Context, v float64) chan *dbus.Call {
+		result := make(chan *dbus.Call, 1)
+		args := []any{PlayerInterface, "Volume", v}
+		if err := q.add(ctx, SetPropertyMethod, args, result); err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+	first := set(context.Background(), 0.1)
+	<-g.started
+	waiting := set(context.Background(), 0.2)
+	ctx, cancel := context.WithCancel(context.Background())
+	last := set(ctx, 0.3)
+	cancel()
+
+	close(g.release)
+	receive(t, first)
+	if call := receive(t, waiting); call.Err != nil {
+		t.Errorf("waiting write err = %v, want it made", call.Err)
+	}
+	if call := receive(t, last); !errors.Is(call.Err, context.Canceled) {
+		t.Errorf("canceled write err = %v, want context.Canceled", call.Err)
+	}
+	want := []string{
+		SetPropertyMethod + "[0.1]",
+		SetPropertyMethod + "[0.3]",
+	}
+	if got := g.made(); !slices.Equal(got, want) {
+		t.Errorf("calls = %v, want %v", got, want)
+	}
+}
+
+func TestCallQueueCanceled(t *testing.T) {
+	q, g := gatedQueue(0, false)
+	first := queueCall(t, q, "Next", 1)
+	<-g.started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	canceled := make(chan *dbus.Call, 1)
+	if err := q.add(ctx, "Next", []any{2}, canceled); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	close(g.release)
+	receive(t, first)
+	if call := receive(t, canceled); !errors.Is(call.Err, context.Canceled) {
+		t.Errorf("canceled call err = %v, want context.Canceled", call.Err)
+	}
+	if got := g.made(); !slices.Equal(got, []string{"Next[1]"}) {
+		t.Errorf("calls = %v, want only the first", got)
+	}
+}
@@ -0,0 +1,135 @@
+package mpris
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/spf13/cast"
+)
+
+// Quirks lists the workarounds a Player applies for the application it talks
+// to, because it deviates from the MPRIS spec. Integer values of any width are
+// accepted from every player, so they need no quirk.
+type Quirks struct {
+	// Name is the application the quirks were picked for, such as "spotify",
+	// empty when the player needs none.
+	Name string
+	// SeekFallback makes SetPosition seek by the distance to the current
+	// position when the player rejects the track ID of the current track,
+	// like Spotify does with its /com/spotify/track/... IDs on some versions.
+	SeekFallback bool
+	// SeekedPosition makes GetPosition estimate the position from the last
+	// Seeked signal, advanced by the clock while playing, instead of reading
+	// the Position property, which Spotify updates with a lag of seconds.
+	// The property is still read until the first Seeked signal arrives.
+	SeekedPosition bool
+}
+
+// knownQuirks holds the quirks of the applications known to need any, by
+// lowercase name. The name is matched against the bus name without the
+// org.mpris.MediaPlayer2 prefix, the desktop entry and the identity.
+var knownQuirks = map[string]Quirks{
+	"spotify": {
+		Name:           "spotify",
+		SeekFallback:   true,
+		SeekedPosition: true,
+	},
+}
+
+// WithoutQuirks turns off the workarounds of Quirks, so the player is treated
+// as following the spec.
+func WithoutQuirks() Option {
+	return func(p *Player) {
+		p.noQuirks = true
+	}
+}
+
+// Quirks returns the workarounds active for the player. They are picked by
+// the bus name of the player, or else by its desktop entry and identity,
+// which are read the first time Quirks is needed. When they can't be read, no
+// quirks apply, and they aren't read again, so players failing GetAll don't
+// pay a round trip on every use; only a read cut short by its context or the
+// call timeout is retried.
+// Players created with WithoutQuirks have none.
+func (i *Player) Quirks() Quirks {
+	return i.QuirksContext(context.Background())
+}
+
+// QuirksContext is like Quirks but takes a context.
+func (i *Player) QuirksContext(ctx context.Context) Quirks {
+	if i.noQuirks {
+		return Quirks{}
+	}
+	if q, ok := knownQuirks[strings.ToLower(i.ShortName())]; ok {
+		return q
+	}
+	i.mu.Lock()
+	q := i.quirks
+	i.mu.Unlock()
+	if q != nil {
+		return *q
+	}
+
+	base, err := i.GetAllPropertiesContext(ctx, BaseInterface)
+	if errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded) {
+		return Quirks{}
+	}
+	resolved := Quirks{}
+	if err == nil {
+		resolved = resolveQuirks(base)
+	}
+	i.mu.Lock()
+	i.quirks = &resolved
+	i.mu.Unlock()
+	return resolved
+}
+
+// resolveQuirks returns the quirks of the application described by the
+// properties of the base interface.
+func resolveQuirks(base map[string]dbus.Variant) Quirks {
+	for _, property := range []string{"DesktopEntry", "Identity"} {
+		v, ok := base[property]
+		if !ok {
+			continue
+		}
+		name, err := cast.ToStringE(v.Value())
+		if err != nil {
+			continue
+		}
+		name = strings.TrimSuffix(path.Base(name), ".desktop")
+		if q, ok := knownQuirks[strings.ToLower(name)]; ok {
+			return q
+		}
+	}
+	return Quirks{}
+}
+
+// seekFallback moves the playback position to position with a relative seek,
+// for players rejecting the track ID SetPosition needs. err is the error
+// SetPosition failed with, returned when the player can't seek either.
+func (i *Player) seekFallback(
+	ctx context.Context,
+	position time.Duration,
+	err error,
+) error {
+	current, perr := i.GetPositionContext(ctx)
+	if perr != nil {
+		return errors.Join(err, perr)
+	}
+	if serr := i.SeekContext(ctx, position-current); serr != nil {
+		return errors.Join(err, serr)
+	}
+	return nil
+}
+
+// rejectedTrackID reports whether err means the player rejected the arguments
+// of SetPosition, rather than being gone or the call being canceled.
+func rejectedTrackID(err error) bool {
+	name, ok := dbusErrorName(err)
+	return ok && name == "org.freedesktop.DBus.Error.InvalidArgs"
+}
@@ -0,0 +1,270 @@
+package mpris_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Nadim147c/go-mpris"
+	"github.com/Nadim147c/go-mpris/mpristest"
+	"github.com/godbus/dbus/v5"
+)
+
+// quirkyTrack is the metadata the quirk tests give the fake player.
+var quirkyTrack = mpris.Metadata{
+	"mpris:trackid": dbus.MakeVariant(dbus.ObjectPath("/track/1")),
+	"mpris:length":  dbus.MakeVariant(int64(90_000_000)),
+	"xesam:artist":  dbus.MakeVariant([]string{"Artist"}),
+}
+
+func TestQuirkLengthAsUint64(t *testing.T) {
+	fake, player := mpristest.NewFakePlayer(t)
+	fake.SetQuirk(mpristest.LengthAsUint64)
+	fake.SetMetadata(quirkyTrack)
+
+	if length, err := player.GetLength(); err != nil || length != 90*time.Second {
+		t.Errorf("GetLength() = %v, %v, want 1m30s", length, err)
+	}
+}
+
+func TestQuirkArtistAsString(t *testing.T) {
+	fake, player := mpristest.NewFakePlayer(t)
+	fake.SetQuirk(mpristest.ArtistAsString)
+	fake.SetMetadata(quirkyTrack)
+
+	metadata, err := player.GetMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	artist, err := metadata.GetStringSlice("xesam:artist")
+	if err != nil || !slices.Equal(artist, []string{"Artist"}) {
+		t.Errorf("artist = %q, %v, want [Artist]", artist, err)
+	}
+}
+
+func TestQuirkTrackIDAsString(t *testing.T) {
+	fake, player := mpristest.NewFakePlayer(t)
+	fake.SetQuirk(mpristest.TrackIDAsString)
+	fake.SetMetadata(quirkyTrack)
+
+	if id, err := player.GetTrackID(); err != nil || id != "/track/1" {
+		t.Errorf("GetTrackID() = %q, %v, want /track/1", id, err)
+	}
+}
+
+func TestQuirkSeekedAsInt32(t *testing.T) {
+	fake, player := mpristest.NewFakePlayer(t)
+	fake.SetQuirk(mpristest.SeekedAsInt32)
+	seeked := make(chan time.Duration, 1)
+	sub, err := player.WatchSeeked(seeked)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	fake.EmitSeeked(3 * time.Second)
+	select {
+	case position := <-seeked:
+		if position != 3*time.Second {
+			t.Errorf("Seeked position = %v, want 3s", position)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Seeked with an int32 position was dropped")
+	}
+}
+
+func TestQuirkInvalidatesOnly(t *testing.T) {
+	fake, player := mpristest.NewFakePlayer(t)
+	fake.SetQuirk(mpristest.InvalidatesOnly)
+	changed := make(chan mpris.Metadata, 1)
+	sub, err := player.Subscribe(t.Context(), mpris.MetadataChanged(
+		func(m mpris.Metadata) { changed <- m },
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	fake.SetMetadata(quirkyTrack)
+	select {
+	case m := <-changed:
+		if id, _ := m.GetObjectPath("mpris:trackid"); id != "/track/1" {
+			t.Errorf("trackid = %q, want the value read back", id)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("invalidated Metadata not reported")
+	}
+}
+
+func TestQuirkDroppedProperty(t *testing.T) {
+	fake, player := mpristest.NewFakePlayer(t)
+	fake.DropProperty("LoopStatus")
+
+	_, err := player.GetLoopStatus()
+	if !errors.Is(err, mpris.ErrUnknownProperty) {
+		t.Errorf("GetLoopStatus() error = %v, want ErrUnknownProperty", err)
+	}
+}
+
+func TestQuirkDelay(t *testing.T) {
+	fake, _ := mpristest.NewFakePlayer(t)
+	fake.Delay("Metadata", 2*time.Second)
+	player := mpris.New(
+		fake.Connect(),
+		mpristest.Name,
+		mpris.WithCallTimeout(100*time.Millisecond),
+	)
+
+	start := time.Now()
+	_, err := player.GetMetadata()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetMetadata() error = %v, want DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("GetMetadata() returned after %v, want the call timeout", elapsed)
+	}
+}
+
+func TestQuirkPresets(t *testing.T) {
+	for name, preset := range mpristest.Presets {
+		t.Run(name, func(t *testing.T) {
+			fake, player := mpristest.NewFakePlayer(t)
+			fake.Imitate(preset)
+			fake.SetMetadata(quirkyTrack)
+
+			if identity, _ := player.GetIdentity(); identity != preset.Identity {
+				t.Errorf("GetIdentity() = %q, want %q", identity, preset.Identity)
+			}
+			if length, err := player.GetLength(); err != nil || length != 90*time.Second {
+				t.Errorf("GetLength() = %v, %v, want 1m30s", length, err)
+			}
+			if id, err := player.GetTrackID(); err != nil || id != "/track/1" {
+				t.Errorf("GetTrackID() = %q, %v, want /track/1", id, err)
+			}
+			if _, err := player.Status(); err != nil {
+				t.Errorf("Status() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestFirefoxStatus(t *testing.T) {
+	fake, player := mpristest.NewFakePlayer(t)
+	fake.Imitate(mpristest.Presets["firefox"])
+	fake.SetPlayer("PlaybackStatus", string(mpris.PlaybackPlaying))
+
+	status, err := player.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Identity != "Mozilla Firefox" || status.PlaybackStatus != mpris.PlaybackPlaying {
+		t.Errorf("Status() = %+v", status)
+	}
+	want := []string{mpris.PlayerInterface + ".LoopStatus", mpris.PlayerInterface + ".Shuffle"}
+	if !slices.Equal(status.Missing, want) {
+		t.Errorf("Missing = %q, want %q", status.Missing, want)
+	}
+	if status.Errors != nil {
+		t.Errorf("Errors = %v, want none", status.Errors)
+	}
+	if caps, err := player.GetCapabilities(); err != nil || !caps.CanPlay {
+		t.Errorf("GetCapabilities() = %+v, %v", caps, err)
+	}
+}
+
+func TestFirefoxSetters(t *testing.T) {
+	fake, player := mpristest.NewFakePlayer(t)
+	fake.Imitate(mpristest.Presets["firefox"])
+
+	setters := map[string]func() error{
+		"SetLoopStatus": func() error { return player.SetLoopStatus(mpris.LoopTrack) },
+		"SetShuffle":    func() error { return player.SetShuffle(true) },
+		"SetRate":       func() error { return player.SetRate(1) },
+	}
+	for name, set := range setters {
+		if err := set(); !errors.Is(err, mpris.ErrNotSupported) {
+			t.Errorf("%s() error = %v, want ErrNotSupported", name, err)
+		}
+	}
+	if _, err := player.ToggleShuffle(); !errors.Is(err, mpris.ErrNotSupported) {
+		t.Errorf("ToggleShuffle() error = %v, want ErrNotSupported", err)
+	}
+}
+
+func TestFailingGetAll(t *testing.T) {
+	fake, player := mpristest.NewFakePlayer(t)
+	fake.Imitate(mpristest.Presets["firefox"])
+	fake.SetQuirk(mpristest.FailingGetAll)
+	fake.SetPlayer("PlaybackStatus", string(mpris.PlaybackPaused))
+
+	status, err := player.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.PlaybackStatus != mpris.PlaybackPaused || !status.CanPlay {
+		t.Errorf("Status() = %+v", status)
+	}
+	for _, name := range []string{"LoopStatus", "Shuffle"} {
+		property := mpris.PlayerInterface + "." + name
+		if !slices.Contains(status.Missing, property) {
+			t.Errorf("Missing = %q, want %s", status.Missing, property)
+		}
+		if err := status.Errors[property]; !errors.Is(err, mpris.ErrNotSupported) {
+			t.Errorf("Errors[%s] = %v, want ErrNotSupported", property, err)
+		}
+	}
+	// The base interface fails too, so Identity is missing with its error.
+	if err := status.Errors[mpris.BaseInterface+".Identity"]; err == nil {
+		t.Error("Errors has no error for Identity")
+	}
+
+	if caps, err := player.GetCapabilities(); err != nil || !caps.CanPlay || !caps.CanSeek {
+		t.Errorf("GetCapabilities() = %+v, %v", caps, err)
+	}
+}
+
+// countingMetrics is a Metrics counting the calls of each member.
+type countingMetrics struct {
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (m *countingMetrics) ObserveCall(_, member string, _ time.Duration, _ error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls[member]++
+}
+
+func (m *countingMetrics) ObserveSignal(string, string) {}
+
+func (m *countingMetrics) count(member string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.calls[member]
+}
+
+func TestQuirksFailingGetAll(t *testing.T) {
+	fake, _ := mpristest.NewFakePlayer(t)
+	fake.SetQuirk(mpristest.FailingGetAll)
+	m := &countingMetrics{calls: map[string]int{}}
+	player := mpris.New(fake.Connect(), mpristest.Name, mpris.WithMetrics(m))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if q := player.QuirksContext(ctx); q != (mpris.Quirks{}) {
+		t.Errorf("QuirksContext(canceled) = %+v, want none", q)
+	}
+	canceled := m.count("GetAll")
+	for range 3 {
+		if q := player.Quirks(); q != (mpris.Quirks{}) {
+			t.Errorf("Quirks() = %+v, want none", q)
+		}
+	}
+	// The canceled read is retried, but the failure is kept.
+	if n := m.count("GetAll") - canceled; n != 1 {
+		t.Errorf("GetAll made %d times after the canceled read, want once", n)
+	}
+}
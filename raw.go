@@ -0,0 +1,53 @@
+package mpris
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// Connection returns the connection the player was created with. It is nil
+// for players that don't talk to the bus through a *dbus.Conn.
+func (i *Player) Connection() *dbus.Conn {
+	return i.conn
+}
+
+// Object returns the object the player exports MPRIS at, for calls the Player
+// has no method for. Calls made through it don't get the timeout and flags of
+// the Player; use Call for that. It is nil when Connection is.
+func (i *Player) Object() dbus.BusObject {
+	if i.conn == nil {
+		return nil
+	}
+	return i.conn.Object(i.name, i.path)
+}
+
+// Call calls method of the interface iface on the player object, such as an
+// extension a player ships besides MPRIS, with the timeout, flags and call
+// queue of the Player. WithRetry only retries the idempotent reads among
+// them, Properties Get and GetAll and Introspect, so other methods are made
+// once. The error is translated like the errors of the other methods, so it
+// matches ErrUnknownMethod, ErrPlayerGone and the like; the call is returned
+// either way, to read the reply with Store.
+func (i *Player) Call(iface, method string, args ...any) (*dbus.Call, error) {
+	return i.CallContext(context.Background(), iface, method, args...)
+}
+
+// CallContext is like Call but takes a context.
+func (i *Player) CallContext(
+	ctx context.Context,
+	iface, method string,
+	args ...any,
+) (*dbus.Call, error) {
+	method = iface + "." + method
+	call := i.do(ctx, method, args...)
+	if call.Err != nil {
+		return call, fmt.Errorf(
+			"failed to call %s: %w",
+			method,
+			translateError(call.Err),
+		)
+	}
+	return call, nil
+}
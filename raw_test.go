@@ -0,0 +1,51 @@
+package mpris_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Nadim147c/go-mpris"
+	"github.com/Nadim147c/go-mpris/mpristest"
+	"github.com/godbus/dbus/v5"
+)
+
+func TestCall(t *testing.T) {
+	fake, player := mpristest.NewFakePlayer(t)
+
+	if _, err := player.Call(mpris.PlayerInterface, "Seek", int64(5_000_000)); err != nil {
+		t.Fatal(err)
+	}
+	calls := fake.Calls()
+	if len(calls) != 1 || calls[0].Method != "Seek" || calls[0].Args[0] != int64(5_000_000) {
+		t.Errorf("Calls() = %v, want Seek(5000000)", calls)
+	}
+
+	call, err := player.Call("org.freedesktop.DBus.Properties", "Get", mpris.BaseInterface, "Identity")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var identity dbus.Variant
+	if err := call.Store(&identity); err != nil || identity.Value() != "Fake Player" {
+		t.Errorf("Identity = %v, %v", identity, err)
+	}
+
+	_, err = player.Call(mpris.PlayerInterface, "Explode")
+	if !errors.Is(err, mpris.ErrUnknownMethod) {
+		t.Errorf("Call(Explode) error = %v, want ErrUnknownMethod", err)
+	}
+}
+
+func TestObject(t *testing.T) {
+	_, player := mpristest.NewFakePlayer(t)
+	if player.Connection() == nil {
+		t.Fatal("Connection() = nil")
+	}
+	obj := player.Object()
+	if obj.Destination() != mpristest.Name || obj.Path() != mpris.DBusObjectPath {
+		t.Errorf("Object() = %s %s", obj.Destination(), obj.Path())
+	}
+	v, err := obj.GetProperty(mpris.BaseInterface + ".Identity")
+	if err != nil || v.Value() != "Fake Player" {
+		t.Errorf("Object().GetProperty(Identity) = %v, %v", v, err)
+	}
+}
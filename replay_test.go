@@ -0,0 +1,523 @@
+package mpris
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+var (
+	update = flag.Bool(
+		"update",
+		false,
+		"rewrite testdata/replay/fixture.json from the test fixture",
+	)
+	record = flag.String(
+		"record",
+		"",
+		"record a session with the named player, e.g. spotify, on the bus of "+
+			realBusAddressEnv+" to testdata/replay/<name>.json",
+	)
+)
+
+// replayDir holds the recorded sessions TestReplay replays.
+const replayDir = "testdata/replay"
+
+// replayWait is how long the replay scenario waits for MetadataChanged after
+// Next.
+const replayWait = 5 * time.Second
+
+// recording is a session with a player: the calls made with their replies,
+// the signals received, and the results of the scenario.
+type recording struct {
+	Player  string
+	Calls   []recordedCall
+	Signals []recordedSignal
+	Want    map[string]string
+}
+
+// recordedCall is a call and its reply, or its error.
+type recordedCall struct {
+	Dest   string
+	Path   dbus.ObjectPath
+	Method string
+	Args   []recordedValue `json:",omitempty"`
+	Reply  []recordedValue `json:",omitempty"`
+	// Error is the D-Bus name of the error, empty for other errors, like a
+	// timeout, whose message is then in Reply.
+	Error  string `json:",omitempty"`
+	Failed bool   `json:",omitempty"`
+}
+
+// recordedSignal is a signal, received after the call numbered After.
+type recordedSignal struct {
+	After  int
+	Sender string
+	Path   dbus.ObjectPath
+	Name   string
+	Body   []recordedValue `json:",omitempty"`
+}
+
+// recordedValue is a D-Bus value in the GVariant text format, which keeps
+// the exact type, e.g. uint64 lengths.
+type recordedValue struct {
+	Signature string
+	Value     string
+}
+
+func encodeValues(values []any) []recordedValue {
+	var encoded []recordedValue
+	for _, v := range values {
+		variant := dbus.MakeVariant(v)
+		encoded = append(encoded, recordedValue{
+			variant.Signature().String(),
+			variant.String(),
+		})
+	}
+	return encoded
+}
+
+func decodeValues(values []recordedValue) ([]any, error) {
+	decoded := []any{}
+	for _, v := range values {
+		sig, err := dbus.ParseSignature(v.Signature)
+		if err != nil {
+			return nil, err
+		}
+		variant, err := dbus.ParseVariant(v.Value, sig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", v.Value, err)
+		}
+		decoded = append(decoded, variant.Value())
+	}
+	return decoded, nil
+}
+
+// recorder is a transport recording the calls and signals going through
+// another one.
+type recorder struct {
+	tr transport
+
+	mu    sync.Mutex
+	rec   recording
+	stops map[chan<- *dbus.Signal]chan struct{}
+}
+
+func newRecorder(tr transport) *recorder {
+	return &recorder{tr: tr, stops: map[chan<- *dbus.Signal]chan struct{}{}}
+}
+
+func (r *recorder) call(
+	ctx context.Context,
+	dest string,
+	path dbus.ObjectPath,
+	method string,
+	flags dbus.Flags,
+	args ...any,
+) *dbus.Call {
+	call := r.tr.call(ctx, dest, path, method, flags, args...)
+	c := recordedCall{
+		Dest:   dest,
+		Path:   path,
+		Method: method,
+		Args:   encodeValues(args),
+		Reply:  encodeValues(call.Body),
+	}
+	var dbusErr dbus.Error
+	switch {
+	case errors.As(call.Err, &dbusErr):
+		c.Error, c.Reply, c.Failed = dbusErr.Name, encodeValues(dbusErr.Body), true
+	case call.Err != nil:
+		c.Reply, c.Failed = encodeValues([]any{call.Err.Error()}), true
+	}
+	r.mu.Lock()
+	r.rec.Calls = append(r.rec.Calls, c)
+	r.mu.Unlock()
+	return call
+}
+
+func (r *recorder) addMatchSignal(rule ...dbus.MatchOption) error {
+	return r.tr.addMatchSignal(rule...)
+}
+
+func (r *recorder) removeMatchSignal(rule ...dbus.MatchOption) error {
+	return r.tr.removeMatchSignal(rule...)
+}
+
+func (r *recorder) signal(ch chan<- *dbus.Signal) {
+	in := make(chan *dbus.Signal, 64)
+	stop := make(chan struct{})
+	r.mu.Lock()
+	r.stops[ch] = stop
+	r.mu.Unlock()
+	r.tr.signal(in)
+	go func() {
+		for {
+			select {
+			case <-stop:
+				r.tr.removeSignal(in)
+				return
+			case sig, ok := <-in:
+				if !ok {
+					close(ch)
+					return
+				}
+				r.mu.Lock()
+				r.rec.Signals = append(r.rec.Signals, recordedSignal{
+					After:  len(r.rec.Calls),
+					Sender: sig.Sender,
+					Path:   sig.Path,
+					Name:   sig.Name,
+					Body:   encodeValues(sig.Body),
+				})
+				r.mu.Unlock()
+				ch <- sig
+			}
+		}
+	}()
+}
+
+func (r *recorder) removeSignal(ch chan<- *dbus.Signal) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if stop, ok := r.stops[ch]; ok {
+		close(stop)
+		delete(r.stops, ch)
+	}
+}
+
+func (r *recorder) connected() bool {
+	return r.tr.connected()
+}
+
+// recording returns what was recorded so far.
+func (r *recorder) recording() recording {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rec := r.rec
+	rec.Calls = slices.Clone(rec.Calls)
+	rec.Signals = slices.Clone(rec.Signals)
+	return rec
+}
+
+// replayer is a transport answering calls with the replies of a recording.
+// A call gets the reply of the first unused recorded call with the same
+// destination, path, method and arguments. The recorded signals are
+// delivered once as many calls as before their arrival were made.
+type replayer struct {
+	mu        sync.Mutex
+	rec       recording
+	used      []bool
+	calls     int
+	delivered int
+	chans     []chan<- *dbus.Signal
+}
+
+func newReplayer(rec recording) *replayer {
+	return &replayer{rec: rec, used: make([]bool, len(rec.Calls))}
+}
+
+func (r *replayer) call(
+	_ context.Context,
+	dest string,
+	path dbus.ObjectPath,
+	method string,
+	_ dbus.Flags,
+	args ...any,
+) *dbus.Call {
+	call := &dbus.Call{Destination: dest, Path: path, Method: method, Args: args}
+	encoded := encodeValues(args)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for n, c := range r.rec.Calls {
+		if r.used[n] || c.Dest != dest || c.Path != path || c.Method != method ||
+			!reflect.DeepEqual(c.Args, encoded) {
+			continue
+		}
+		r.used[n] = true
+		r.calls++
+		body, err := decodeValues(c.Reply)
+		switch {
+		case err != nil:
+			call.Err = err
+		case c.Error != "":
+			call.Err = dbus.Error{Name: c.Error, Body: body}
+		case c.Failed:
+			call.Err = errors.New(fmt.Sprint(body...))
+		default:
+			call.Body = body
+		}
+		r.deliver()
+		return call
+	}
+	call.Err = fmt.Errorf("replay: unexpected call %s %v on %s", method, args, dest)
+	return call
+}
+
+// deliver sends the signals due to the registered channels. Signals wait
+// while no channel is registered. r.mu must be held.
+func (r *replayer) deliver() {
+	if len(r.chans) == 0 {
+		return
+	}
+	for _, s := range r.rec.Signals[r.delivered:] {
+		if s.After > r.calls {
+			return
+		}
+		r.delivered++
+		body, err := decodeValues(s.Body)
+		if err != nil {
+			continue
+		}
+		sig := &dbus.Signal{Sender: s.Sender, Path: s.Path, Name: s.Name, Body: body}
+		for _, ch := range r.chans {
+			ch <- sig
+		}
+	}
+}
+
+func (r *replayer) addMatchSignal(...dbus.MatchOption) error    { return nil }
+func (r *replayer) removeMatchSignal(...dbus.MatchOption) error { return nil }
+
+func (r *replayer) signal(ch chan<- *dbus.Signal) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.chans = append(r.chans, ch)
+	r.deliver()
+}
+
+func (r *replayer) removeSignal(ch chan<- *dbus.Signal) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.chans = slices.DeleteFunc(r.chans, func(c chan<- *dbus.Signal) bool {
+		return c == ch
+	})
+}
+
+func (r *replayer) connected() bool { return true }
+
+// replayScenario reads the player the way applications do, then skips to the
+// next track, and returns the results by name.
+func replayScenario(p *Player) map[string]string {
+	got := map[string]string{}
+	note := func(name string, v any, err error) {
+		if err != nil {
+			got[name] = "error: " + err.Error()
+			return
+		}
+		got[name] = fmt.Sprint(v)
+	}
+
+	identity, err := p.GetIdentity()
+	note("Identity", identity, err)
+	status, err := p.GetPlaybackStatus()
+	note("PlaybackStatus", status, err)
+	track, err := p.GetTrackMetadata()
+	note("Track", fmt.Sprintf(
+		"%s %q by %q, %v",
+		track.TrackID,
+		track.Title,
+		track.Artists,
+		track.Length,
+	), err)
+	length, err := p.GetLength()
+	note("Length", length, err)
+	volume, err := p.GetVolume()
+	note("Volume", volume, err)
+	caps, err := p.GetCapabilities()
+	note("Capabilities", fmt.Sprintf("%+v", caps), err)
+	snapshot, err := p.Status()
+	note("Status.Missing", snapshot.Missing, err)
+
+	changed := make(chan Metadata, 1)
+	sub, err := p.Subscribe(context.Background(), MetadataChanged(func(m Metadata) {
+		select {
+		case changed <- m:
+		default:
+		}
+	}))
+	if err != nil {
+		note("Next", nil, err)
+		return got
+	}
+	defer sub.Close()
+	if err := p.Next(); err != nil {
+		note("Next", nil, err)
+		return got
+	}
+	select {
+	case m := <-changed:
+		title, err := m.GetString("xesam:title")
+		note("Next", title, err)
+	case <-time.After(replayWait):
+		got["Next"] = "no MetadataChanged"
+	}
+	return got
+}
+
+// nextObject answers Next for the fixture by announcing the next track.
+type nextObject struct{ props *testProperties }
+
+func (o nextObject) Next() *dbus.Error {
+	o.props.set(PlayerInterface, "Metadata", map[string]dbus.Variant{
+		"mpris:trackid": dbus.MakeVariant(dbus.ObjectPath("/track/2")),
+		"mpris:length":  dbus.MakeVariant(uint64(200_000_000)),
+		"xesam:title":   dbus.MakeVariant("Second"),
+	})
+	return nil
+}
+
+// recordFixture records the scenario against a player on a private bus that,
+// like Spotify, sends lengths as uint64 and lacks LoopStatus and Shuffle.
+func recordFixture(t *testing.T) recording {
+	t.Helper()
+	server, player := testBus(t)
+	props := exportTestProperties(t, server, map[string]map[string]any{
+		BaseInterface: {
+			"Identity":            "Fixture",
+			"CanQuit":             false,
+			"CanRaise":            false,
+			"HasTrackList":        false,
+			"SupportedUriSchemes": []string{},
+			"SupportedMimeTypes":  []string{},
+		},
+		PlayerInterface: {
+			"PlaybackStatus": "Playing",
+			"Metadata": map[string]dbus.Variant{
+				"mpris:trackid": dbus.MakeVariant(dbus.ObjectPath("/track/1")),
+				"mpris:length":  dbus.MakeVariant(uint64(180_000_000)),
+				"xesam:title":   dbus.MakeVariant("First"),
+				"xesam:artist":  dbus.MakeVariant([]string{"Artist"}),
+			},
+			"Volume":        0.5,
+			"Position":      int64(1_000_000),
+			"Rate":          1.0,
+			"MinimumRate":   1.0,
+			"MaximumRate":   1.0,
+			"CanGoNext":     true,
+			"CanGoPrevious": false,
+			"CanPlay":       true,
+			"CanPause":      true,
+			"CanSeek":       true,
+			"CanControl":    true,
+		},
+	})
+	err := server.Export(nextObject{props}, DBusObjectPath, PlayerInterface)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return recordSession(t, player.conn, testPlayerName)
+}
+
+// recordSession runs the scenario against the player name on conn and
+// returns the recording.
+func recordSession(t *testing.T, conn *dbus.Conn, name string) recording {
+	t.Helper()
+	r := newRecorder(connTransport{conn})
+	p, err := newTransportPlayer(r, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := replayScenario(p)
+	rec := r.recording()
+	rec.Player, rec.Want = name, want
+	return rec
+}
+
+func writeRecording(t *testing.T, file string, rec recording) {
+	t.Helper()
+	data, err := json.MarshalIndent(rec, "", "\t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, append(data, '\n'), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// replay runs the scenario against rec and compares the results with the
+// recorded ones.
+func replay(t *testing.T, rec recording) {
+	t.Helper()
+	p, err := newTransportPlayer(newReplayer(rec), rec.Player)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := replayScenario(p)
+	for _, name := range slices.Sorted(maps.Keys(rec.Want)) {
+		if got[name] != rec.Want[name] {
+			t.Errorf("%s = %q, recorded %q", name, got[name], rec.Want[name])
+		}
+	}
+}
+
+func TestRecordReplay(t *testing.T) {
+	rec := recordFixture(t)
+	if rec.Want["Next"] != "Second" {
+		t.Fatalf("scenario results = %q, want the second track after Next", rec.Want)
+	}
+	if rec.Want["Length"] != "3m0s" {
+		t.Errorf("Length = %q, want the uint64 length decoded", rec.Want["Length"])
+	}
+	if *update {
+		writeRecording(t, filepath.Join(replayDir, "fixture.json"), rec)
+	}
+
+	// Round trip through JSON, like the golden files.
+	data, err := json.Marshal(rec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded recording
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	replay(t, decoded)
+}
+
+func TestRecordRealPlayer(t *testing.T) {
+	if *record == "" {
+		t.Skip("no player to record given with -record")
+	}
+	rec := recordSession(t, realBusConn(t), BaseInterface+"."+*record)
+	writeRecording(t, filepath.Join(replayDir, *record+".json"), rec)
+}
+
+func TestReplay(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join(replayDir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no recorded sessions found")
+	}
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var rec recording
+			if err := json.Unmarshal(data, &rec); err != nil {
+				t.Fatal(err)
+			}
+			replay(t, rec)
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package mpris
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// currentTrack returns the metadata of the current track and its length. The
+// error wraps ErrUnknownLength when the length is missing or 0.
+func (i *Player) currentTrack(ctx context.Context) (Metadata, time.Duration, error) {
+	m, err := i.GetMetadataContext(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	length, err := m.getLength("mpris:length")
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %w", ErrUnknownLength, err)
+	}
+	if length == 0 {
+		return nil, 0, fmt.Errorf("%w: mpris:length is 0", ErrUnknownLength)
+	}
+	return m, length, nil
+}
+
+// clampPercent returns pct limited to [0, 100].
+func clampPercent(pct float64) float64 {
+	return min(max(pct, 0), 100)
+}
+
+// GetPositionPercent returns the playback position as a percentage of the
+// length of the current track, between 0 and 100. The error wraps
+// ErrUnknownLength when the player doesn't report the length.
+func (i *Player) GetPositionPercent() (float64, error) {
+	return i.GetPositionPercentContext(context.Background())
+}
+
+// GetPositionPercentContext is like GetPositionPercent but takes a context.
+func (i *Player) GetPositionPercentContext(ctx context.Context) (float64, error) {
+	_, length, err := i.currentTrack(ctx)
+	if err != nil {
+		return 0, err
+	}
+	position, err := i.GetPositionContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return clampPercent(100 * float64(position) / float64(length)), nil
+}
+
+// SetPositionPercent moves the playback position to pct percent of the length
+// of the current track. pct is clamped to [0, 100]. The error wraps
+// ErrUnknownLength when the player doesn't report the length.
+func (i *Player) SetPositionPercent(pct float64) error {
+	return i.SetPositionPercentContext(context.Background(), pct)
+}
+
+// SetPositionPercentContext is like SetPositionPercent but takes a context.
+func (i *Player) SetPositionPercentContext(ctx context.Context, pct float64) error {
+	m, length, err := i.currentTrack(ctx)
+	if err != nil {
+		return err
+	}
+	return i.setPercent(ctx, m, length, pct)
+}
+
+// setPercent moves the playback position of the track described by m, which
+// is length long, to pct percent.
+func (i *Player) setPercent(
+	ctx context.Context,
+	m Metadata,
+	length time.Duration,
+	pct float64,
+) error {
+	trackID, err := m.GetObjectPath("mpris:trackid")
+	if err != nil {
+		return err
+	}
+	position := time.Duration(float64(length) * clampPercent(pct) / 100)
+	return i.setTrackPosition(ctx, m, trackID, position)
+}
+
+// SeekPercent moves the playback position by deltaPct percent of the length of
+// the current track, forward for positive values. The new position is clamped
+// to the track. The error wraps ErrUnknownLength when the player doesn't
+// report the length.
+func (i *Player) SeekPercent(deltaPct float64) error {
+	return i.SeekPercentContext(context.Background(), deltaPct)
+}
+
+// SeekPercentContext is like SeekPercent but takes a context.
+func (i *Player) SeekPercentContext(ctx context.Context, deltaPct float64) error {
+	m, length, err := i.currentTrack(ctx)
+	if err != nil {
+		return err
+	}
+	position, err := i.GetPositionContext(ctx)
+	if err != nil {
+		return err
+	}
+	pct := 100 * float64(position) / float64(length)
+	return i.setPercent(ctx, m, length, pct+deltaPct)
+}
+
+// SeekForward moves the playback position forward by d, stopping at the end
+// of the track when its length is known, and returns the new position. It
+// fails with an error wrapping ErrNotAllowed when CanSeek is false. Players
+// whose Seek method fails are moved with SetPosition instead, since some
+// advertise CanSeek but only implement the latter.
+func (i *Player) SeekForward(d time.Duration) (time.Duration, error) {
+	return i.SeekForwardContext(context.Background(), d)
+}
+
+// SeekForwardContext is like SeekForward but takes a context.
+func (i *Player) SeekForwardContext(
+	ctx context.Context,
+	d time.Duration,
+) (time.Duration, error) {
+	return i.seekBy(ctx, d)
+}
+
+// SeekBackward is like SeekForward but moves the playback position back by d,
+// stopping at the start of the track.
+func (i *Player) SeekBackward(d time.Duration) (time.Duration, error) {
+	return i.SeekBackwardContext(context.Background(), d)
+}
+
+// SeekBackwardContext is like SeekBackward but takes a context.
+func (i *Player) SeekBackwardContext(
+	ctx context.Context,
+	d time.Duration,
+) (time.Duration, error) {
+	return i.seekBy(ctx, -d)
+}
+
+// seekBy moves the playback position by offset, clamped to the current track,
+// and returns the new position.
+func (i *Player) seekBy(
+	ctx context.Context,
+	offset time.Duration,
+) (time.Duration, error) {
+	can, err := i.CanSeekContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if !can {
+		return 0, fmt.Errorf(
+			"%w: %s.CanSeek is false, can't seek",
+			ErrNotAllowed,
+			PlayerInterface,
+		)
+	}
+	m, err := i.GetMetadataContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	position, err := i.GetPositionContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	target := max(position+offset, 0)
+	if length, err := m.getLength("mpris:length"); err == nil && length > 0 {
+		target = min(target, length)
+	}
+	if target == position {
+		return position, nil
+	}
+
+	seekErr := i.SeekContext(ctx, target-position)
+	if seekErr == nil {
+		return target, nil
+	}
+	if errors.Is(seekErr, ErrPlayerGone) || ctx.Err() != nil {
+		return position, seekErr
+	}
+	trackID, err := m.GetObjectPath("mpris:trackid")
+	if err != nil {
+		return position, errors.Join(seekErr, err)
+	}
+	if err := i.setTrackPosition(ctx, m, trackID, target); err != nil {
+		return position, errors.Join(seekErr, err)
+	}
+	return target, nil
+}
@@ -0,0 +1,156 @@
+package mpris
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// seekablePlayer implements Seek and SetPosition over testProperties, like a
+// player that moves within the current track.
+type seekablePlayer struct {
+	props *testProperties
+	// brokenSeek makes Seek fail, like mpv with some scripts.
+	brokenSeek atomic.Bool
+}
+
+// SeekBy implements Seek, which can't be its name because of io.Seeker.
+func (p *seekablePlayer) SeekBy(offset int64) *dbus.Error {
+	if p.brokenSeek.Load() {
+		return dbus.MakeFailedError(errors.New("seek is broken"))
+	}
+	v, _ := p.props.Get(PlayerInterface, "Position")
+	p.props.set(PlayerInterface, "Position", v.Value().(int64)+offset)
+	return nil
+}
+
+func (p *seekablePlayer) SetPosition(_ dbus.ObjectPath, position int64) *dbus.Error {
+	p.props.set(PlayerInterface, "Position", position)
+	return nil
+}
+
+// exportSeekablePlayer exports a seekablePlayer playing a track that is length
+// long, currently at position.
+func exportSeekablePlayer(
+	t *testing.T,
+	server *dbus.Conn,
+	length, position time.Duration,
+) *seekablePlayer {
+	t.Helper()
+	metadata := map[string]dbus.Variant{
+		"mpris:trackid": dbus.MakeVariant(dbus.ObjectPath("/track/1")),
+	}
+	if length >= 0 {
+		metadata["mpris:length"] = dbus.MakeVariant(length.Microseconds())
+	}
+	props := exportTestProperties(t, server, map[string]map[string]any{
+		PlayerInterface: {
+			"Metadata": metadata,
+			"Position": position.Microseconds(),
+			"CanSeek":  true,
+		},
+	})
+	p := &seekablePlayer{props: props}
+	methods := map[string]string{"SeekBy": "Seek"}
+	err := server.ExportWithMap(p, methods, DBusObjectPath, PlayerInterface)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestPositionPercent(t *testing.T) {
+	server, player := testBus(t)
+	exportSeekablePlayer(t, server, 200*time.Second, 50*time.Second)
+
+	pct, err := player.GetPositionPercent()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pct != 25 {
+		t.Errorf("GetPositionPercent() = %v, want 25", pct)
+	}
+
+	tests := []struct {
+		set  func() error
+		want time.Duration
+	}{
+		{func() error { return player.SetPositionPercent(50) }, 100 * time.Second},
+		{func() error { return player.SetPositionPercent(150) }, 200 * time.Second},
+		{func() error { return player.SetPositionPercent(-5) }, 0},
+		{func() error { return player.SeekPercent(10) }, 20 * time.Second},
+		{func() error { return player.SeekPercent(-25) }, 0},
+		{func() error { return player.SeekPercent(120) }, 200 * time.Second},
+	}
+	for n, tt := range tests {
+		if err := tt.set(); err != nil {
+			t.Fatal(err)
+		}
+		position, err := player.GetPosition()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if position != tt.want {
+			t.Errorf("%d: position = %v, want %v", n, position, tt.want)
+		}
+	}
+}
+
+func TestPositionPercentUnknownLength(t *testing.T) {
+	for _, length := range []time.Duration{-1, 0} {
+		server, player := testBus(t)
+		exportSeekablePlayer(t, server, length, 0)
+
+		if _, err := player.GetPositionPercent(); !errors.Is(err, ErrUnknownLength) {
+			t.Errorf("GetPositionPercent() error = %v, want ErrUnknownLength", err)
+		}
+		if err := player.SetPositionPercent(50); !errors.Is(err, ErrUnknownLength) {
+			t.Errorf("SetPositionPercent() error = %v, want ErrUnknownLength", err)
+		}
+		if err := player.SeekPercent(5); !errors.Is(err, ErrUnknownLength) {
+			t.Errorf("SeekPercent() error = %v, want ErrUnknownLength", err)
+		}
+	}
+}
+
+func TestSeekForwardBackward(t *testing.T) {
+	for _, broken := range []bool{false, true} {
+		server, player := testBus(t)
+		fake := exportSeekablePlayer(t, server, 60*time.Second, 30*time.Second)
+		fake.brokenSeek.Store(broken)
+
+		tests := []struct {
+			seek func(time.Duration) (time.Duration, error)
+			d    time.Duration
+			want time.Duration
+		}{
+			{player.SeekForward, 10 * time.Second, 40 * time.Second},
+			{player.SeekForward, time.Minute, 60 * time.Second},
+			{player.SeekBackward, 15 * time.Second, 45 * time.Second},
+			{player.SeekBackward, time.Minute, 0},
+		}
+		for _, tt := range tests {
+			got, err := tt.seek(tt.d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			position, _ := player.GetPosition()
+			if got != tt.want || position != tt.want {
+				t.Errorf("broken = %v: seek by %v = %v, position %v, want %v",
+					broken, tt.d, got, position, tt.want)
+			}
+		}
+	}
+}
+
+func TestSeekForwardCannotSeek(t *testing.T) {
+	server, player := testBus(t)
+	fake := exportSeekablePlayer(t, server, time.Minute, 0)
+	fake.props.set(PlayerInterface, "CanSeek", false)
+	if _, err := player.SeekForward(time.Second); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("SeekForward() error = %v, want ErrNotAllowed", err)
+	}
+}
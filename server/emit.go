@@ -0,0 +1,129 @@
+package server
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/Nadim147c/go-mpris"
+	"github.com/godbus/dbus/v5"
+)
+
+// coalesceWindow is how long property changes are collected before they are
+// announced, so changes made together, like a new track's Metadata,
+// PlaybackStatus and CanGoNext, go out in a single PropertiesChanged signal
+// per interface, like well-behaved players do.
+const coalesceWindow = 5 * time.Millisecond
+
+// NotifyProperties changes the values of properties of iface and announces
+// them. Properties named in invalidated keep their value, but are announced
+// without it, so clients read them again when they need them, which suits
+// large values. Changes made within a few milliseconds of each other are
+// announced together; Flush announces them right away.
+func (s *PlayerServer) NotifyProperties(
+	iface string,
+	changed map[string]any,
+	invalidated []string,
+) error {
+	// Check every property before storing any, so a mistake doesn't leave
+	// the changes half made.
+	for name, v := range changed {
+		if err := s.props.announceable(iface, name); err != nil {
+			return err
+		}
+		if _, _, err := check(iface, name, v); err != nil {
+			return err
+		}
+	}
+	for _, name := range invalidated {
+		if err := s.props.announceable(iface, name); err != nil {
+			return err
+		}
+	}
+	for name, v := range changed {
+		s.props.store(iface, name, v)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pending == nil {
+		s.pending = map[string]map[string]bool{}
+	}
+	if s.pending[iface] == nil {
+		s.pending[iface] = map[string]bool{}
+	}
+	for name := range changed {
+		s.pending[iface][name] = specs[iface][name].invalidates
+	}
+	for _, name := range invalidated {
+		s.pending[iface][name] = true
+	}
+	if s.flushTimer == nil {
+		s.flushTimer = time.AfterFunc(coalesceWindow, func() { s.Flush() })
+	}
+	return nil
+}
+
+// NotifyMetadata changes the metadata of the current track and announces it.
+func (s *PlayerServer) NotifyMetadata(m mpris.Metadata) error {
+	return s.NotifyProperties(mpris.PlayerInterface, map[string]any{
+		"Metadata": m,
+	}, nil)
+}
+
+// NotifyPlaybackStatus changes the playback status and announces it.
+func (s *PlayerServer) NotifyPlaybackStatus(status mpris.PlaybackStatus) error {
+	return s.NotifyProperties(mpris.PlayerInterface, map[string]any{
+		"PlaybackStatus": status,
+	}, nil)
+}
+
+// EmitSeeked emits the Seeked signal, telling clients that the playback
+// position jumped to position, e.g. after a seek or when a track restarts.
+// Pending property changes are announced first.
+func (s *PlayerServer) EmitSeeked(position time.Duration) error {
+	if err := s.Flush(); err != nil {
+		return err
+	}
+	return s.conn.Emit(
+		mpris.DBusObjectPath,
+		mpris.PlayerInterface+".Seeked",
+		position.Microseconds(),
+	)
+}
+
+// Flush announces the pending property changes right away, with one
+// PropertiesChanged signal per interface.
+func (s *PlayerServer) Flush() error {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = nil
+	if s.flushTimer != nil {
+		s.flushTimer.Stop()
+		s.flushTimer = nil
+	}
+	s.mu.Unlock()
+
+	for _, iface := range slices.Sorted(maps.Keys(pending)) {
+		changed := map[string]dbus.Variant{}
+		invalidated := []string{}
+		for _, name := range slices.Sorted(maps.Keys(pending[iface])) {
+			if pending[iface][name] {
+				invalidated = append(invalidated, name)
+				continue
+			}
+			changed[name] = s.props.value(iface, name)
+		}
+		err := s.conn.Emit(
+			mpris.DBusObjectPath,
+			mpris.PropertiesChangedSignal,
+			iface,
+			changed,
+			invalidated,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package server
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Nadim147c/go-mpris"
+	"github.com/godbus/dbus/v5"
+)
+
+// watchSignals returns a channel receiving the signals sent from the MPRIS
+// object path to conn.
+func watchSignals(t *testing.T, conn *dbus.Conn) <-chan *dbus.Signal {
+	t.Helper()
+	if err := conn.AddMatchSignal(dbus.WithMatchObjectPath(mpris.DBusObjectPath)); err != nil {
+		t.Fatal(err)
+	}
+	ch := make(chan *dbus.Signal, 16)
+	conn.Signal(ch)
+	return ch
+}
+
+// receiveSignal returns the next signal on ch, failing the test after five
+// seconds.
+func receiveSignal(t *testing.T, ch <-chan *dbus.Signal) *dbus.Signal {
+	t.Helper()
+	select {
+	case sig := <-ch:
+		return sig
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for a signal")
+		return nil
+	}
+}
+
+func TestNotifyPropertiesCoalesces(t *testing.T) {
+	addr := testBusAddress(t)
+	s, err := New(testConn(t, addr), "test", &recordingPlayer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	signals := watchSignals(t, testConn(t, addr))
+
+	metadata := mpris.Metadata{"xesam:title": dbus.MakeVariant("Song")}
+	if err := s.NotifyMetadata(metadata); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.NotifyPlaybackStatus(mpris.PlaybackPlaying); err != nil {
+		t.Fatal(err)
+	}
+	err = s.NotifyProperties(mpris.PlayerInterface, map[string]any{
+		"CanGoNext": true,
+	}, []string{"Volume"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sig := receiveSignal(t, signals)
+	var (
+		iface       string
+		changed     map[string]dbus.Variant
+		invalidated []string
+	)
+	if err := dbus.Store(sig.Body, &iface, &changed, &invalidated); err != nil {
+		t.Fatal(err)
+	}
+	keys := make([]string, 0, len(changed))
+	for k := range changed {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	if want := []string{"CanGoNext", "Metadata", "PlaybackStatus"}; !slices.Equal(keys, want) {
+		t.Errorf("changed = %v, want %v in one signal", keys, want)
+	}
+	if !slices.Equal(invalidated, []string{"Volume"}) {
+		t.Errorf("invalidated = %v, want [Volume]", invalidated)
+	}
+	select {
+	case sig := <-signals:
+		t.Errorf("unexpected second signal %v", sig)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestNotifyPropertiesInvalid(t *testing.T) {
+	s, _, player := testServer(t)
+
+	err := s.NotifyProperties(mpris.PlayerInterface, map[string]any{
+		"CanPlay":  true,
+		"CanPause": "yes",
+	}, nil)
+	if err == nil {
+		t.Fatal("NotifyProperties() with a wrong type succeeded")
+	}
+	if can, _ := player.CanPlay(); can {
+		t.Error("CanPlay changed although NotifyProperties() failed")
+	}
+	if err := s.NotifyProperties(mpris.PlayerInterface, nil, []string{"Position"}); err == nil {
+		t.Error("NotifyProperties() announced Position")
+	}
+}
+
+func TestEmitSeeked(t *testing.T) {
+	s, _, player := testServer(t)
+
+	seeked := make(chan time.Duration, 1)
+	sub, err := player.Subscribe(t.Context(), mpris.Seeked(func(d time.Duration) {
+		seeked <- d
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	if err := s.EmitSeeked(90 * time.Second); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case d := <-seeked:
+		if d != 90*time.Second {
+			t.Errorf("Seeked position = %v, want 1m30s", d)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Seeked not received")
+	}
+}
@@ -0,0 +1,151 @@
+package server
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/Nadim147c/go-mpris"
+	"github.com/godbus/dbus/v5/introspect"
+	"github.com/godbus/dbus/v5/prop"
+)
+
+// methods holds the methods of every interface the server exports.
+var methods = map[string][]introspect.Method{
+	mpris.BaseInterface: {
+		{Name: "Raise"},
+		{Name: "Quit"},
+	},
+	mpris.PlayerInterface: {
+		{Name: "Next"},
+		{Name: "Previous"},
+		{Name: "Pause"},
+		{Name: "PlayPause"},
+		{Name: "Stop"},
+		{Name: "Play"},
+		{Name: "Seek", Args: []introspect.Arg{
+			{Name: "Offset", Type: "x", Direction: "in"},
+		}},
+		{Name: "SetPosition", Args: []introspect.Arg{
+			{Name: "TrackId", Type: "o", Direction: "in"},
+			{Name: "Position", Type: "x", Direction: "in"},
+		}},
+		{Name: "OpenUri", Args: []introspect.Arg{
+			{Name: "Uri", Type: "s", Direction: "in"},
+		}},
+	},
+	mpris.TrackListInterface: {
+		{Name: "GetTracksMetadata", Args: []introspect.Arg{
+			{Name: "TrackIds", Type: "ao", Direction: "in"},
+			{Name: "Metadata", Type: "aa{sv}", Direction: "out"},
+		}},
+		{Name: "AddTrack", Args: []introspect.Arg{
+			{Name: "Uri", Type: "s", Direction: "in"},
+			{Name: "AfterTrack", Type: "o", Direction: "in"},
+			{Name: "SetAsCurrent", Type: "b", Direction: "in"},
+		}},
+		{Name: "RemoveTrack", Args: []introspect.Arg{
+			{Name: "TrackId", Type: "o", Direction: "in"},
+		}},
+		{Name: "GoTo", Args: []introspect.Arg{
+			{Name: "TrackId", Type: "o", Direction: "in"},
+		}},
+	},
+	mpris.PlaylistsInterface: {
+		{Name: "ActivatePlaylist", Args: []introspect.Arg{
+			{Name: "PlaylistId", Type: "o", Direction: "in"},
+		}},
+		{Name: "GetPlaylists", Args: []introspect.Arg{
+			{Name: "Index", Type: "u", Direction: "in"},
+			{Name: "MaxCount", Type: "u", Direction: "in"},
+			{Name: "Order", Type: "s", Direction: "in"},
+			{Name: "ReverseOrder", Type: "b", Direction: "in"},
+			{Name: "Playlists", Type: "a(oss)", Direction: "out"},
+		}},
+	},
+}
+
+// signals holds the signals of every interface the server exports.
+var signals = map[string][]introspect.Signal{
+	mpris.PlayerInterface: {
+		{Name: "Seeked", Args: []introspect.Arg{
+			{Name: "Position", Type: "x"},
+		}},
+	},
+	mpris.TrackListInterface: {
+		{Name: "TrackListReplaced", Args: []introspect.Arg{
+			{Name: "Tracks", Type: "ao"},
+			{Name: "CurrentTrack", Type: "o"},
+		}},
+		{Name: "TrackAdded", Args: []introspect.Arg{
+			{Name: "Metadata", Type: "a{sv}"},
+			{Name: "AfterTrack", Type: "o"},
+		}},
+		{Name: "TrackRemoved", Args: []introspect.Arg{
+			{Name: "TrackId", Type: "o"},
+		}},
+		{Name: "TrackMetadataChanged", Args: []introspect.Arg{
+			{Name: "TrackId", Type: "o"},
+			{Name: "Metadata", Type: "a{sv}"},
+		}},
+	},
+	mpris.PlaylistsInterface: {
+		{Name: "PlaylistChanged", Args: []introspect.Arg{
+			{Name: "Playlist", Type: "(oss)"},
+		}},
+	},
+}
+
+// introspection returns the introspection data of the exported object, which
+// implements the MPRIS interfaces ifaces.
+func introspection(ifaces []string) *introspect.Node {
+	node := &introspect.Node{
+		Name: mpris.DBusObjectPath,
+		Interfaces: []introspect.Interface{
+			introspect.IntrospectData,
+			prop.IntrospectData,
+		},
+	}
+	for _, iface := range ifaces {
+		node.Interfaces = append(node.Interfaces, introspect.Interface{
+			Name:       iface,
+			Methods:    methods[iface],
+			Signals:    signals[iface],
+			Properties: introspectProperties(iface),
+		})
+	}
+	return node
+}
+
+// introspectProperties returns the introspection data of the properties of
+// iface, sorted by name.
+func introspectProperties(iface string) []introspect.Property {
+	var list []introspect.Property
+	for name, spec := range specs[iface] {
+		p := introspect.Property{
+			Name:   name,
+			Type:   spec.signature,
+			Access: "read",
+		}
+		if spec.writable {
+			p.Access = "readwrite"
+		}
+		emits := ""
+		switch {
+		case iface == mpris.PlayerInterface && name == "Position":
+			emits = "false"
+		case spec.invalidates:
+			emits = "invalidates"
+		}
+		if emits != "" {
+			p.Annotations = []introspect.Annotation{{
+				Name:  "org.freedesktop.DBus.Property.EmitsChangedSignal",
+				Value: emits,
+			}}
+		}
+		list = append(list, p)
+	}
+	slices.SortFunc(list, func(a, b introspect.Property) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return list
+}
@@ -0,0 +1,270 @@
+package server
+
+import (
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/Nadim147c/go-mpris"
+	"github.com/godbus/dbus/v5"
+)
+
+// propertySpec describes a property of the spec.
+type propertySpec struct {
+	signature string
+	writable  bool
+	// invalidates is set for properties announced without their value.
+	invalidates bool
+}
+
+// specs holds the properties of every interface the server can export.
+var specs = map[string]map[string]propertySpec{
+	mpris.BaseInterface: {
+		"CanQuit":             {"b", false, false},
+		"Fullscreen":          {"b", true, false},
+		"CanSetFullscreen":    {"b", false, false},
+		"CanRaise":            {"b", false, false},
+		"HasTrackList":        {"b", false, false},
+		"Identity":            {"s", false, false},
+		"DesktopEntry":        {"s", false, false},
+		"SupportedUriSchemes": {"as", false, false},
+		"SupportedMimeTypes":  {"as", false, false},
+	},
+	mpris.PlayerInterface: {
+		"PlaybackStatus": {"s", false, false},
+		"LoopStatus":     {"s", true, false},
+		"Rate":           {"d", true, false},
+		"Shuffle":        {"b", true, false},
+		"Metadata":       {"a{sv}", false, false},
+		"Volume":         {"d", true, false},
+		"Position":       {"x", false, false},
+		"MinimumRate":    {"d", false, false},
+		"MaximumRate":    {"d", false, false},
+		"CanGoNext":      {"b", false, false},
+		"CanGoPrevious":  {"b", false, false},
+		"CanPlay":        {"b", false, false},
+		"CanPause":       {"b", false, false},
+		"CanSeek":        {"b", false, false},
+		"CanControl":     {"b", false, false},
+	},
+	mpris.TrackListInterface: {
+		"Tracks":        {"ao", false, true},
+		"CanEditTracks": {"b", false, false},
+	},
+	mpris.PlaylistsInterface: {
+		"PlaylistCount":  {"u", false, false},
+		"Orderings":      {"as", false, false},
+		"ActivePlaylist": {"(b(oss))", false, false},
+	},
+}
+
+// defaults holds the values of the required properties the application
+// didn't set.
+func defaults(name string, hasTrackList bool) map[string]map[string]any {
+	return map[string]map[string]any{
+		mpris.BaseInterface: {
+			"CanQuit":             false,
+			"CanRaise":            false,
+			"HasTrackList":        hasTrackList,
+			"Identity":            name,
+			"SupportedUriSchemes": []string{},
+			"SupportedMimeTypes":  []string{},
+		},
+		mpris.PlayerInterface: {
+			"PlaybackStatus": string(mpris.PlaybackStopped),
+			"Rate":           1.0,
+			"Metadata":       map[string]dbus.Variant{},
+			"Volume":         1.0,
+			"MinimumRate":    1.0,
+			"MaximumRate":    1.0,
+			"CanGoNext":      false,
+			"CanGoPrevious":  false,
+			"CanPlay":        false,
+			"CanPause":       false,
+			"CanSeek":        false,
+			"CanControl":     true,
+		},
+		mpris.TrackListInterface: {
+			"Tracks":        []dbus.ObjectPath{},
+			"CanEditTracks": false,
+		},
+		mpris.PlaylistsInterface: {
+			"PlaylistCount":  uint32(0),
+			"Orderings":      []string{},
+			"ActivePlaylist": MaybePlaylist{},
+		},
+	}
+}
+
+// properties answers org.freedesktop.DBus.Properties for the server.
+type properties struct {
+	s *PlayerServer
+
+	mu     sync.Mutex
+	values map[string]map[string]dbus.Variant
+}
+
+// newProperties returns the properties of the interfaces the server exports,
+// set to their defaults.
+func newProperties(s *PlayerServer) *properties {
+	p := &properties{s: s, values: map[string]map[string]dbus.Variant{}}
+	_, hasTrackList := s.impl.(TrackList)
+	all := defaults(s.name, hasTrackList)
+	for _, iface := range s.ifaces {
+		p.values[iface] = map[string]dbus.Variant{}
+		for name, v := range all[iface] {
+			p.values[iface][name] = dbus.MakeVariant(v)
+		}
+	}
+	return p
+}
+
+// lookup returns the spec of iface.name, failing when the property doesn't
+// exist.
+func lookup(iface, name string) (propertySpec, error) {
+	spec, ok := specs[iface][name]
+	if !ok {
+		return spec, fmt.Errorf("%w: %s.%s", mpris.ErrUnknownProperty, iface, name)
+	}
+	return spec, nil
+}
+
+// announceable fails for properties the application can't announce: unknown
+// ones, ones of interfaces the server doesn't export and Position, which the
+// spec never announces.
+func (p *properties) announceable(iface, name string) error {
+	// The interfaces are fixed when the server is created, so p.values
+	// can be read without holding p.mu.
+	if _, ok := p.values[iface]; !ok {
+		return fmt.Errorf(
+			"%w: %s isn't exported by the server",
+			mpris.ErrNotSupported,
+			iface,
+		)
+	}
+	if iface == mpris.PlayerInterface && name == "Position" {
+		return fmt.Errorf(
+			"%w: Position is read from Player.Position",
+			mpris.ErrNotSupported,
+		)
+	}
+	_, err := lookup(iface, name)
+	return err
+}
+
+// check returns the spec of iface.name and v as a variant, failing when the
+// property doesn't exist or v has the wrong type.
+func check(iface, name string, v any) (propertySpec, dbus.Variant, error) {
+	spec, err := lookup(iface, name)
+	if err != nil {
+		return spec, dbus.Variant{}, err
+	}
+	variant, ok := v.(dbus.Variant)
+	if !ok {
+		variant = dbus.MakeVariant(v)
+	}
+	if got := variant.Signature().String(); got != spec.signature {
+		return spec, dbus.Variant{}, fmt.Errorf(
+			"%w: %s.%s must have type %s, not %s",
+			mpris.ErrOutOfRange,
+			iface,
+			name,
+			spec.signature,
+			got,
+		)
+	}
+	return spec, variant, nil
+}
+
+// store sets iface.name to v without announcing it.
+func (p *properties) store(iface, name string, v any) error {
+	if err := p.announceable(iface, name); err != nil {
+		return err
+	}
+	_, variant, err := check(iface, name, v)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.values[iface][name] = variant
+	return nil
+}
+
+// value returns the value of iface.name.
+func (p *properties) value(iface, name string) dbus.Variant {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.values[iface][name]
+}
+
+// Get implements org.freedesktop.DBus.Properties.Get.
+func (p *properties) Get(iface, name string) (dbus.Variant, *dbus.Error) {
+	if iface == mpris.PlayerInterface && name == "Position" {
+		return dbus.MakeVariant(p.s.impl.Position().Microseconds()), nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.values[iface]; !ok {
+		return dbus.Variant{}, unknownInterface(iface)
+	}
+	v, ok := p.values[iface][name]
+	if !ok {
+		return dbus.Variant{}, dbus.NewError(
+			"org.freedesktop.DBus.Error.UnknownProperty",
+			[]any{fmt.Sprintf("Unknown property %s.%s", iface, name)},
+		)
+	}
+	return v, nil
+}
+
+// GetAll implements org.freedesktop.DBus.Properties.GetAll.
+func (p *properties) GetAll(iface string) (map[string]dbus.Variant, *dbus.Error) {
+	p.mu.Lock()
+	values, ok := p.values[iface]
+	values = maps.Clone(values)
+	p.mu.Unlock()
+	if !ok {
+		return nil, unknownInterface(iface)
+	}
+	if iface == mpris.PlayerInterface {
+		values["Position"], _ = p.Get(iface, "Position")
+	}
+	return values, nil
+}
+
+// Set implements org.freedesktop.DBus.Properties.Set.
+func (p *properties) Set(iface, name string, v dbus.Variant) *dbus.Error {
+	if _, ok := p.values[iface]; !ok {
+		return unknownInterface(iface)
+	}
+	spec, variant, err := check(iface, name, v)
+	if err != nil {
+		return dbus.NewError(
+			"org.freedesktop.DBus.Error.InvalidArgs",
+			[]any{err.Error()},
+		)
+	}
+	if !spec.writable {
+		return dbus.NewError(
+			"org.freedesktop.DBus.Error.PropertyReadOnly",
+			[]any{fmt.Sprintf("%s.%s is read-only", iface, name)},
+		)
+	}
+	setter, ok := p.s.impl.(PropertySetter)
+	if !ok {
+		return dbusError(notSupported("setting " + name))
+	}
+	if err := setter.SetProperty(iface, name, variant.Value()); err != nil {
+		return dbusError(err)
+	}
+	err = p.s.NotifyProperties(iface, map[string]any{name: variant}, nil)
+	return dbusError(err)
+}
+
+func unknownInterface(iface string) *dbus.Error {
+	return dbus.NewError(
+		"org.freedesktop.DBus.Error.UnknownInterface",
+		[]any{"Unknown interface " + iface},
+	)
+}
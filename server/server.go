@@ -0,0 +1,311 @@
+// Package server exports a media player over D-Bus as an MPRIS player, so
+// that applications written in Go can be controlled by desktop shells,
+// playerctl and the client side of this module.
+package server
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Nadim147c/go-mpris"
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+)
+
+func init() {
+	mpris.RegisterFeature(mpris.FeatureServer)
+}
+
+// ErrNameTaken is returned by New when another connection owns the bus name.
+var ErrNameTaken = errors.New("mpris: bus name is taken")
+
+// Player is implemented by the application to act on the calls of clients.
+// Methods return an error wrapping mpris.ErrNotSupported for operations the
+// application doesn't implement, which reaches the client as
+// org.freedesktop.DBus.Error.NotSupported. Embed Unimplemented to only
+// implement some of them.
+type Player interface {
+	Raise() error
+	Quit() error
+	Next() error
+	Previous() error
+	Pause() error
+	PlayPause() error
+	Stop() error
+	Play() error
+	// SeekBy implements the Seek method, which can't be its name because
+	// of io.Seeker.
+	SeekBy(offset time.Duration) error
+	SetPosition(trackID dbus.ObjectPath, position time.Duration) error
+	OpenURI(uri string) error
+	// Position returns the current playback position. It is called for
+	// every read of the Position property, which is never announced with
+	// PropertiesChanged.
+	Position() time.Duration
+}
+
+// PropertySetter is implemented by players that let clients change the
+// writable properties: LoopStatus, Rate, Shuffle and Volume of the player
+// interface and Fullscreen of the base interface. value has the D-Bus type of
+// the property, e.g. string for LoopStatus. When SetProperty succeeds, the
+// value is stored and announced. Without PropertySetter, writes fail with
+// org.freedesktop.DBus.Error.NotSupported.
+type PropertySetter interface {
+	SetProperty(iface, name string, value any) error
+}
+
+// Unimplemented implements every method of Player by returning an error
+// wrapping mpris.ErrNotSupported, and Position by returning 0.
+type Unimplemented struct{}
+
+func notSupported(method string) error {
+	return fmt.Errorf("%w: %s isn't implemented", mpris.ErrNotSupported, method)
+}
+
+//revive:disable:exported
+
+func (Unimplemented) Raise() error     { return notSupported("Raise") }
+func (Unimplemented) Quit() error      { return notSupported("Quit") }
+func (Unimplemented) Next() error      { return notSupported("Next") }
+func (Unimplemented) Previous() error  { return notSupported("Previous") }
+func (Unimplemented) Pause() error     { return notSupported("Pause") }
+func (Unimplemented) PlayPause() error { return notSupported("PlayPause") }
+func (Unimplemented) Stop() error      { return notSupported("Stop") }
+func (Unimplemented) Play() error      { return notSupported("Play") }
+
+func (Unimplemented) SeekBy(time.Duration) error {
+	return notSupported("Seek")
+}
+
+func (Unimplemented) SetPosition(dbus.ObjectPath, time.Duration) error {
+	return notSupported("SetPosition")
+}
+
+func (Unimplemented) OpenURI(string) error { return notSupported("OpenUri") }
+
+func (Unimplemented) Position() time.Duration { return 0 }
+
+//revive:enable:exported
+
+// Option configures a PlayerServer created by New.
+type Option func(*PlayerServer)
+
+// WithProperties sets initial values of properties of iface, before the bus
+// name is requested, so clients never see the defaults. Values must have the
+// D-Bus type the spec gives the property; New fails otherwise.
+func WithProperties(iface string, values map[string]any) Option {
+	return func(s *PlayerServer) {
+		for name, v := range values {
+			s.initial = append(s.initial, propertyValue{iface, name, v})
+		}
+	}
+}
+
+// propertyValue is a value for a property given to WithProperties.
+type propertyValue struct {
+	iface, name string
+	value       any
+}
+
+// PlayerServer exports a Player on the bus. It answers the MPRIS methods and
+// the properties of the base and player interfaces, which the application
+// keeps up to date with Set and NotifyProperties. A PlayerServer is safe for
+// concurrent use.
+type PlayerServer struct {
+	conn    *dbus.Conn
+	name    string
+	impl    Player
+	props   *properties
+	initial []propertyValue
+	// ifaces holds the MPRIS interfaces the server exports.
+	ifaces []string
+
+	mu sync.Mutex
+	// pending holds the property changes waiting to be announced by
+	// interface and name, with true for invalidated properties.
+	pending    map[string]map[string]bool
+	flushTimer *time.Timer
+}
+
+// New exports impl on conn at /org/mpris/MediaPlayer2 and requests the bus
+// name org.mpris.MediaPlayer2.<name>, where name may also be given with the
+// prefix. The TrackList and Playlists interfaces are only exported, and
+// HasTrackList only defaults to true, when impl implements TrackList or
+// Playlists. Properties the application doesn't set through WithProperties
+// start with the values the spec implies, e.g. PlaybackStatus "Stopped" and
+// every Can* property false except CanControl. New fails with ErrNameTaken
+// when the name is owned by another connection. The PlayerServer must be
+// closed with Close.
+func New(
+	conn *dbus.Conn,
+	name string,
+	impl Player,
+	opts ...Option,
+) (*PlayerServer, error) {
+	if !strings.HasPrefix(name, mpris.BaseInterface+".") {
+		name = mpris.BaseInterface + "." + name
+	}
+	s := &PlayerServer{
+		conn:   conn,
+		name:   name,
+		impl:   impl,
+		ifaces: []string{mpris.BaseInterface, mpris.PlayerInterface},
+	}
+	if _, ok := impl.(TrackList); ok {
+		s.ifaces = append(s.ifaces, mpris.TrackListInterface)
+	}
+	if _, ok := impl.(Playlists); ok {
+		s.ifaces = append(s.ifaces, mpris.PlaylistsInterface)
+	}
+	s.props = newProperties(s)
+	for _, opt := range opts {
+		opt(s)
+	}
+	for _, p := range s.initial {
+		if err := s.props.store(p.iface, p.name, p.value); err != nil {
+			return nil, err
+		}
+	}
+
+	// Export everything before requesting the name, so clients never see
+	// a half exported player.
+	if err := s.export(); err != nil {
+		s.unexport()
+		return nil, err
+	}
+	reply, err := conn.RequestName(name, dbus.NameFlagDoNotQueue)
+	if err != nil {
+		s.unexport()
+		return nil, fmt.Errorf("failed to request %s: %w", name, err)
+	}
+	if reply != dbus.RequestNameReplyPrimaryOwner {
+		s.unexport()
+		return nil, fmt.Errorf("%w: %s", ErrNameTaken, name)
+	}
+	return s, nil
+}
+
+// export exports the objects answering the calls of clients.
+func (s *PlayerServer) export() error {
+	path := dbus.ObjectPath(mpris.DBusObjectPath)
+	err := s.conn.Export(rootObject{s}, path, mpris.BaseInterface)
+	if err != nil {
+		return err
+	}
+	methods := map[string]string{"SeekBy": "Seek"}
+	err = s.conn.ExportWithMap(playerObject{s}, methods, path, mpris.PlayerInterface)
+	if err != nil {
+		return err
+	}
+	if t, ok := s.impl.(TrackList); ok {
+		err := s.conn.Export(trackListObject{t}, path, mpris.TrackListInterface)
+		if err != nil {
+			return err
+		}
+	}
+	if p, ok := s.impl.(Playlists); ok {
+		err := s.conn.Export(playlistsObject{p}, path, mpris.PlaylistsInterface)
+		if err != nil {
+			return err
+		}
+	}
+	err = s.conn.Export(s.props, path, "org.freedesktop.DBus.Properties")
+	if err != nil {
+		return err
+	}
+	return s.conn.Export(
+		introspect.NewIntrospectable(introspection(s.ifaces)),
+		path,
+		"org.freedesktop.DBus.Introspectable",
+	)
+}
+
+// unexport removes the objects exported by export.
+func (s *PlayerServer) unexport() {
+	path := dbus.ObjectPath(mpris.DBusObjectPath)
+	ifaces := append([]string{
+		"org.freedesktop.DBus.Properties",
+		"org.freedesktop.DBus.Introspectable",
+	}, s.ifaces...)
+	for _, iface := range ifaces {
+		s.conn.Export(nil, path, iface)
+	}
+}
+
+// Name returns the bus name of the player.
+func (s *PlayerServer) Name() string {
+	return s.name
+}
+
+// Set changes the value of a property of iface and announces the change with
+// PropertiesChanged, like NotifyProperties. value must have the D-Bus type the
+// spec gives the property, e.g. a string or mpris.PlaybackStatus for
+// PlaybackStatus and mpris.Metadata for Metadata. Position can't be set; it
+// is read from Player.Position.
+func (s *PlayerServer) Set(iface, name string, value any) error {
+	return s.NotifyProperties(iface, map[string]any{name: value}, nil)
+}
+
+// Close announces pending property changes, releases the bus name and
+// removes the exported objects. The connection stays open.
+func (s *PlayerServer) Close() error {
+	flushErr := s.Flush()
+	_, err := s.conn.ReleaseName(s.name)
+	s.unexport()
+	return errors.Join(flushErr, err)
+}
+
+// dbusError converts an error of the application to the D-Bus error sent to
+// the client.
+func dbusError(err error) *dbus.Error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, mpris.ErrNotSupported):
+		return dbus.NewError(
+			"org.freedesktop.DBus.Error.NotSupported",
+			[]any{err.Error()},
+		)
+	default:
+		return dbus.MakeFailedError(err)
+	}
+}
+
+// rootObject answers the methods of the base interface.
+type rootObject struct{ s *PlayerServer }
+
+func (o rootObject) Raise() *dbus.Error { return dbusError(o.s.impl.Raise()) }
+func (o rootObject) Quit() *dbus.Error  { return dbusError(o.s.impl.Quit()) }
+
+// playerObject answers the methods of the player interface.
+type playerObject struct{ s *PlayerServer }
+
+func (o playerObject) Next() *dbus.Error      { return dbusError(o.s.impl.Next()) }
+func (o playerObject) Previous() *dbus.Error  { return dbusError(o.s.impl.Previous()) }
+func (o playerObject) Pause() *dbus.Error     { return dbusError(o.s.impl.Pause()) }
+func (o playerObject) PlayPause() *dbus.Error { return dbusError(o.s.impl.PlayPause()) }
+func (o playerObject) Stop() *dbus.Error      { return dbusError(o.s.impl.Stop()) }
+func (o playerObject) Play() *dbus.Error      { return dbusError(o.s.impl.Play()) }
+
+// SeekBy answers Seek, with the offset in microseconds.
+func (o playerObject) SeekBy(offset int64) *dbus.Error {
+	return dbusError(o.s.impl.SeekBy(time.Duration(offset) * time.Microsecond))
+}
+
+// SetPosition answers SetPosition, with the position in microseconds.
+func (o playerObject) SetPosition(trackID dbus.ObjectPath, position int64) *dbus.Error {
+	return dbusError(o.s.impl.SetPosition(
+		trackID,
+		time.Duration(position)*time.Microsecond,
+	))
+}
+
+// OpenUri answers OpenUri.
+//
+//revive:disable-next-line:var-naming
+func (o playerObject) OpenUri(uri string) *dbus.Error {
+	return dbusError(o.s.impl.OpenURI(uri))
+}
@@ -0,0 +1,261 @@
+package server
+
+import (
+	"bufio"
+	"errors"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Nadim147c/go-mpris"
+	"github.com/godbus/dbus/v5"
+)
+
+// testBusAddress starts a private dbus-daemon for the duration of the test and
+// returns its address. The test is skipped when dbus-daemon is unavailable.
+func testBusAddress(t *testing.T) string {
+	t.Helper()
+	bin, err := exec.LookPath("dbus-daemon")
+	if err != nil {
+		t.Skip("dbus-daemon not found")
+	}
+	cmd := exec.Command(bin, "--session", "--nofork", "--print-address=1")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Skipf("Could not start dbus-daemon: %v", err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+	addr, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		t.Fatalf("Could not read dbus-daemon address: %v", err)
+	}
+	return strings.TrimSpace(addr)
+}
+
+// testConn opens a connection to the bus at addr that is closed when the test
+// ends.
+func testConn(t *testing.T, addr string) *dbus.Conn {
+	t.Helper()
+	conn, err := dbus.Connect(addr)
+	if err != nil {
+		t.Fatalf("Could not connect to test bus: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// recordingPlayer records the calls it receives.
+type recordingPlayer struct {
+	Unimplemented
+
+	mu    sync.Mutex
+	calls []string
+}
+
+func (p *recordingPlayer) record(call string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls = append(p.calls, call)
+	return nil
+}
+
+func (p *recordingPlayer) recorded() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Clone(p.calls)
+}
+
+func (p *recordingPlayer) Play() error { return p.record("Play") }
+
+func (p *recordingPlayer) SeekBy(offset time.Duration) error {
+	return p.record("Seek " + offset.String())
+}
+
+func (p *recordingPlayer) SetPosition(trackID dbus.ObjectPath, position time.Duration) error {
+	return p.record("SetPosition " + string(trackID) + " " + position.String())
+}
+
+func (p *recordingPlayer) OpenURI(uri string) error { return p.record("OpenUri " + uri) }
+
+func (p *recordingPlayer) Position() time.Duration { return 42 * time.Second }
+
+func (p *recordingPlayer) SetProperty(iface, name string, value any) error {
+	if name == "Rate" {
+		return errors.New("rate is fixed")
+	}
+	return p.record("Set " + name)
+}
+
+// testServer exports a recordingPlayer called test and returns it with a
+// client for it.
+func testServer(t *testing.T, opts ...Option) (*PlayerServer, *recordingPlayer, *mpris.Player) {
+	t.Helper()
+	addr := testBusAddress(t)
+	impl := &recordingPlayer{}
+	s, err := New(testConn(t, addr), "test", impl, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s, impl, mpris.New(testConn(t, addr), s.Name())
+}
+
+func TestServerProperties(t *testing.T) {
+	s, _, player := testServer(t, WithProperties(mpris.BaseInterface, map[string]any{
+		"Identity": "Test Player",
+		"CanRaise": true,
+	}))
+
+	if s.Name() != mpris.BaseInterface+".test" {
+		t.Errorf("Name() = %q", s.Name())
+	}
+	if identity, err := player.GetIdentity(); err != nil || identity != "Test Player" {
+		t.Errorf("GetIdentity() = %q, %v", identity, err)
+	}
+	if status, err := player.GetPlaybackStatus(); err != nil || status != mpris.PlaybackStopped {
+		t.Errorf("GetPlaybackStatus() = %q, %v, want the default", status, err)
+	}
+	if position, err := player.GetPosition(); err != nil || position != 42*time.Second {
+		t.Errorf("GetPosition() = %v, %v", position, err)
+	}
+
+	changed := make(chan mpris.PlaybackStatus, 1)
+	sub, err := player.Subscribe(t.Context(), mpris.PlaybackStatusChanged(
+		func(s mpris.PlaybackStatus) { changed <- s },
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+	if err := s.Set(mpris.PlayerInterface, "PlaybackStatus", mpris.PlaybackPlaying); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case status := <-changed:
+		if status != mpris.PlaybackPlaying {
+			t.Errorf("announced status = %q, want Playing", status)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("PlaybackStatus change not announced")
+	}
+
+	if err := s.Set(mpris.PlayerInterface, "Volume", "loud"); !errors.Is(err, mpris.ErrOutOfRange) {
+		t.Errorf("Set(Volume, string) error = %v, want ErrOutOfRange", err)
+	}
+	if err := s.Set(mpris.PlayerInterface, "Typo", 1); !errors.Is(err, mpris.ErrUnknownProperty) {
+		t.Errorf("Set(Typo) error = %v, want ErrUnknownProperty", err)
+	}
+	if _, err := New(s.conn, "other", &recordingPlayer{}, WithProperties(
+		mpris.PlayerInterface, map[string]any{"CanPlay": "yes"},
+	)); !errors.Is(err, mpris.ErrOutOfRange) {
+		t.Errorf("New() with a wrong type error = %v, want ErrOutOfRange", err)
+	}
+}
+
+func TestServerMethods(t *testing.T) {
+	_, impl, player := testServer(t)
+
+	if err := player.Play(); err != nil {
+		t.Fatal(err)
+	}
+	if err := player.Seek(-2 * time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := player.SetPositionFor("/track/1", time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := player.OpenURI("file:///a.mp3"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Play", "Seek -2s", "SetPosition /track/1 1s", "OpenUri file:///a.mp3"}
+	if got := impl.recorded(); !slices.Equal(got, want) {
+		t.Errorf("calls = %q, want %q", got, want)
+	}
+
+	if err := player.Next(); !errors.Is(err, mpris.ErrNotSupported) {
+		t.Errorf("Next() error = %v, want ErrNotSupported", err)
+	}
+}
+
+func TestServerSetFromClient(t *testing.T) {
+	_, impl, player := testServer(t)
+
+	if err := player.SetVolume(0.5); err != nil {
+		t.Fatal(err)
+	}
+	if volume, _ := player.GetVolume(); volume != 0.5 {
+		t.Errorf("volume = %v after SetVolume(0.5)", volume)
+	}
+	if got := impl.recorded(); !slices.Equal(got, []string{"Set Volume"}) {
+		t.Errorf("calls = %q, want the Volume write", got)
+	}
+
+	if err := player.SetRateUnchecked(2); err == nil {
+		t.Error("SetRate() succeeded although the player rejects it")
+	}
+	if rate, _ := player.GetRate(); rate != 1 {
+		t.Errorf("rate = %v after a rejected write, want 1", rate)
+	}
+
+	err := player.SetProperty(mpris.PlayerInterface, "CanPlay", dbus.MakeVariant(true))
+	if err == nil {
+		t.Error("setting the read-only CanPlay succeeded")
+	}
+}
+
+func TestServerNameTaken(t *testing.T) {
+	addr := testBusAddress(t)
+	s, err := New(testConn(t, addr), "test", &recordingPlayer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	_, err = New(testConn(t, addr), "test", &recordingPlayer{})
+	if !errors.Is(err, ErrNameTaken) {
+		t.Errorf("New() error = %v, want ErrNameTaken", err)
+	}
+}
+
+func TestServerIntrospection(t *testing.T) {
+	addr := testBusAddress(t)
+	s, err := New(testConn(t, addr), "test", &recordingPlayer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	var data string
+	err = testConn(t, addr).Object(s.Name(), mpris.DBusObjectPath).Call(
+		"org.freedesktop.DBus.Introspectable.Introspect", 0,
+	).Store(&data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<interface name="org.mpris.MediaPlayer2">`,
+		`<interface name="org.mpris.MediaPlayer2.Player">`,
+		`<method name="Seek">`,
+		`<property name="Volume" type="d" access="readwrite">`,
+		`<signal name="Seeked">`,
+	} {
+		if !strings.Contains(data, want) {
+			t.Errorf("introspection data lacks %s", want)
+		}
+	}
+}
+
+func TestServerFeature(t *testing.T) {
+	if !mpris.Features().Server {
+		t.Error("Server feature not registered by the server package")
+	}
+}
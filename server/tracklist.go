@@ -0,0 +1,159 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/Nadim147c/go-mpris"
+	"github.com/godbus/dbus/v5"
+)
+
+// TrackList is implemented by players that expose their queue through the
+// org.mpris.MediaPlayer2.TrackList interface. New only exports the interface,
+// and sets HasTrackList, when the Player also implements TrackList. The
+// application keeps the Tracks and CanEditTracks properties up to date with
+// Set.
+type TrackList interface {
+	GetTracksMetadata(trackIDs []dbus.ObjectPath) ([]mpris.Metadata, error)
+	AddTrack(uri string, afterTrack dbus.ObjectPath, setAsCurrent bool) error
+	RemoveTrack(trackID dbus.ObjectPath) error
+	GoTo(trackID dbus.ObjectPath) error
+}
+
+// Playlist is a playlist as exchanged over the Playlists interface.
+type Playlist struct {
+	ID   dbus.ObjectPath
+	Name string
+	// Icon is the URI of an icon for the playlist, or empty.
+	Icon string
+}
+
+// MaybePlaylist is the type of the ActivePlaylist property: Playlist is only
+// meaningful when Valid is true.
+type MaybePlaylist struct {
+	Valid    bool
+	Playlist Playlist
+}
+
+// Playlists is implemented by players that expose their playlists through
+// the org.mpris.MediaPlayer2.Playlists interface. New only exports the
+// interface when the Player also implements Playlists. The application keeps
+// the PlaylistCount, Orderings and ActivePlaylist properties up to date with
+// Set.
+type Playlists interface {
+	ActivatePlaylist(playlistID dbus.ObjectPath) error
+	GetPlaylists(index, maxCount uint32, order string, reverse bool) ([]Playlist, error)
+}
+
+// trackListObject answers the methods of the TrackList interface.
+type trackListObject struct{ impl TrackList }
+
+func (o trackListObject) GetTracksMetadata(
+	trackIDs []dbus.ObjectPath,
+) ([]mpris.Metadata, *dbus.Error) {
+	metadata, err := o.impl.GetTracksMetadata(trackIDs)
+	if metadata == nil {
+		metadata = []mpris.Metadata{}
+	}
+	return metadata, dbusError(err)
+}
+
+func (o trackListObject) AddTrack(
+	uri string,
+	afterTrack dbus.ObjectPath,
+	setAsCurrent bool,
+) *dbus.Error {
+	return dbusError(o.impl.AddTrack(uri, afterTrack, setAsCurrent))
+}
+
+func (o trackListObject) RemoveTrack(trackID dbus.ObjectPath) *dbus.Error {
+	return dbusError(o.impl.RemoveTrack(trackID))
+}
+
+func (o trackListObject) GoTo(trackID dbus.ObjectPath) *dbus.Error {
+	return dbusError(o.impl.GoTo(trackID))
+}
+
+// playlistsObject answers the methods of the Playlists interface.
+type playlistsObject struct{ impl Playlists }
+
+func (o playlistsObject) ActivatePlaylist(playlistID dbus.ObjectPath) *dbus.Error {
+	return dbusError(o.impl.ActivatePlaylist(playlistID))
+}
+
+func (o playlistsObject) GetPlaylists(
+	index, maxCount uint32,
+	order string,
+	reverse bool,
+) ([]Playlist, *dbus.Error) {
+	playlists, err := o.impl.GetPlaylists(index, maxCount, order, reverse)
+	if playlists == nil {
+		playlists = []Playlist{}
+	}
+	return playlists, dbusError(err)
+}
+
+// emitOn emits the signal member of iface, after announcing pending property
+// changes. It fails with an error wrapping mpris.ErrNotSupported when the
+// server doesn't export iface.
+func (s *PlayerServer) emitOn(iface, member string, args ...any) error {
+	if _, ok := s.props.values[iface]; !ok {
+		return fmt.Errorf(
+			"%w: %s isn't exported by the server",
+			mpris.ErrNotSupported,
+			iface,
+		)
+	}
+	if err := s.Flush(); err != nil {
+		return err
+	}
+	return s.conn.Emit(mpris.DBusObjectPath, iface+"."+member, args...)
+}
+
+// EmitTrackListReplaced announces that the whole track list was replaced by
+// tracks, with current being the current track, and stores tracks as the
+// Tracks property.
+func (s *PlayerServer) EmitTrackListReplaced(
+	tracks []dbus.ObjectPath,
+	current dbus.ObjectPath,
+) error {
+	if tracks == nil {
+		tracks = []dbus.ObjectPath{}
+	}
+	if err := s.props.store(mpris.TrackListInterface, "Tracks", tracks); err != nil {
+		return err
+	}
+	return s.emitOn(mpris.TrackListInterface, "TrackListReplaced", tracks, current)
+}
+
+// EmitTrackAdded announces that the track described by metadata was added
+// after afterTrack, or at the start when afterTrack is mpris.NoTrack.
+func (s *PlayerServer) EmitTrackAdded(
+	metadata mpris.Metadata,
+	afterTrack dbus.ObjectPath,
+) error {
+	return s.emitOn(mpris.TrackListInterface, "TrackAdded", metadata, afterTrack)
+}
+
+// EmitTrackRemoved announces that the track trackID was removed.
+func (s *PlayerServer) EmitTrackRemoved(trackID dbus.ObjectPath) error {
+	return s.emitOn(mpris.TrackListInterface, "TrackRemoved", trackID)
+}
+
+// EmitTrackMetadataChanged announces that the metadata of the track trackID
+// changed to metadata.
+func (s *PlayerServer) EmitTrackMetadataChanged(
+	trackID dbus.ObjectPath,
+	metadata mpris.Metadata,
+) error {
+	return s.emitOn(
+		mpris.TrackListInterface,
+		"TrackMetadataChanged",
+		trackID,
+		metadata,
+	)
+}
+
+// EmitPlaylistChanged announces that the name or icon of playlist changed.
+func (s *PlayerServer) EmitPlaylistChanged(playlist Playlist) error {
+	return s.emitOn(mpris.PlaylistsInterface, "PlaylistChanged", playlist)
+}
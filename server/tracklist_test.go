@@ -0,0 +1,166 @@
+package server
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Nadim147c/go-mpris"
+	"github.com/godbus/dbus/v5"
+)
+
+// queuePlayer is a recordingPlayer with a track list and playlists.
+type queuePlayer struct {
+	recordingPlayer
+}
+
+func (p *queuePlayer) GetTracksMetadata(ids []dbus.ObjectPath) ([]mpris.Metadata, error) {
+	var list []mpris.Metadata
+	for _, id := range ids {
+		list = append(list, mpris.Metadata{"mpris:trackid": dbus.MakeVariant(id)})
+	}
+	return list, nil
+}
+
+func (p *queuePlayer) AddTrack(uri string, _ dbus.ObjectPath, _ bool) error {
+	return p.record("AddTrack " + uri)
+}
+
+func (p *queuePlayer) RemoveTrack(id dbus.ObjectPath) error {
+	return p.record("RemoveTrack " + string(id))
+}
+
+func (p *queuePlayer) GoTo(id dbus.ObjectPath) error {
+	return p.record("GoTo " + string(id))
+}
+
+func (p *queuePlayer) ActivatePlaylist(id dbus.ObjectPath) error {
+	return p.record("ActivatePlaylist " + string(id))
+}
+
+func (p *queuePlayer) GetPlaylists(uint32, uint32, string, bool) ([]Playlist, error) {
+	return []Playlist{{ID: "/playlist/1", Name: "Favorites"}}, nil
+}
+
+// introspectServer returns the introspection data of the server s.
+func introspectServer(t *testing.T, conn *dbus.Conn, s *PlayerServer) string {
+	t.Helper()
+	var data string
+	err := conn.Object(s.Name(), mpris.DBusObjectPath).
+		Call("org.freedesktop.DBus.Introspectable.Introspect", 0).
+		Store(&data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestServerWithoutTrackList(t *testing.T) {
+	addr := testBusAddress(t)
+	s, err := New(testConn(t, addr), "test", &recordingPlayer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	client := testConn(t, addr)
+	player := mpris.New(client, s.Name())
+
+	if has, _ := player.HasTrackList(); has {
+		t.Error("HasTrackList() = true without a TrackList")
+	}
+	if _, err := player.CanEditTracks(); err == nil {
+		t.Error("TrackList property readable without a TrackList")
+	}
+	data := introspectServer(t, client, s)
+	for _, iface := range []string{mpris.TrackListInterface, mpris.PlaylistsInterface} {
+		if strings.Contains(data, iface) {
+			t.Errorf("introspection advertises %s", iface)
+		}
+	}
+	if err := s.EmitTrackRemoved("/track/1"); !errors.Is(err, mpris.ErrNotSupported) {
+		t.Errorf("EmitTrackRemoved() error = %v, want ErrNotSupported", err)
+	}
+	if err := s.Set(mpris.TrackListInterface, "CanEditTracks", true); !errors.Is(err, mpris.ErrNotSupported) {
+		t.Errorf("Set(CanEditTracks) error = %v, want ErrNotSupported", err)
+	}
+}
+
+func TestServerTrackList(t *testing.T) {
+	addr := testBusAddress(t)
+	impl := &queuePlayer{}
+	s, err := New(testConn(t, addr), "test", impl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	client := testConn(t, addr)
+	player := mpris.New(client, s.Name())
+	obj := client.Object(s.Name(), mpris.DBusObjectPath)
+
+	if has, _ := player.HasTrackList(); !has {
+		t.Error("HasTrackList() = false with a TrackList")
+	}
+	data := introspectServer(t, client, s)
+	for _, iface := range []string{mpris.TrackListInterface, mpris.PlaylistsInterface} {
+		if !strings.Contains(data, `<interface name="`+iface+`">`) {
+			t.Errorf("introspection lacks %s", iface)
+		}
+	}
+
+	var metadata []map[string]dbus.Variant
+	err = obj.Call(mpris.TrackListInterface+".GetTracksMetadata", 0,
+		[]dbus.ObjectPath{"/track/1"}).Store(&metadata)
+	if err != nil || len(metadata) != 1 {
+		t.Errorf("GetTracksMetadata() = %v, %v", metadata, err)
+	}
+	if err := obj.Call(mpris.TrackListInterface+".GoTo", 0, dbus.ObjectPath("/track/2")).Err; err != nil {
+		t.Fatal(err)
+	}
+	var playlists []Playlist
+	err = obj.Call(mpris.PlaylistsInterface+".GetPlaylists", 0,
+		uint32(0), uint32(10), "Alphabetical", false).Store(&playlists)
+	if err != nil || len(playlists) != 1 || playlists[0].Name != "Favorites" {
+		t.Errorf("GetPlaylists() = %v, %v", playlists, err)
+	}
+	if got := impl.recorded(); !slices.Equal(got, []string{"GoTo /track/2"}) {
+		t.Errorf("calls = %q", got)
+	}
+
+	signals := watchSignals(t, client)
+	tracks := []dbus.ObjectPath{"/track/1", "/track/2"}
+	if err := s.EmitTrackListReplaced(tracks, "/track/1"); err != nil {
+		t.Fatal(err)
+	}
+	if sig := receiveSignal(t, signals); sig.Name != mpris.TrackListInterface+".TrackListReplaced" {
+		t.Errorf("signal = %s, want TrackListReplaced", sig.Name)
+	}
+	v, err := player.GetTrackListProperty("Tracks")
+	if err != nil || !slices.Equal(v.Value().([]dbus.ObjectPath), tracks) {
+		t.Errorf("Tracks = %v, %v", v, err)
+	}
+
+	if err := s.EmitPlaylistChanged(Playlist{ID: "/playlist/1", Name: "Best"}); err != nil {
+		t.Fatal(err)
+	}
+	if sig := receiveSignal(t, signals); sig.Name != mpris.PlaylistsInterface+".PlaylistChanged" {
+		t.Errorf("signal = %s, want PlaylistChanged", sig.Name)
+	}
+
+	// Tracks is announced without its value, as the spec asks.
+	if err := s.Set(mpris.TrackListInterface, "Tracks", tracks[:1]); err != nil {
+		t.Fatal(err)
+	}
+	sig := receiveSignal(t, signals)
+	var (
+		iface       string
+		changed     map[string]dbus.Variant
+		invalidated []string
+	)
+	if err := dbus.Store(sig.Body, &iface, &changed, &invalidated); err != nil {
+		t.Fatal(err)
+	}
+	if len(changed) != 0 || !slices.Equal(invalidated, []string{"Tracks"}) {
+		t.Errorf("Tracks announced as %v, %v, want invalidated", changed, invalidated)
+	}
+}
@@ -0,0 +1,75 @@
+package mpris
+
+import (
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// Session is a private D-Bus connection owned by this package. It keeps the
+// signal handling of the package apart from connections shared with the rest
+// of the application, and closing it closes the connection.
+type Session struct {
+	conn *dbus.Conn
+}
+
+// Connect opens a private connection to the session bus.
+func Connect() (*Session, error) {
+	return connectPrivate("session bus", dbus.SessionBusPrivate)
+}
+
+// ConnectAddress opens a private connection to the bus at addr, such as
+// "unix:path=/run/mpd/bus" or the address printed by
+// "dbus-daemon --session --print-address".
+func ConnectAddress(addr string) (*Session, error) {
+	return connectPrivate(
+		"bus at "+addr,
+		func(opts ...dbus.ConnOption) (*dbus.Conn, error) {
+			return dbus.Dial(addr, opts...)
+		},
+	)
+}
+
+// connectPrivate opens a private connection with open, then authenticates and
+// registers it on the bus, which open leaves to the caller.
+func connectPrivate(
+	bus string,
+	open func(...dbus.ConnOption) (*dbus.Conn, error),
+) (*Session, error) {
+	conn, err := open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the %s: %w", bus, err)
+	}
+	if err := conn.Auth(nil); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to authenticate to the %s: %w", bus, err)
+	}
+	if err := conn.Hello(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to register on the %s: %w", bus, err)
+	}
+	return &Session{conn: conn}, nil
+}
+
+// Conn returns the connection of the session, for the functions of this
+// package that take one, such as WatchPlayers and NewManager.
+func (s *Session) Conn() *dbus.Conn {
+	return s.conn
+}
+
+// List lists the available players. See List.
+func (s *Session) List(opts ...ListOption) ([]string, error) {
+	return List(s.conn, opts...)
+}
+
+// Player returns the player with the name on the session's connection. See
+// New.
+func (s *Session) Player(name string, opts ...Option) *Player {
+	return New(s.conn, name, opts...)
+}
+
+// Close closes the connection of the session. Players and subscriptions using
+// it stop working.
+func (s *Session) Close() error {
+	return s.conn.Close()
+}
@@ -0,0 +1,15 @@
+//go:build mpris_nosystembus
+
+package mpris
+
+import "fmt"
+
+// ConnectSystem opens a private connection to the system bus. This build was
+// made with the mpris_nosystembus tag, so it always fails with an error
+// wrapping ErrNotSupported.
+func ConnectSystem() (*Session, error) {
+	return nil, fmt.Errorf(
+		"%w: built without system bus support",
+		ErrNotSupported,
+	)
+}
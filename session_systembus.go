@@ -0,0 +1,11 @@
+//go:build !mpris_nosystembus
+
+package mpris
+
+import "github.com/godbus/dbus/v5"
+
+// ConnectSystem opens a private connection to the system bus, where players
+// running as system services, such as mpd with mpDris2, publish MPRIS.
+func ConnectSystem() (*Session, error) {
+	return connectPrivate("system bus", dbus.SystemBusPrivate)
+}
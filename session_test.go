@@ -0,0 +1,73 @@
+package mpris
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestConnect(t *testing.T) {
+	addr := testBusAddress(t)
+	claimName(t, addr, testPlayerName)
+	t.Setenv("DBUS_SESSION_BUS_ADDRESS", addr)
+
+	s, err := Connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	names, err := s.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(names, []string{testPlayerName}) {
+		t.Errorf("List() = %q", names)
+	}
+	if err := s.Player(testPlayerName).Ping(t.Context()); err != nil {
+		t.Errorf("Ping() error = %v", err)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if s.Conn().Connected() {
+		t.Error("connection still open after Close")
+	}
+}
+
+func TestConnectFails(t *testing.T) {
+	t.Setenv("DBUS_SESSION_BUS_ADDRESS", "unix:path=/nonexistent/bus")
+	if _, err := Connect(); err == nil {
+		t.Error("Connect() to a missing bus succeeded")
+	}
+}
+
+func TestConnectAddress(t *testing.T) {
+	addr := testBusAddress(t)
+	claimName(t, addr, testPlayerName)
+
+	s, err := ConnectAddress(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	names, err := s.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(names, []string{testPlayerName}) {
+		t.Errorf("List() = %q", names)
+	}
+
+	if _, err := ConnectAddress("unix:path=/nonexistent/bus"); err == nil {
+		t.Error("ConnectAddress() to a missing bus succeeded")
+	}
+}
+
+func TestConnectSystemWithoutSupport(t *testing.T) {
+	if systemBusSupport {
+		t.Skip("built with system bus support")
+	}
+	if _, err := ConnectSystem(); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("ConnectSystem() error = %v, want ErrNotSupported", err)
+	}
+}
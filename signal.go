@@ -0,0 +1,416 @@
+package mpris
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/spf13/cast"
+)
+
+// propertiesChanged is the decoded body of a PropertiesChanged signal.
+type propertiesChanged struct {
+	iface       string
+	changed     map[string]dbus.Variant
+	invalidated []string
+}
+
+// parsePropertiesChanged decodes the body of a PropertiesChanged signal. The
+// returned maps and slices belong to sig and must not be modified.
+func parsePropertiesChanged(sig *dbus.Signal) (propertiesChanged, bool) {
+	if sig.Name != PropertiesChangedSignal || len(sig.Body) < 2 {
+		return propertiesChanged{}, false
+	}
+	iface, ok := sig.Body[0].(string)
+	if !ok {
+		return propertiesChanged{}, false
+	}
+	changed, ok := sig.Body[1].(map[string]dbus.Variant)
+	if !ok {
+		return propertiesChanged{}, false
+	}
+	pc := propertiesChanged{iface: iface, changed: changed}
+	if len(sig.Body) > 2 {
+		pc.invalidated, _ = sig.Body[2].([]string)
+	}
+	return pc, true
+}
+
+// getOwner returns the unique bus name currently owning the player's name.
+func (i *Player) getOwner() (string, error) {
+	return i.getOwnerContext(context.Background())
+}
+
+// getOwnerContext is like getOwner but takes a context.
+func (i *Player) getOwnerContext(ctx context.Context) (string, error) {
+	ctx, cancel := i.callCtx(ctx)
+	defer cancel()
+	var owner string
+	err := busCall(ctx, i.tr, "org.freedesktop.DBus.GetNameOwner", i.name).
+		Store(&owner)
+	return owner, translateError(err)
+}
+
+// signalOwner returns the unique name signals of the player are accepted
+// from: the owner captured by NewChecked, or else the current owner.
+func (i *Player) signalOwner() (string, error) {
+	if owner, _ := i.state(); owner != "" {
+		return owner, nil
+	}
+	return i.getOwner()
+}
+
+// watchProperties subscribes to the PropertiesChanged signals the player emits
+// for iface. Properties listed in watched are re-fetched when the player only
+// reports them as invalidated.
+func (i *Player) watchProperties(
+	iface string,
+	watched []string,
+	handle func(context.Context, propertiesChanged),
+) (*Subscription, error) {
+	sender, fromPlayer, err := i.signalSender()
+	if err != nil {
+		return nil, err
+	}
+	rule := []dbus.MatchOption{
+		sender,
+		dbus.WithMatchObjectPath(i.path),
+		dbus.WithMatchInterface("org.freedesktop.DBus.Properties"),
+		dbus.WithMatchMember("PropertiesChanged"),
+		dbus.WithMatchArg(0, iface),
+	}
+	filter := func(sig *dbus.Signal) bool {
+		return fromPlayer(sig.Sender) && sig.Path == i.path
+	}
+	handler := i.observeSignals(i.propertiesHandler(iface, watched, handle))
+	return subscribe(i.tr, [][]dbus.MatchOption{rule}, filter, handler)
+}
+
+// propertiesHandler adapts handle into a signal handler receiving the
+// PropertiesChanged signals of iface, with the watched properties that were
+// only invalidated resolved by resolveInvalidated.
+func (i *Player) propertiesHandler(
+	iface string,
+	watched []string,
+	handle func(context.Context, propertiesChanged),
+) func(context.Context, *dbus.Signal) {
+	return func(ctx context.Context, sig *dbus.Signal) {
+		pc, ok := parsePropertiesChanged(sig)
+		if ok && pc.iface == iface {
+			pc = i.resolveInvalidated(ctx, pc, watched)
+			handle(ctx, i.dropEchoes(pc))
+		}
+	}
+}
+
+// resolveInvalidated fetches the current value of every watched property that
+// pc lists as invalidated without a value. Several players, notably older
+// Chromium builds, never send values for changed properties at all. The
+// returned propertiesChanged has its own changed map whenever something was
+// fetched, because the one in pc is shared with other subscriptions. Properties
+// that fail to be fetched are left out.
+func (i *Player) resolveInvalidated(
+	ctx context.Context,
+	pc propertiesChanged,
+	watched []string,
+) propertiesChanged {
+	var changed map[string]dbus.Variant
+	for _, property := range pc.invalidated {
+		if _, ok := pc.changed[property]; ok {
+			continue
+		}
+		if !slices.Contains(watched, property) {
+			continue
+		}
+		v, err := i.GetPropertyContext(ctx, pc.iface, property)
+		if err != nil {
+			continue
+		}
+		if changed == nil {
+			changed = maps.Clone(pc.changed)
+			if changed == nil {
+				changed = map[string]dbus.Variant{}
+			}
+		}
+		changed[property] = v
+	}
+	if changed != nil {
+		pc.changed = changed
+	}
+	return pc
+}
+
+// metadataHandler sends every Metadata found in a PropertiesChanged signal to
+// ch. Each delivery is a fresh copy, because the decoded map is shared by every
+// subscription that receives the same signal.
+func metadataHandler(ch chan<- Metadata) func(context.Context, propertiesChanged) {
+	return func(ctx context.Context, pc propertiesChanged) {
+		v, ok := pc.changed["Metadata"]
+		if !ok {
+			return
+		}
+		m, err := toMetadata(v.Value())
+		if err != nil {
+			return
+		}
+		select {
+		case ch <- m.Clone():
+		case <-ctx.Done():
+		}
+	}
+}
+
+// WatchMetadataChanged listens for changes of the Metadata property and sends
+// the new metadata to ch until the returned Subscription is closed. Every value
+// sent is a copy owned by the receiver.
+func (i *Player) WatchMetadataChanged(ch chan<- Metadata) (*Subscription, error) {
+	return i.watchProperties(
+		PlayerInterface,
+		[]string{"Metadata"},
+		metadataHandler(ch),
+	)
+}
+
+// OnMetadataChanged listens for changes of the Metadata property and sends the
+// new metadata to ch until ctx is canceled. Every value sent is a copy owned by
+// the receiver.
+func (i *Player) OnMetadataChanged(ctx context.Context, ch chan<- Metadata) error {
+	sub, err := i.WatchMetadataChanged(ch)
+	if err != nil {
+		return err
+	}
+	return sub.wait(ctx)
+}
+
+// Handler is a callback for a player event. Handlers are created with
+// functions such as PlaybackStatusChanged and MetadataChanged and registered
+// with Player.Subscribe.
+type Handler struct {
+	// iface and property name the property a property handler watches.
+	iface    string
+	property string
+	// onProperty receives the new value of the watched property.
+	onProperty func(context.Context, dbus.Variant)
+	// onSignal receives every other signal.
+	onSignal func(context.Context, *dbus.Signal)
+}
+
+// withoutContext adapts fn to the callbacks of the Context handlers.
+func withoutContext[T any](fn func(T)) func(context.Context, T) {
+	return func(_ context.Context, v T) { fn(v) }
+}
+
+// propertyChanged returns a Handler calling fn with the new value of property
+// on iface, cast using caster. Values that fail to cast are dropped.
+func propertyChanged[T any](
+	iface, property string,
+	caster func(any) (T, error),
+	fn func(context.Context, T),
+) Handler {
+	return Handler{
+		iface:    iface,
+		property: property,
+		onProperty: func(ctx context.Context, v dbus.Variant) {
+			val, err := caster(v.Value())
+			if err != nil {
+				return
+			}
+			fn(ctx, val)
+		},
+	}
+}
+
+// PlaybackStatusChanged returns a Handler called with the new playback status
+// whenever it changes.
+func PlaybackStatusChanged(fn func(PlaybackStatus)) Handler {
+	return PlaybackStatusChangedContext(withoutContext(fn))
+}
+
+// PlaybackStatusChangedContext is like PlaybackStatusChanged but calls fn
+// with a context carrying the Stamp of the change, see StampFromContext.
+func PlaybackStatusChangedContext(
+	fn func(context.Context, PlaybackStatus),
+) Handler {
+	return propertyChanged(PlayerInterface, "PlaybackStatus",
+		func(a any) (PlaybackStatus, error) {
+			s, err := cast.ToStringE(a)
+			return PlaybackStatus(s), err
+		}, fn)
+}
+
+// LoopStatusChanged returns a Handler called with the new loop status whenever
+// it changes.
+func LoopStatusChanged(fn func(LoopStatus)) Handler {
+	return LoopStatusChangedContext(withoutContext(fn))
+}
+
+// LoopStatusChangedContext is like LoopStatusChanged but calls fn with a
+// context carrying the Stamp of the change, see StampFromContext.
+func LoopStatusChangedContext(fn func(context.Context, LoopStatus)) Handler {
+	return propertyChanged(PlayerInterface, "LoopStatus",
+		func(a any) (LoopStatus, error) {
+			s, err := cast.ToStringE(a)
+			return LoopStatus(s), err
+		}, fn)
+}
+
+// ShuffleChanged returns a Handler called with the new shuffle mode whenever
+// it changes.
+func ShuffleChanged(fn func(bool)) Handler {
+	return ShuffleChangedContext(withoutContext(fn))
+}
+
+// ShuffleChangedContext is like ShuffleChanged but calls fn with a context
+// carrying the Stamp of the change, see StampFromContext.
+func ShuffleChangedContext(fn func(context.Context, bool)) Handler {
+	return propertyChanged(PlayerInterface, "Shuffle", cast.ToBoolE, fn)
+}
+
+// VolumeChanged returns a Handler called with the new volume whenever it
+// changes.
+func VolumeChanged(fn func(float64)) Handler {
+	return VolumeChangedContext(withoutContext(fn))
+}
+
+// VolumeChangedContext is like VolumeChanged but calls fn with a context
+// carrying the Stamp of the change, see StampFromContext.
+func VolumeChangedContext(fn func(context.Context, float64)) Handler {
+	return propertyChanged(PlayerInterface, "Volume", cast.ToFloat64E, fn)
+}
+
+// RateChanged returns a Handler called with the new playback rate whenever it
+// changes.
+func RateChanged(fn func(float64)) Handler {
+	return RateChangedContext(withoutContext(fn))
+}
+
+// RateChangedContext is like RateChanged but calls fn with a context
+// carrying the Stamp of the change, see StampFromContext.
+func RateChangedContext(fn func(context.Context, float64)) Handler {
+	return propertyChanged(PlayerInterface, "Rate", cast.ToFloat64E, fn)
+}
+
+// MetadataChanged returns a Handler called with the new metadata whenever it
+// changes. Every call receives its own copy of the metadata.
+func MetadataChanged(fn func(Metadata)) Handler {
+	return MetadataChangedContext(withoutContext(fn))
+}
+
+// MetadataChangedContext is like MetadataChanged but calls fn with a context
+// carrying the Stamp of the change, see StampFromContext.
+func MetadataChangedContext(fn func(context.Context, Metadata)) Handler {
+	return propertyChanged(PlayerInterface, "Metadata",
+		func(a any) (Metadata, error) {
+			m, err := toMetadata(a)
+			return m.Clone(), err
+		}, fn)
+}
+
+// FullscreenChanged returns a Handler called with the new fullscreen state
+// whenever it changes.
+func FullscreenChanged(fn func(bool)) Handler {
+	return FullscreenChangedContext(withoutContext(fn))
+}
+
+// FullscreenChangedContext is like FullscreenChanged but calls fn with a
+// context carrying the Stamp of the change, see StampFromContext.
+func FullscreenChangedContext(fn func(context.Context, bool)) Handler {
+	return propertyChanged(BaseInterface, "Fullscreen", cast.ToBoolE, fn)
+}
+
+// Seeked returns a Handler called with the new position whenever the player
+// emits the "Seeked" signal.
+func Seeked(fn func(time.Duration)) Handler {
+	return SeekedContext(withoutContext(fn))
+}
+
+// SeekedContext is like Seeked but calls fn with a context carrying the Stamp
+// of the signal, see StampFromContext.
+func SeekedContext(fn func(context.Context, time.Duration)) Handler {
+	return Handler{onSignal: func(ctx context.Context, sig *dbus.Signal) {
+		if position, ok := decodeSeeked(sig); ok {
+			fn(ctx, position)
+		}
+	}}
+}
+
+// callHandlers calls every handler interested in sig. A panicking handler is
+// recovered so it can't stop the delivery of later events.
+func (i *Player) callHandlers(
+	ctx context.Context,
+	handlers []Handler,
+	sig *dbus.Signal,
+) {
+	call := func(fn func()) {
+		defer func() { _ = recover() }()
+		fn()
+	}
+
+	pc, ok := parsePropertiesChanged(sig)
+	if !ok {
+		for _, h := range handlers {
+			if h.onSignal != nil {
+				call(func() { h.onSignal(ctx, sig) })
+			}
+		}
+		return
+	}
+
+	var watched []string
+	for _, h := range handlers {
+		if h.iface == pc.iface && h.onProperty != nil {
+			watched = append(watched, h.property)
+		}
+	}
+	pc = i.dropEchoes(i.resolveInvalidated(ctx, pc, watched))
+	for _, h := range handlers {
+		if h.iface != pc.iface || h.onProperty == nil {
+			continue
+		}
+		if v, ok := pc.changed[h.property]; ok {
+			call(func() { h.onProperty(ctx, v) })
+		}
+	}
+}
+
+// Subscribe registers callbacks for player events until ctx is canceled or
+// the returned Subscription is closed.
+//
+// All handlers of one Subscribe call are invoked from a single goroutine owned
+// by the library, one at a time and in the order the signals arrived, which
+// makes it safe to update UI state from them without extra locking. A handler
+// that panics is recovered and does not stop later events. Subscribe may be
+// called any number of times; every call creates an independent Subscription,
+// whose Close may also be called from inside a handler.
+func (i *Player) Subscribe(ctx context.Context, handlers ...Handler) (*Subscription, error) {
+	sender, fromPlayer, err := i.signalSender()
+	if err != nil {
+		return nil, err
+	}
+	rule := []dbus.MatchOption{
+		sender,
+		dbus.WithMatchObjectPath(i.path),
+	}
+	filter := func(sig *dbus.Signal) bool {
+		return fromPlayer(sig.Sender) && sig.Path == i.path
+	}
+	handle := i.observeSignals(func(ctx context.Context, sig *dbus.Signal) {
+		i.callHandlers(ctx, handlers, sig)
+	})
+	sub := newSubscription(filter, handle)
+	sub.detached = true
+	if err := sub.start(i.tr, rule); err != nil {
+		return nil, err
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-sub.Done():
+		}
+		sub.Close()
+	}()
+	return sub, nil
+}
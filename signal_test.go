@@ -0,0 +1,354 @@
+package mpris
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// receive waits for a value on ch, failing the test after a timeout.
+func receive[T any](t *testing.T, ch <-chan T) T {
+	t.Helper()
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for event")
+		panic("unreachable")
+	}
+}
+
+// expectNothing fails the test if a value arrives on ch within a short window.
+func expectNothing[T any](t *testing.T, ch <-chan T) {
+	t.Helper()
+	select {
+	case v := <-ch:
+		t.Fatalf("Unexpected event: %v", v)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func metadataWithTitle(title string) map[string]dbus.Variant {
+	return map[string]dbus.Variant{
+		"Metadata": dbus.MakeVariant(map[string]dbus.Variant{
+			"xesam:title": dbus.MakeVariant(title),
+		}),
+	}
+}
+
+func TestSubscriptionCloseKeepsOtherSubscriptions(t *testing.T) {
+	server, player := testBus(t)
+
+	first := make(chan Metadata, 1)
+	second := make(chan Metadata, 1)
+
+	sub1, err := player.WatchMetadataChanged(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub2, err := player.WatchMetadataChanged(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub2.Close()
+
+	emitPropertiesChanged(t, server, PlayerInterface, metadataWithTitle("one"))
+	receive(t, first)
+	receive(t, second)
+
+	if err := sub1.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	if err := sub1.Close(); err != nil {
+		t.Fatalf("Second Close returned error: %v", err)
+	}
+	if err := sub1.Err(); err != nil {
+		t.Errorf("Err after Close = %v, want nil", err)
+	}
+
+	emitPropertiesChanged(t, server, PlayerInterface, metadataWithTitle("two"))
+	m := receive(t, second)
+	if title, _ := m.Get("xesam:title"); title != "two" {
+		t.Errorf("title = %v, want two", title)
+	}
+	expectNothing(t, first)
+}
+
+func TestSubscriptionErrOnConnectionClose(t *testing.T) {
+	_, player := testBus(t)
+
+	sub, err := player.WatchMetadataChanged(make(chan Metadata))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	player.conn.Close()
+	receive(t, sub.Done())
+	if sub.Err() == nil {
+		t.Error("Err = nil after the connection was closed")
+	}
+}
+
+func TestOnSeekedFiltersOtherInterfaces(t *testing.T) {
+	server, player := testBus(t)
+
+	ch := make(chan time.Duration, 1)
+	sub, err := player.WatchSeeked(ch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	emitPropertiesChanged(t, server, PlayerInterface, metadataWithTitle("x"))
+	err = server.Emit(DBusObjectPath, PlayerInterface+".Seeked", int64(1500))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := receive(t, ch); got != 1500*time.Microsecond {
+		t.Errorf("position = %v, want 1.5ms", got)
+	}
+	expectNothing(t, ch)
+}
+
+func TestSubscribeCallbacksInSignalOrder(t *testing.T) {
+	server, player := testBus(t)
+
+	events := make(chan string, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, err := player.Subscribe(ctx,
+		PlaybackStatusChanged(func(s PlaybackStatus) {
+			events <- "status:" + string(s)
+		}),
+		MetadataChanged(func(m Metadata) {
+			title, _ := m.Get("xesam:title")
+			events <- "title:" + title.(string)
+		}),
+		Seeked(func(d time.Duration) {
+			events <- "seeked:" + d.String()
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	emitPropertiesChanged(t, server, PlayerInterface, map[string]dbus.Variant{
+		"PlaybackStatus": dbus.MakeVariant("Playing"),
+	})
+	emitPropertiesChanged(t, server, PlayerInterface, metadataWithTitle("song"))
+	if err := server.Emit(DBusObjectPath, PlayerInterface+".Seeked", int64(2000)); err != nil {
+		t.Fatal(err)
+	}
+	emitPropertiesChanged(t, server, PlayerInterface, map[string]dbus.Variant{
+		"PlaybackStatus": dbus.MakeVariant("Paused"),
+	})
+
+	want := []string{"status:Playing", "title:song", "seeked:2ms", "status:Paused"}
+	for _, w := range want {
+		if got := receive(t, events); got != w {
+			t.Errorf("event = %q, want %q", got, w)
+		}
+	}
+}
+
+func TestSubscribeSurvivesPanickingHandler(t *testing.T) {
+	server, player := testBus(t)
+
+	statuses := make(chan PlaybackStatus, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, err := player.Subscribe(ctx,
+		PlaybackStatusChanged(func(s PlaybackStatus) {
+			if s == PlaybackStopped {
+				panic("boom")
+			}
+		}),
+		PlaybackStatusChanged(func(s PlaybackStatus) { statuses <- s }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{"Stopped", "Playing"} {
+		emitPropertiesChanged(t, server, PlayerInterface, map[string]dbus.Variant{
+			"PlaybackStatus": dbus.MakeVariant(s),
+		})
+	}
+	if got := receive(t, statuses); got != PlaybackStopped {
+		t.Errorf("status = %q, want Stopped", got)
+	}
+	if got := receive(t, statuses); got != PlaybackPlaying {
+		t.Errorf("status = %q, want Playing", got)
+	}
+}
+
+func TestSubscribeMultipleTimes(t *testing.T) {
+	server, player := testBus(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	volumes := make(chan float64, 4)
+	var subs []*Subscription
+	for range 2 {
+		var self atomic.Pointer[Subscription]
+		sub, err := player.Subscribe(ctx, VolumeChanged(func(v float64) {
+			volumes <- v
+			// Closing from inside a callback must not deadlock.
+			self.Load().Close()
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		self.Store(sub)
+		subs = append(subs, sub)
+	}
+
+	emitPropertiesChanged(t, server, PlayerInterface, map[string]dbus.Variant{
+		"Volume": dbus.MakeVariant(0.5),
+	})
+	receive(t, volumes)
+	receive(t, volumes)
+	for _, sub := range subs {
+		receive(t, sub.Done())
+	}
+
+	emitPropertiesChanged(t, server, PlayerInterface, map[string]dbus.Variant{
+		"Volume": dbus.MakeVariant(0.7),
+	})
+	expectNothing(t, volumes)
+}
+
+func TestSubscribeStopsWithContext(t *testing.T) {
+	_, player := testBus(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sub, err := player.Subscribe(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	receive(t, sub.Done())
+	if err := sub.Err(); err != nil {
+		t.Errorf("Err = %v, want nil", err)
+	}
+}
+
+func TestInvalidatedPropertiesAreFetched(t *testing.T) {
+	server, player := testBus(t)
+
+	props := exportTestProperties(t, server, map[string]map[string]any{
+		PlayerInterface: {
+			"PlaybackStatus": "Stopped",
+			"Metadata": map[string]dbus.Variant{
+				"xesam:title": dbus.MakeVariant("old"),
+			},
+		},
+	})
+	props.setEmit("PlaybackStatus", emitInvalidates)
+	props.setEmit("Metadata", emitInvalidates)
+
+	metadata := make(chan Metadata, 1)
+	sub, err := player.WatchMetadataChanged(metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	statuses := make(chan PlaybackStatus, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, err = player.Subscribe(ctx,
+		PlaybackStatusChanged(func(s PlaybackStatus) { statuses <- s }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	props.set(PlayerInterface, "PlaybackStatus", "Playing")
+	if got := receive(t, statuses); got != PlaybackPlaying {
+		t.Errorf("status = %q, want Playing", got)
+	}
+
+	props.set(PlayerInterface, "Metadata", map[string]dbus.Variant{
+		"xesam:title": dbus.MakeVariant("new"),
+	})
+	m := receive(t, metadata)
+	if title, _ := m.Get("xesam:title"); title != "new" {
+		t.Errorf("title = %v, want new", title)
+	}
+}
+
+func TestInvalidatedUnwatchedPropertiesAreIgnored(t *testing.T) {
+	server, player := testBus(t)
+
+	metadata := make(chan Metadata, 1)
+	sub, err := player.WatchMetadataChanged(metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	// Nothing is exported, so fetching anything would fail; the signal must
+	// simply not produce an event.
+	emitPropertiesChanged(t, server, PlayerInterface, nil, "Volume")
+	expectNothing(t, metadata)
+}
+
+func TestSeekedHandlerNumericTypes(t *testing.T) {
+	tests := []struct {
+		name string
+		body any
+		want time.Duration
+		ok   bool
+	}{
+		{"int64", int64(1_500_000), 1500 * time.Millisecond, true},
+		{"uint64", uint64(1_500_000), 1500 * time.Millisecond, true},
+		{"int32", int32(250_000), 250 * time.Millisecond, true},
+		{"uint32", uint32(250_000), 250 * time.Millisecond, true},
+		{"negative int64", int64(-1), -time.Microsecond, true},
+		{"string", "1500000", 0, false},
+		{"double", 1.5, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sig := &dbus.Signal{
+				Name: PlayerInterface + ".Seeked",
+				Body: []any{tt.body},
+			}
+
+			ch := make(chan time.Duration, 1)
+			seekedHandler(ch)(context.Background(), sig)
+
+			var called bool
+			var got time.Duration
+			Seeked(func(d time.Duration) {
+				called = true
+				got = d
+			}).onSignal(context.Background(), sig)
+
+			if !tt.ok {
+				if len(ch) != 0 || called {
+					t.Errorf("%T body was accepted", tt.body)
+				}
+				return
+			}
+			if len(ch) != 1 {
+				t.Fatalf("%T body was dropped", tt.body)
+			}
+			if pos := <-ch; pos != tt.want {
+				t.Errorf("channel position = %v, want %v", pos, tt.want)
+			}
+			if !called || got != tt.want {
+				t.Errorf("callback position = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,396 @@
+package mpris
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/spf13/cast"
+)
+
+// PlayerProperties holds the properties of the player interface, fetched in a
+// single call by GetAllPlayerProperties. Properties the player doesn't report
+// are zero.
+type PlayerProperties struct {
+	PlaybackStatus PlaybackStatus
+	LoopStatus     LoopStatus
+	Rate           float64
+	Shuffle        bool
+	Volume         float64
+	Position       time.Duration
+	MinimumRate    float64
+	MaximumRate    float64
+	Metadata       Metadata
+	Capabilities
+}
+
+// BaseProperties holds the properties of the base interface, fetched in a
+// single call by GetAllBaseProperties. Properties the player doesn't report
+// are zero.
+type BaseProperties struct {
+	CanQuit             bool
+	Fullscreen          bool
+	CanSetFullscreen    bool
+	CanRaise            bool
+	HasTrackList        bool
+	Identity            string
+	DesktopEntry        string
+	SupportedURISchemes []string
+	SupportedMimeTypes  []string
+}
+
+// propertyError is the error of a single property, so the aggregate calls can
+// tell which field an error belongs to.
+type propertyError struct {
+	// property is the name of the property, qualified with its interface.
+	property string
+	err      error
+}
+
+func (e *propertyError) Error() string { return e.err.Error() }
+func (e *propertyError) Unwrap() error { return e.err }
+
+// collectPropertyErrors adds the propertyErrors joined in err to errs, which
+// is allocated when needed, and returns errs.
+func collectPropertyErrors(errs map[string]error, err error) map[string]error {
+	switch e := err.(type) {
+	case *propertyError:
+		if errs == nil {
+			errs = map[string]error{}
+		}
+		errs[e.property] = e.err
+	case interface{ Unwrap() []error }:
+		for _, err := range e.Unwrap() {
+			errs = collectPropertyErrors(errs, err)
+		}
+	}
+	return errs
+}
+
+// decodeProperty casts the property name from props into dst when it is
+// present. Missing properties leave dst untouched; a failed cast is returned as
+// an error naming the property.
+func decodeProperty[T any](
+	props map[string]dbus.Variant,
+	iface, name string,
+	dst *T,
+	caster func(any) (T, error),
+) error {
+	v, ok := props[name]
+	if !ok || v.Value() == nil {
+		return nil
+	}
+	val, err := caster(v.Value())
+	if err != nil {
+		return &propertyError{iface + "." + name, fmt.Errorf(
+			"failed to cast %s.%s value (%v): %w",
+			iface,
+			name,
+			v.Value(),
+			err,
+		)}
+	}
+	*dst = val
+	return nil
+}
+
+func toPlaybackStatus(a any) (PlaybackStatus, error) {
+	s, err := cast.ToStringE(a)
+	return PlaybackStatus(s), err
+}
+
+func toLoopStatus(a any) (LoopStatus, error) {
+	s, err := cast.ToStringE(a)
+	return LoopStatus(s), err
+}
+
+// decodePlayerProperties decodes the result of GetAll on the player interface.
+// Like Metadata.Decode, values that fail to cast are reported together with an
+// otherwise complete result.
+func decodePlayerProperties(props map[string]dbus.Variant) (PlayerProperties, error) {
+	var p PlayerProperties
+	const iface = PlayerInterface
+	err := errors.Join(
+		decodeProperty(props, iface, "PlaybackStatus", &p.PlaybackStatus, toPlaybackStatus),
+		decodeProperty(props, iface, "LoopStatus", &p.LoopStatus, toLoopStatus),
+		decodeProperty(props, iface, "Rate", &p.Rate, cast.ToFloat64E),
+		decodeProperty(props, iface, "Shuffle", &p.Shuffle, cast.ToBoolE),
+		decodeProperty(props, iface, "Volume", &p.Volume, cast.ToFloat64E),
+		decodeProperty(props, iface, "Position", &p.Position, microsecondsToDuration),
+		decodeProperty(props, iface, "MinimumRate", &p.MinimumRate, cast.ToFloat64E),
+		decodeProperty(props, iface, "MaximumRate", &p.MaximumRate, cast.ToFloat64E),
+		decodeProperty(props, iface, "Metadata", &p.Metadata, toMetadata),
+	)
+	p.Capabilities.update(props)
+	return p, err
+}
+
+// GetAllPlayerProperties returns the properties of the player interface with
+// a single GetAll call. Properties the player omits are left zero; values that
+// can't be cast are reported in the error alongside the rest of the result.
+func (i *Player) GetAllPlayerProperties() (PlayerProperties, error) {
+	return i.GetAllPlayerPropertiesContext(context.Background())
+}
+
+// GetAllPlayerPropertiesContext is like GetAllPlayerProperties but takes a
+// context.
+func (i *Player) GetAllPlayerPropertiesContext(
+	ctx context.Context,
+) (PlayerProperties, error) {
+	props, err := i.GetAllPropertiesContext(ctx, PlayerInterface)
+	if err != nil {
+		return PlayerProperties{}, err
+	}
+	return decodePlayerProperties(props)
+}
+
+// decodeBaseProperties decodes the result of GetAll on the base interface.
+func decodeBaseProperties(props map[string]dbus.Variant) (BaseProperties, error) {
+	var b BaseProperties
+	const iface = BaseInterface
+	err := errors.Join(
+		decodeProperty(props, iface, "CanQuit", &b.CanQuit, cast.ToBoolE),
+		decodeProperty(props, iface, "Fullscreen", &b.Fullscreen, cast.ToBoolE),
+		decodeProperty(props, iface, "CanSetFullscreen", &b.CanSetFullscreen, cast.ToBoolE),
+		decodeProperty(props, iface, "CanRaise", &b.CanRaise, cast.ToBoolE),
+		decodeProperty(props, iface, "HasTrackList", &b.HasTrackList, cast.ToBoolE),
+		decodeProperty(props, iface, "Identity", &b.Identity, cast.ToStringE),
+		decodeProperty(props, iface, "DesktopEntry", &b.DesktopEntry, cast.ToStringE),
+		decodeProperty(props, iface, "SupportedUriSchemes", &b.SupportedURISchemes, toStrings),
+		decodeProperty(props, iface, "SupportedMimeTypes", &b.SupportedMimeTypes, toStrings),
+	)
+	return b, err
+}
+
+// GetAllBaseProperties returns the properties of the base interface with a
+// single GetAll call. See GetAllPlayerProperties.
+func (i *Player) GetAllBaseProperties() (BaseProperties, error) {
+	return i.GetAllBasePropertiesContext(context.Background())
+}
+
+// GetAllBasePropertiesContext is like GetAllBaseProperties but takes a context.
+func (i *Player) GetAllBasePropertiesContext(
+	ctx context.Context,
+) (BaseProperties, error) {
+	props, err := i.GetAllPropertiesContext(ctx, BaseInterface)
+	if err != nil {
+		return BaseProperties{}, err
+	}
+	return decodeBaseProperties(props)
+}
+
+// Status is a snapshot of the state a now-playing widget usually shows.
+type Status struct {
+	Identity       string
+	PlaybackStatus PlaybackStatus
+	Track          TrackMetadata
+	Position       time.Duration
+	Volume         float64
+	Shuffle        bool
+	LoopStatus     LoopStatus
+	Capabilities
+	// Missing lists the properties the player didn't report, qualified with
+	// their interface and sorted. Their fields are zero.
+	Missing []string
+	// Errors holds the errors of the fields that couldn't be read or cast, by
+	// the qualified name of their property. Errors about properties the
+	// player doesn't implement match ErrNotSupported, so a UI can disable the
+	// controls they back. Their fields are zero.
+	Errors map[string]error
+}
+
+// statusProperties lists the player properties backing Status.
+var statusProperties = append([]string{
+	"PlaybackStatus",
+	"Metadata",
+	"Position",
+	"Volume",
+	"Shuffle",
+	"LoopStatus",
+}, capabilityProperties...)
+
+// missingProperties returns the names out of names that props lacks.
+func missingProperties(props map[string]dbus.Variant, names ...string) []string {
+	var missing []string
+	for _, name := range names {
+		if v, ok := props[name]; !ok || v.Value() == nil {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// readEach reads the properties names of iface one by one, concurrently, for
+// players whose GetAll fails although some of the properties can be read. The
+// errors are keyed by the qualified property name; the ones about properties
+// the player doesn't implement match ErrNotSupported.
+func (i *Player) readEach(
+	ctx context.Context,
+	iface string,
+	names []string,
+) (map[string]dbus.Variant, map[string]error) {
+	values := make([]dbus.Variant, len(names))
+	errs := make([]error, len(names))
+	tasks := make([]func(), len(names))
+	for n, name := range names {
+		tasks[n] = func() {
+			values[n], errs[n] = i.GetPropertyContext(ctx, iface, name)
+		}
+	}
+	parallel(listWorkers, tasks...)
+
+	props := map[string]dbus.Variant{}
+	var failed map[string]error
+	for n, name := range names {
+		if errs[n] != nil {
+			failed = collectPropertyErrors(failed, &propertyError{
+				iface + "." + name,
+				missingAsNotSupported(errs[n]),
+			})
+			continue
+		}
+		props[name] = values[n]
+	}
+	return props, failed
+}
+
+// readableOneByOne reports whether the properties of a player whose GetAll
+// failed with err may still be read one by one, rather than the player being
+// gone, lacking the interface or the call being canceled.
+func readableOneByOne(ctx context.Context, err error) bool {
+	return ctx.Err() == nil &&
+		!errors.Is(err, ErrPlayerGone) &&
+		!errors.Is(err, ErrNotSupported)
+}
+
+// Status returns a snapshot of the player with two concurrent GetAll calls,
+// one per interface, so it takes about one round trip. Properties the player
+// doesn't implement are listed in Status.Missing instead of failing the call,
+// and so is Identity when the base interface can't be read at all. When GetAll
+// fails on the player interface, the properties are read one by one, and
+// the ones failing are listed in Status.Missing and Status.Errors. Values
+// that can't be cast are reported in Status.Errors and in the error alongside
+// an otherwise complete Status.
+func (i *Player) Status() (Status, error) {
+	return i.StatusContext(context.Background())
+}
+
+// StatusContext is like Status but takes a context.
+func (i *Player) StatusContext(ctx context.Context) (Status, error) {
+	var (
+		props, base  map[string]dbus.Variant
+		err, baseErr error
+		readErrs     map[string]error
+	)
+	parallel(2, func() {
+		props, err = i.GetAllPropertiesContext(ctx, PlayerInterface)
+	}, func() {
+		base, baseErr = i.GetAllPropertiesContext(ctx, BaseInterface)
+	})
+	if err != nil {
+		if !readableOneByOne(ctx, err) {
+			return Status{}, err
+		}
+		props, readErrs = i.readEach(ctx, PlayerInterface, statusProperties)
+		if len(props) == 0 {
+			return Status{}, err
+		}
+	}
+	p, err := decodePlayerProperties(props)
+	s := Status{
+		PlaybackStatus: p.PlaybackStatus,
+		Position:       p.Position,
+		Volume:         p.Volume,
+		Shuffle:        p.Shuffle,
+		LoopStatus:     p.LoopStatus,
+		Capabilities:   p.Capabilities,
+		Errors:         readErrs,
+	}
+	var trackErr error
+	s.Track, trackErr = p.Metadata.decode(p.Metadata)
+	if trackErr != nil {
+		trackErr = &propertyError{PlayerInterface + ".Metadata", trackErr}
+	}
+	err = errors.Join(err, trackErr)
+
+	if baseErr == nil {
+		baseErr = decodeProperty(base, BaseInterface, "Identity", &s.Identity, cast.ToStringE)
+		err = errors.Join(err, baseErr)
+	} else {
+		s.Errors = collectPropertyErrors(s.Errors, &propertyError{
+			BaseInterface + ".Identity",
+			baseErr,
+		})
+	}
+	s.Errors = collectPropertyErrors(s.Errors, err)
+	for _, name := range missingProperties(base, "Identity") {
+		s.Missing = append(s.Missing, BaseInterface+"."+name)
+	}
+	for _, name := range missingProperties(props, statusProperties...) {
+		s.Missing = append(s.Missing, PlayerInterface+"."+name)
+	}
+	slices.Sort(s.Missing)
+	return s, err
+}
+
+// baseProperties lists the properties of the base interface, as named on the
+// bus.
+var baseProperties = []string{
+	"CanQuit",
+	"Fullscreen",
+	"CanSetFullscreen",
+	"CanRaise",
+	"HasTrackList",
+	"Identity",
+	"DesktopEntry",
+	"SupportedUriSchemes",
+	"SupportedMimeTypes",
+}
+
+// AppInfo is a snapshot of the base interface, what a player picker usually
+// shows next to the players: their name, icon and capabilities.
+type AppInfo struct {
+	BaseProperties
+	// Present tells, by the name of every property of the base interface as
+	// on the bus, such as "DesktopEntry", whether the player reported it.
+	// The fields of the properties it didn't report are zero.
+	Present map[string]bool
+}
+
+// Has reports whether the player reported the base interface property name,
+// such as "Fullscreen", which the specification makes optional.
+func (a AppInfo) Has(name string) bool {
+	return a.Present[name]
+}
+
+// AppInfo returns a snapshot of the base interface with a single GetAll call.
+// Properties the player doesn't implement, such as the optional Fullscreen
+// and DesktopEntry, are reported as absent in AppInfo.Present instead of
+// failing the call. Values that can't be cast are reported in the error
+// alongside an otherwise complete AppInfo.
+func (i *Player) AppInfo() (AppInfo, error) {
+	return i.AppInfoContext(context.Background())
+}
+
+// AppInfoContext is like AppInfo but takes a context.
+func (i *Player) AppInfoContext(ctx context.Context) (AppInfo, error) {
+	props, err := i.GetAllPropertiesContext(ctx, BaseInterface)
+	if err != nil {
+		return AppInfo{}, err
+	}
+	b, err := decodeBaseProperties(props)
+	a := AppInfo{
+		BaseProperties: b,
+		Present:        make(map[string]bool, len(baseProperties)),
+	}
+	for _, name := range baseProperties {
+		a.Present[name] = true
+	}
+	for _, name := range missingProperties(props, baseProperties...) {
+		a.Present[name] = false
+	}
+	return a, err
+}
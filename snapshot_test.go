@@ -0,0 +1,193 @@
+package mpris
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestGetAllPlayerProperties(t *testing.T) {
+	server, player := testBus(t)
+	exportTestProperties(t, server, map[string]map[string]any{
+		PlayerInterface: {
+			"PlaybackStatus": "Playing",
+			"LoopStatus":     "Playlist",
+			"Rate":           1.0,
+			"Shuffle":        true,
+			"Volume":         0.5,
+			"Position":       uint64(3_000_000),
+			"MinimumRate":    0.5,
+			"MaximumRate":    2.0,
+			"CanGoNext":      true,
+			"CanPlay":        true,
+			"CanControl":     true,
+			"Metadata": map[string]dbus.Variant{
+				"xesam:title": dbus.MakeVariant("Title"),
+			},
+			"X-Vendor": "ignored",
+		},
+	})
+
+	got, err := player.GetAllPlayerProperties()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.PlaybackStatus != PlaybackPlaying || got.LoopStatus != LoopPlaylist {
+		t.Errorf("statuses = %q, %q", got.PlaybackStatus, got.LoopStatus)
+	}
+	if got.Rate != 1 || !got.Shuffle || got.Volume != 0.5 || got.MinimumRate != 0.5 || got.MaximumRate != 2 {
+		t.Errorf("GetAllPlayerProperties() = %+v", got)
+	}
+	if got.Position != 3*time.Second {
+		t.Errorf("Position = %v, want 3s", got.Position)
+	}
+	want := Capabilities{CanGoNext: true, CanPlay: true, CanControl: true}
+	if got.Capabilities != want {
+		t.Errorf("Capabilities = %+v, want %+v", got.Capabilities, want)
+	}
+	if title, _ := got.Metadata.GetString("xesam:title"); title != "Title" {
+		t.Errorf("Metadata title = %q", title)
+	}
+}
+
+func TestGetAllPlayerPropertiesOptional(t *testing.T) {
+	server, player := testBus(t)
+	exportTestProperties(t, server, map[string]map[string]any{
+		PlayerInterface: {
+			"PlaybackStatus": "Paused",
+			"Volume":         []string{"not", "a", "number"},
+		},
+	})
+
+	got, err := player.GetAllPlayerProperties()
+	if err == nil {
+		t.Error("GetAllPlayerProperties accepted a bad Volume")
+	}
+	if got.PlaybackStatus != PlaybackPaused || got.LoopStatus != "" || got.Shuffle || got.Metadata != nil {
+		t.Errorf("GetAllPlayerProperties() = %+v", got)
+	}
+}
+
+func TestGetAllBaseProperties(t *testing.T) {
+	server, player := testBus(t)
+	exportTestProperties(t, server, map[string]map[string]any{
+		BaseInterface: {
+			"CanQuit":             true,
+			"CanRaise":            false,
+			"HasTrackList":        false,
+			"Identity":            "VLC media player",
+			"DesktopEntry":        "vlc",
+			"SupportedUriSchemes": []string{"file", "http"},
+			"SupportedMimeTypes":  []string{"audio/mpeg"},
+		},
+	})
+
+	got, err := player.GetAllBaseProperties()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.CanQuit || got.CanRaise || got.Fullscreen || got.Identity != "VLC media player" || got.DesktopEntry != "vlc" {
+		t.Errorf("GetAllBaseProperties() = %+v", got)
+	}
+	if !slices.Equal(got.SupportedURISchemes, []string{"file", "http"}) ||
+		!slices.Equal(got.SupportedMimeTypes, []string{"audio/mpeg"}) {
+		t.Errorf("supported = %q, %q", got.SupportedURISchemes, got.SupportedMimeTypes)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	server, player := testBus(t)
+	exportTestProperties(t, server, map[string]map[string]any{
+		BaseInterface: {"Identity": "mpv"},
+		PlayerInterface: {
+			"PlaybackStatus": "Playing",
+			"Position":       int64(1_000_000),
+			"Volume":         0.8,
+			"CanPlay":        true,
+			"CanPause":       true,
+			"Metadata": map[string]dbus.Variant{
+				"xesam:title":  dbus.MakeVariant("Title"),
+				"mpris:length": dbus.MakeVariant(int64(60_000_000)),
+			},
+		},
+	})
+
+	got, err := player.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Identity != "mpv" || got.PlaybackStatus != PlaybackPlaying || got.Volume != 0.8 {
+		t.Errorf("Status() = %+v", got)
+	}
+	if got.Position != time.Second || got.Track.Title != "Title" || got.Track.Length != time.Minute {
+		t.Errorf("Status() position and track = %v, %+v", got.Position, got.Track)
+	}
+	if !got.CanPlay || !got.CanPause || got.CanSeek {
+		t.Errorf("Status() capabilities = %+v", got.Capabilities)
+	}
+	want := []string{
+		PlayerInterface + ".CanControl",
+		PlayerInterface + ".CanGoNext",
+		PlayerInterface + ".CanGoPrevious",
+		PlayerInterface + ".CanSeek",
+		PlayerInterface + ".LoopStatus",
+		PlayerInterface + ".Shuffle",
+	}
+	if !slices.Equal(got.Missing, want) {
+		t.Errorf("Missing = %q, want %q", got.Missing, want)
+	}
+}
+
+func TestStatusWithoutBaseInterface(t *testing.T) {
+	server, player := testBus(t)
+	exportTestProperties(t, server, map[string]map[string]any{
+		PlayerInterface: {"PlaybackStatus": "Stopped"},
+	})
+
+	got, err := player.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.PlaybackStatus != PlaybackStopped || !slices.Contains(got.Missing, BaseInterface+".Identity") {
+		t.Errorf("Status() = %+v", got)
+	}
+}
+
+func TestAppInfo(t *testing.T) {
+	server, player := testBus(t)
+	exportTestProperties(t, server, map[string]map[string]any{
+		BaseInterface: {
+			"CanQuit":             true,
+			"CanRaise":            true,
+			"CanSetFullscreen":    false,
+			"HasTrackList":        false,
+			"Identity":            "Spotify",
+			"SupportedUriSchemes": []string{"spotify"},
+			"SupportedMimeTypes":  []string{},
+		},
+	})
+
+	got, err := player.AppInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.CanQuit || !got.CanRaise || got.Identity != "Spotify" ||
+		!slices.Equal(got.SupportedURISchemes, []string{"spotify"}) {
+		t.Errorf("AppInfo() = %+v", got)
+	}
+	for _, name := range []string{"Identity", "CanQuit", "HasTrackList", "SupportedMimeTypes"} {
+		if !got.Has(name) {
+			t.Errorf("Has(%q) = false, want true", name)
+		}
+	}
+	for _, name := range []string{"Fullscreen", "DesktopEntry"} {
+		if got.Has(name) {
+			t.Errorf("Has(%q) = true, want false", name)
+		}
+	}
+	if len(got.Present) != 9 {
+		t.Errorf("Present = %v, want every base property", got.Present)
+	}
+}
@@ -0,0 +1,54 @@
+package mpris
+
+import (
+	"context"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// Stamp tells when and in which order the signal behind an event was
+// observed, for events such as Event and TrackChange. The handlers made by
+// the Context variants of the Handler constructors, such as SeekedContext,
+// read it with StampFromContext.
+type Stamp struct {
+	// Timestamp is when the library read the signal off the bus. It is a
+	// local observation time, not the time the player changed, which D-Bus
+	// doesn't convey; signals may reach the bus late from a busy player.
+	Timestamp time.Time
+	// Seq is the sequence number of the signal on its connection, which
+	// increases with every message read, so it orders events across every
+	// subscription on the same connection, unlike Timestamp, which the
+	// clock may move back. It is 0 for events no signal caused, such as the
+	// EventPlayerAdded of the players already on the bus when WatchPlayers
+	// started.
+	Seq uint64
+}
+
+// newStamp returns the Stamp of sig, observed at t.
+func newStamp(sig *dbus.Signal, t time.Time) Stamp {
+	return Stamp{Timestamp: t, Seq: uint64(sig.Sequence)}
+}
+
+// stampKey is the context key of the Stamp of the signal being handled.
+type stampKey struct{}
+
+// withStamp returns ctx carrying stamp, for the handler of the signal it
+// belongs to.
+func withStamp(ctx context.Context, stamp Stamp) context.Context {
+	return context.WithValue(ctx, stampKey{}, stamp)
+}
+
+// StampFromContext returns the Stamp of the signal whose handler was called
+// with ctx, such as a handler made by SeekedContext, and whether ctx carries
+// one.
+func StampFromContext(ctx context.Context) (Stamp, bool) {
+	stamp, ok := ctx.Value(stampKey{}).(Stamp)
+	return stamp, ok
+}
+
+// stampOf returns the Stamp of the signal handled with ctx, if any.
+func stampOf(ctx context.Context) Stamp {
+	stamp, _ := StampFromContext(ctx)
+	return stamp
+}
@@ -0,0 +1,102 @@
+package mpris
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestEventStamps(t *testing.T) {
+	addr := testBusAddress(t)
+	claimName(t, addr, BaseInterface+".existing")
+	client := testConn(t, addr)
+
+	var subs [2]chan Event
+	for n := range subs {
+		subs[n] = make(chan Event, 10)
+		sub, err := WatchPlayers(client, subs[n])
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sub.Close()
+		if e := receive(t, subs[n]); e.Seq != 0 || e.Timestamp.IsZero() {
+			t.Errorf("stamp of an existing player = %+v, want no Seq", e.Stamp)
+		}
+	}
+
+	start := time.Now()
+	player := claimName(t, addr, BaseInterface+".seeking")
+	for _, position := range []int64{1000, 2000} {
+		err := player.Emit(DBusObjectPath, PlayerInterface+".Seeked", position)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var last uint64
+	for range 3 {
+		e := receive(t, subs[0])
+		if other := receive(t, subs[1]); other.Stamp != e.Stamp {
+			t.Errorf("%v stamped %+v and %+v by two subscriptions", e.Kind, e.Stamp, other.Stamp)
+		}
+		if e.Seq <= last {
+			t.Errorf("%v Seq = %d after %d", e.Kind, e.Seq, last)
+		}
+		if e.Timestamp.Before(start) || e.Timestamp.After(time.Now()) {
+			t.Errorf("%v Timestamp = %v, want after %v", e.Kind, e.Timestamp, start)
+		}
+		last = e.Seq
+	}
+}
+
+func TestHandlerStamps(t *testing.T) {
+	server, player := testBus(t)
+	type stamped struct {
+		stamp Stamp
+		ok    bool
+	}
+	stamps := make(chan stamped, 2)
+	handle := func(ctx context.Context) {
+		stamp, ok := StampFromContext(ctx)
+		stamps <- stamped{stamp, ok}
+	}
+	sub, err := player.Subscribe(context.Background(),
+		SeekedContext(func(ctx context.Context, _ time.Duration) {
+			handle(ctx)
+		}),
+		PlaybackStatusChangedContext(func(ctx context.Context, _ PlaybackStatus) {
+			handle(ctx)
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	start := time.Now()
+	err = server.Emit(DBusObjectPath, PlayerInterface+".Seeked", int64(1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	emitPropertiesChanged(t, server, PlayerInterface, map[string]dbus.Variant{
+		"PlaybackStatus": dbus.MakeVariant("Paused"),
+	})
+
+	var last uint64
+	for _, event := range []string{"Seeked", "PlaybackStatus"} {
+		s := receive(t, stamps)
+		if !s.ok || s.stamp.Seq <= last {
+			t.Errorf("%s stamp = %+v, %v, want Seq after %d", event, s.stamp, s.ok, last)
+		}
+		if s.stamp.Timestamp.Before(start) || s.stamp.Timestamp.After(time.Now()) {
+			t.Errorf("%s Timestamp = %v, want after %v", event, s.stamp.Timestamp, start)
+		}
+		last = s.stamp.Seq
+	}
+
+	if stamp, ok := StampFromContext(context.Background()); ok {
+		t.Errorf("StampFromContext(Background) = %+v, want none", stamp)
+	}
+}
@@ -0,0 +1,129 @@
+package mpris
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// statusConfirmTimeout bounds how long PlayPauseAndStatus waits for the player
+// to announce its new playback status.
+const statusConfirmTimeout = time.Second
+
+// isStatus returns whether the playback status of the player is want.
+func (i *Player) isStatus(ctx context.Context, want PlaybackStatus) (bool, error) {
+	status, err := i.GetPlaybackStatusContext(ctx)
+	return status == want, err
+}
+
+// IsPlaying returns whether the player is playing.
+func (i *Player) IsPlaying() (bool, error) {
+	return i.IsPlayingContext(context.Background())
+}
+
+// IsPlayingContext is like IsPlaying but takes a context.
+func (i *Player) IsPlayingContext(ctx context.Context) (bool, error) {
+	return i.isStatus(ctx, PlaybackPlaying)
+}
+
+// IsPaused returns whether the player is paused.
+func (i *Player) IsPaused() (bool, error) {
+	return i.IsPausedContext(context.Background())
+}
+
+// IsPausedContext is like IsPaused but takes a context.
+func (i *Player) IsPausedContext(ctx context.Context) (bool, error) {
+	return i.isStatus(ctx, PlaybackPaused)
+}
+
+// IsStopped returns whether the player is stopped.
+func (i *Player) IsStopped() (bool, error) {
+	return i.IsStoppedContext(context.Background())
+}
+
+// IsStoppedContext is like IsStopped but takes a context.
+func (i *Player) IsStoppedContext(ctx context.Context) (bool, error) {
+	return i.isStatus(ctx, PlaybackStopped)
+}
+
+// PlayPauseAndStatus calls PlayPause and returns the playback status the
+// player announces afterwards. Querying the status right after PlayPause races
+// with the transition inside the player, so the announcement is waited for,
+// up to a second. When none arrives in time, the status is queried after all.
+func (i *Player) PlayPauseAndStatus() (PlaybackStatus, error) {
+	return i.PlayPauseAndStatusContext(context.Background())
+}
+
+// PlayPauseAndStatusContext is like PlayPauseAndStatus but takes a context.
+func (i *Player) PlayPauseAndStatusContext(
+	ctx context.Context,
+) (PlaybackStatus, error) {
+	changed := make(chan PlaybackStatus, 1)
+	// Subscribe before calling PlayPause, so the announcement isn't missed.
+	sub, err := i.Subscribe(ctx, PlaybackStatusChanged(func(s PlaybackStatus) {
+		select {
+		case changed <- s:
+		default:
+		}
+	}))
+	if err != nil {
+		return "", err
+	}
+	defer sub.Close()
+
+	if err := i.PlayPauseContext(ctx); err != nil {
+		return "", err
+	}
+
+	timer := time.NewTimer(statusConfirmTimeout)
+	defer timer.Stop()
+	select {
+	case status := <-changed:
+		return status, nil
+	case <-timer.C:
+		return i.GetPlaybackStatusContext(ctx)
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// WaitForStatus blocks until the playback status of the player is want or ctx
+// is done. It returns right away when the status already is want.
+func (i *Player) WaitForStatus(ctx context.Context, want PlaybackStatus) error {
+	reached := make(chan struct{}, 1)
+	// Subscribe before checking the current status, so a change in between
+	// isn't missed.
+	sub, err := i.Subscribe(ctx, PlaybackStatusChanged(func(s PlaybackStatus) {
+		if s != want {
+			return
+		}
+		select {
+		case reached <- struct{}{}:
+		default:
+		}
+	}))
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	status, err := i.GetPlaybackStatusContext(ctx)
+	if err != nil {
+		return err
+	}
+	if status == want {
+		return nil
+	}
+
+	select {
+	case <-reached:
+		return nil
+	case <-sub.Done():
+		if err := sub.Err(); err != nil {
+			return err
+		}
+		return fmt.Errorf("waiting for playback status %s: %w", want, ctx.Err())
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for playback status %s: %w", want, ctx.Err())
+	}
+}
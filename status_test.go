@@ -0,0 +1,116 @@
+package mpris
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// slowPlayer implements PlayPause, switching between playing and paused a
+// moment after the call returns, like most real players.
+type slowPlayer struct {
+	props *testProperties
+}
+
+func (p *slowPlayer) PlayPause() *dbus.Error {
+	v, _ := p.props.Get(PlayerInterface, "PlaybackStatus")
+	next := "Playing"
+	if v.Value() == "Playing" {
+		next = "Paused"
+	}
+	time.AfterFunc(50*time.Millisecond, func() {
+		p.props.set(PlayerInterface, "PlaybackStatus", next)
+	})
+	return nil
+}
+
+func exportSlowPlayer(t *testing.T, server *dbus.Conn, status string) *testProperties {
+	t.Helper()
+	props := exportTestProperties(t, server, map[string]map[string]any{
+		PlayerInterface: {"PlaybackStatus": status},
+	})
+	p := &slowPlayer{props: props}
+	if err := server.Export(p, DBusObjectPath, PlayerInterface); err != nil {
+		t.Fatal(err)
+	}
+	return props
+}
+
+func TestIsPlaying(t *testing.T) {
+	server, player := testBus(t)
+	props := exportTestProperties(t, server, map[string]map[string]any{
+		PlayerInterface: {"PlaybackStatus": "Playing"},
+	})
+	for _, status := range []string{"Playing", "Paused", "Stopped"} {
+		props.set(PlayerInterface, "PlaybackStatus", status)
+		playing, _ := player.IsPlaying()
+		paused, _ := player.IsPaused()
+		stopped, _ := player.IsStopped()
+		got := []bool{playing, paused, stopped}
+		want := []bool{status == "Playing", status == "Paused", status == "Stopped"}
+		for n := range got {
+			if got[n] != want[n] {
+				t.Errorf("%s: Is* = %v, want %v", status, got, want)
+				break
+			}
+		}
+	}
+}
+
+func TestPlayPauseAndStatus(t *testing.T) {
+	server, player := testBus(t)
+	exportSlowPlayer(t, server, "Paused")
+
+	for _, want := range []PlaybackStatus{PlaybackPlaying, PlaybackPaused} {
+		status, err := player.PlayPauseAndStatus()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status != want {
+			t.Errorf("PlayPauseAndStatus() = %v, want %v", status, want)
+		}
+	}
+}
+
+func TestPlayPauseAndStatusWithoutSignal(t *testing.T) {
+	server, player := testBus(t)
+	props := exportSlowPlayer(t, server, "Paused")
+	props.setEmit("PlaybackStatus", emitNothing)
+
+	status, err := player.PlayPauseAndStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != PlaybackPlaying {
+		t.Errorf("PlayPauseAndStatus() = %v, want Playing", status)
+	}
+}
+
+func TestWaitForStatus(t *testing.T) {
+	server, player := testBus(t)
+	props := exportSlowPlayer(t, server, "Paused")
+
+	if err := player.WaitForStatus(t.Context(), PlaybackPaused); err != nil {
+		t.Errorf("WaitForStatus(Paused) while paused error = %v", err)
+	}
+
+	if err := player.PlayPause(); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+	if err := player.WaitForStatus(ctx, PlaybackPlaying); err != nil {
+		t.Errorf("WaitForStatus(Playing) error = %v", err)
+	}
+
+	props.set(PlayerInterface, "PlaybackStatus", "Stopped")
+	ctx, cancel = context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+	err := player.WaitForStatus(ctx, PlaybackPlaying)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WaitForStatus() error = %v, want DeadlineExceeded", err)
+	}
+}
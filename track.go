@@ -0,0 +1,103 @@
+package mpris
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// TrackChange describes a change of the current track.
+type TrackChange struct {
+	// OldTrackID is the mpris:trackid of the previous track, if any.
+	OldTrackID dbus.ObjectPath
+	// NewTrackID is the mpris:trackid of the new track, if any.
+	NewTrackID dbus.ObjectPath
+	// Metadata is the metadata of the new track. It is owned by the receiver.
+	Metadata Metadata
+	// Stamp tells when and in which order the metadata change was observed.
+	Stamp
+}
+
+// metadataString returns the value of key in m as a string, or "" when it is
+// missing or not castable.
+func metadataString(m Metadata, key string) string {
+	s, _ := m.GetString(key)
+	return s
+}
+
+// sameTrack returns whether a and b describe the same track. Track ids are
+// compared first. Since mpv reuses the same trackid across files in some
+// configurations, equal or missing track ids fall back to comparing xesam:url,
+// and then the title and artists.
+func sameTrack(a, b Metadata) bool {
+	aID, bID := metadataString(a, "mpris:trackid"), metadataString(b, "mpris:trackid")
+	if aID != "" && bID != "" && aID != bID {
+		return false
+	}
+
+	aURL, bURL := metadataString(a, "xesam:url"), metadataString(b, "xesam:url")
+	if aURL != "" && bURL != "" {
+		return aURL == bURL
+	}
+
+	if metadataString(a, "xesam:title") != metadataString(b, "xesam:title") {
+		return false
+	}
+	aArtist, _ := a.Get("xesam:artist")
+	bArtist, _ := b.Get("xesam:artist")
+	return fmt.Sprint(aArtist) == fmt.Sprint(bArtist)
+}
+
+// trackHandler sends a TrackChange to ch whenever the metadata describes a
+// different track than last. last is the metadata of the current track.
+func trackHandler(
+	last Metadata,
+	ch chan<- TrackChange,
+) func(context.Context, propertiesChanged) {
+	return func(ctx context.Context, pc propertiesChanged) {
+		v, ok := pc.changed["Metadata"]
+		if !ok {
+			return
+		}
+		m, err := toMetadata(v.Value())
+		if err != nil || sameTrack(last, m) {
+			return
+		}
+		change := TrackChange{
+			OldTrackID: dbus.ObjectPath(metadataString(last, "mpris:trackid")),
+			NewTrackID: dbus.ObjectPath(metadataString(m, "mpris:trackid")),
+			Metadata:   m.Clone(),
+			Stamp:      stampOf(ctx),
+		}
+		last = m
+		select {
+		case ch <- change:
+		case <-ctx.Done():
+		}
+	}
+}
+
+// WatchTrackChanged sends a TrackChange to ch every time the current track
+// changes, until the returned Subscription is closed. Unlike metadata
+// listeners, it ignores metadata updates that don't change the track, such as
+// new art or refined tags.
+func (i *Player) WatchTrackChanged(ch chan<- TrackChange) (*Subscription, error) {
+	// Without the current metadata the first change is always reported.
+	last, _ := i.GetMetadata()
+	return i.watchProperties(
+		PlayerInterface,
+		[]string{"Metadata"},
+		trackHandler(last.Clone(), ch),
+	)
+}
+
+// OnTrackChanged sends a TrackChange to ch every time the current track
+// changes, until ctx is canceled. See WatchTrackChanged.
+func (i *Player) OnTrackChanged(ctx context.Context, ch chan<- TrackChange) error {
+	sub, err := i.WatchTrackChanged(ch)
+	if err != nil {
+		return err
+	}
+	return sub.wait(ctx)
+}
@@ -0,0 +1,130 @@
+package mpris
+
+import (
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func track(fields map[string]any) Metadata {
+	m := Metadata{}
+	for k, v := range fields {
+		m[k] = dbus.MakeVariant(v)
+	}
+	return m
+}
+
+func TestSameTrack(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Metadata
+		want bool
+	}{
+		{
+			"same trackid",
+			track(map[string]any{"mpris:trackid": dbus.ObjectPath("/t/1")}),
+			track(map[string]any{"mpris:trackid": dbus.ObjectPath("/t/1")}),
+			true,
+		},
+		{
+			"different trackid",
+			track(map[string]any{"mpris:trackid": dbus.ObjectPath("/t/1")}),
+			track(map[string]any{"mpris:trackid": dbus.ObjectPath("/t/2")}),
+			false,
+		},
+		{
+			"reused trackid with different url",
+			track(map[string]any{
+				"mpris:trackid": dbus.ObjectPath("/io/mpv/playlist/0"),
+				"xesam:url":     "file:///a.mp3",
+			}),
+			track(map[string]any{
+				"mpris:trackid": dbus.ObjectPath("/io/mpv/playlist/0"),
+				"xesam:url":     "file:///b.mp3",
+			}),
+			false,
+		},
+		{
+			"same trackid with new art",
+			track(map[string]any{
+				"mpris:trackid": dbus.ObjectPath("/t/1"),
+				"xesam:url":     "file:///a.mp3",
+			}),
+			track(map[string]any{
+				"mpris:trackid": dbus.ObjectPath("/t/1"),
+				"xesam:url":     "file:///a.mp3",
+				"mpris:artUrl":  "file:///a.png",
+			}),
+			true,
+		},
+		{
+			"no ids, same url",
+			track(map[string]any{"xesam:url": "https://x/1"}),
+			track(map[string]any{"xesam:url": "https://x/1", "xesam:title": "T"}),
+			true,
+		},
+		{
+			"title and artist fallback, same",
+			track(map[string]any{"xesam:title": "T", "xesam:artist": []string{"A"}}),
+			track(map[string]any{"xesam:title": "T", "xesam:artist": []string{"A"}}),
+			true,
+		},
+		{
+			"title and artist fallback, different artist",
+			track(map[string]any{"xesam:title": "T", "xesam:artist": []string{"A"}}),
+			track(map[string]any{"xesam:title": "T", "xesam:artist": []string{"B"}}),
+			false,
+		},
+		{
+			"nothing to previous track",
+			Metadata{},
+			track(map[string]any{"xesam:title": "T"}),
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sameTrack(tt.a, tt.b); got != tt.want {
+				t.Errorf("sameTrack() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOnTrackChanged(t *testing.T) {
+	server, player := testBus(t)
+	props := exportTestProperties(t, server, map[string]map[string]any{
+		PlayerInterface: {
+			"Metadata": map[string]dbus.Variant(track(map[string]any{
+				"mpris:trackid": dbus.ObjectPath("/t/1"),
+			})),
+		},
+	})
+
+	ch := make(chan TrackChange, 2)
+	sub, err := player.WatchTrackChanged(ch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	props.set(PlayerInterface, "Metadata", map[string]dbus.Variant(track(map[string]any{
+		"mpris:trackid": dbus.ObjectPath("/t/1"),
+		"mpris:artUrl":  "file:///art.png",
+	})))
+	props.set(PlayerInterface, "Metadata", map[string]dbus.Variant(track(map[string]any{
+		"mpris:trackid": dbus.ObjectPath("/t/2"),
+		"xesam:title":   "Second",
+	})))
+
+	change := receive(t, ch)
+	if change.OldTrackID != "/t/1" || change.NewTrackID != "/t/2" {
+		t.Errorf("change = %s -> %s, want /t/1 -> /t/2",
+			change.OldTrackID, change.NewTrackID)
+	}
+	if title, _ := change.Metadata.Get("xesam:title"); title != "Second" {
+		t.Errorf("title = %v, want Second", title)
+	}
+	expectNothing(t, ch)
+}
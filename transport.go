@@ -0,0 +1,75 @@
+package mpris
+
+import (
+	"context"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// transport is what the package needs from a D-Bus connection: calling
+// methods and receiving signals. Players and subscriptions go through it
+// instead of using *dbus.Conn directly, so tests can record the calls and
+// signals of a session with a real player and replay them without a bus.
+type transport interface {
+	// call calls method on the object path of dest.
+	call(
+		ctx context.Context,
+		dest string,
+		path dbus.ObjectPath,
+		method string,
+		flags dbus.Flags,
+		args ...any,
+	) *dbus.Call
+	addMatchSignal(rule ...dbus.MatchOption) error
+	removeMatchSignal(rule ...dbus.MatchOption) error
+	// signal makes the transport deliver the signals it receives to ch.
+	signal(ch chan<- *dbus.Signal)
+	removeSignal(ch chan<- *dbus.Signal)
+	connected() bool
+}
+
+// busCall calls method of the bus itself, org.freedesktop.DBus.
+func busCall(
+	ctx context.Context,
+	tr transport,
+	method string,
+	args ...any,
+) *dbus.Call {
+	return tr.call(ctx, busName, "/org/freedesktop/DBus", method, 0, args...)
+}
+
+// connTransport is the transport of a godbus connection.
+type connTransport struct {
+	conn *dbus.Conn
+}
+
+func (t connTransport) call(
+	ctx context.Context,
+	dest string,
+	path dbus.ObjectPath,
+	method string,
+	flags dbus.Flags,
+	args ...any,
+) *dbus.Call {
+	return t.conn.Object(dest, path).CallWithContext(ctx, method, flags, args...)
+}
+
+func (t connTransport) addMatchSignal(rule ...dbus.MatchOption) error {
+	return t.conn.AddMatchSignal(rule...)
+}
+
+func (t connTransport) removeMatchSignal(rule ...dbus.MatchOption) error {
+	return t.conn.RemoveMatchSignal(rule...)
+}
+
+func (t connTransport) signal(ch chan<- *dbus.Signal) {
+	t.conn.Signal(ch)
+}
+
+func (t connTransport) removeSignal(ch chan<- *dbus.Signal) {
+	t.conn.RemoveSignal(ch)
+}
+
+func (t connTransport) connected() bool {
+	return t.conn.Connected()
+}
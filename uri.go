@@ -0,0 +1,152 @@
+package mpris
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// openConfirmTimeout bounds how long PlayURI waits for the player to load the
+// URI it was asked to open.
+const openConfirmTimeout = 2 * time.Second
+
+// containsFold returns whether list is empty, meaning unknown, or contains s
+// ignoring case.
+func containsFold(list []string, s string) bool {
+	return len(list) == 0 || slices.ContainsFunc(list, func(v string) bool {
+		return strings.EqualFold(v, s)
+	})
+}
+
+// SupportsURIScheme returns whether the player can open URIs with scheme,
+// ignoring case. An empty SupportedUriSchemes list means the player didn't
+// say, so every scheme is assumed to be supported.
+func (i *Player) SupportsURIScheme(scheme string) (bool, error) {
+	return i.SupportsURISchemeContext(context.Background(), scheme)
+}
+
+// SupportsURISchemeContext is like SupportsURIScheme but takes a context.
+func (i *Player) SupportsURISchemeContext(
+	ctx context.Context,
+	scheme string,
+) (bool, error) {
+	schemes, err := i.GetSupportedUriSchemesContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	return containsFold(schemes, scheme), nil
+}
+
+// SupportsMimeType returns whether the player can play the MIME type, ignoring
+// case and parameters such as "; charset=utf-8". An empty SupportedMimeTypes
+// list means the player didn't say, so every type is assumed to be supported.
+func (i *Player) SupportsMimeType(mimeType string) (bool, error) {
+	return i.SupportsMimeTypeContext(context.Background(), mimeType)
+}
+
+// SupportsMimeTypeContext is like SupportsMimeType but takes a context.
+func (i *Player) SupportsMimeTypeContext(
+	ctx context.Context,
+	mimeType string,
+) (bool, error) {
+	types, err := i.SupportedMimeTypesContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	if mediaType, _, err := mime.ParseMediaType(mimeType); err == nil {
+		mimeType = mediaType
+	}
+	return containsFold(types, mimeType), nil
+}
+
+// OpenURIChecked is like OpenURI, but first checks that the player supports
+// the scheme of uri. When it doesn't, the error wraps ErrNotSupported and
+// lists the schemes the player supports. An absolute path is opened as a
+// file:// URI.
+func (i *Player) OpenURIChecked(uri string) error {
+	return i.OpenURICheckedContext(context.Background(), uri)
+}
+
+// OpenURICheckedContext is like OpenURIChecked but takes a context.
+func (i *Player) OpenURICheckedContext(ctx context.Context, uri string) error {
+	if filepath.IsAbs(uri) {
+		uri = (&url.URL{Scheme: "file", Path: uri}).String()
+	}
+	u, err := url.Parse(uri)
+	if err != nil {
+		return fmt.Errorf("invalid URI %q: %w", uri, err)
+	}
+	if u.Scheme == "" {
+		return fmt.Errorf("invalid URI %q: no scheme", uri)
+	}
+	schemes, err := i.GetSupportedUriSchemesContext(ctx)
+	if err != nil {
+		return err
+	}
+	if !containsFold(schemes, u.Scheme) {
+		return fmt.Errorf(
+			"%w: %s can't open %s URIs, only %s",
+			ErrNotSupported,
+			i.name,
+			u.Scheme,
+			strings.Join(schemes, ", "),
+		)
+	}
+	return i.OpenURIContext(ctx, uri)
+}
+
+// PlayURI opens uri and makes sure it plays. Some players, like Chromium,
+// accept OpenUri but stay paused, so once the metadata shows the new URL, or
+// after two seconds, Play is called when the player isn't playing. PlayURI
+// returns whether that fallback Play was needed, so callers can log player
+// quirks.
+func (i *Player) PlayURI(uri string) (bool, error) {
+	return i.PlayURIContext(context.Background(), uri)
+}
+
+// PlayURIContext is like PlayURI but takes a context.
+func (i *Player) PlayURIContext(ctx context.Context, uri string) (bool, error) {
+	// A missing or unreadable URL only means any new URL counts as loaded.
+	before, _ := i.GetMetadataContext(ctx)
+	oldURL := metadataString(before, "xesam:url")
+
+	loaded := make(chan struct{}, 1)
+	// Subscribe before calling OpenUri, so the new metadata isn't missed.
+	sub, err := i.Subscribe(ctx, MetadataChanged(func(m Metadata) {
+		if u := metadataString(m, "xesam:url"); u == oldURL && u != uri {
+			return
+		}
+		select {
+		case loaded <- struct{}{}:
+		default:
+		}
+	}))
+	if err != nil {
+		return false, err
+	}
+	defer sub.Close()
+
+	if err := i.OpenURIContext(ctx, uri); err != nil {
+		return false, err
+	}
+
+	timer := time.NewTimer(openConfirmTimeout)
+	defer timer.Stop()
+	select {
+	case <-loaded:
+	case <-timer.C:
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+
+	playing, err := i.IsPlayingContext(ctx)
+	if err != nil || playing {
+		return false, err
+	}
+	return true, i.PlayContext(ctx)
+}
@@ -0,0 +1,153 @@
+package mpris
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// openingPlayer implements OpenUri, recording the URIs it was asked to open.
+type openingPlayer struct {
+	mu   sync.Mutex
+	uris []string
+}
+
+func (p *openingPlayer) OpenUri(uri string) *dbus.Error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.uris = append(p.uris, uri)
+	return nil
+}
+
+func (p *openingPlayer) opened() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Clone(p.uris)
+}
+
+func TestSupportsURISchemeAndMimeType(t *testing.T) {
+	server, player := testBus(t)
+	props := exportTestProperties(t, server, map[string]map[string]any{
+		BaseInterface: {
+			"SupportedUriSchemes": []string{"file", "HTTP"},
+			"SupportedMimeTypes":  []string{"audio/mpeg", "audio/ogg"},
+		},
+	})
+
+	for scheme, want := range map[string]bool{"file": true, "http": true, "smb": false} {
+		if got, err := player.SupportsURIScheme(scheme); err != nil || got != want {
+			t.Errorf("SupportsURIScheme(%s) = %v, %v, want %v", scheme, got, err, want)
+		}
+	}
+	for mime, want := range map[string]bool{
+		"audio/mpeg":               true,
+		"Audio/OGG":                true,
+		"audio/ogg; codecs=vorbis": true,
+		"video/mp4":                false,
+	} {
+		if got, err := player.SupportsMimeType(mime); err != nil || got != want {
+			t.Errorf("SupportsMimeType(%s) = %v, %v, want %v", mime, got, err, want)
+		}
+	}
+
+	props.set(BaseInterface, "SupportedUriSchemes", []string{})
+	if got, _ := player.SupportsURIScheme("smb"); !got {
+		t.Error("SupportsURIScheme() with an empty list = false, want true")
+	}
+}
+
+func TestOpenURIChecked(t *testing.T) {
+	server, player := testBus(t)
+	exportTestProperties(t, server, map[string]map[string]any{
+		BaseInterface: {"SupportedUriSchemes": []string{"file", "http"}},
+	})
+	fake := &openingPlayer{}
+	if err := server.Export(fake, DBusObjectPath, PlayerInterface); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, uri := range []string{"http://example.com/a.mp3", "/music/my song.mp3"} {
+		if err := player.OpenURIChecked(uri); err != nil {
+			t.Errorf("OpenURIChecked(%q) error = %v", uri, err)
+		}
+	}
+	want := []string{"http://example.com/a.mp3", "file:///music/my%20song.mp3"}
+	if got := fake.opened(); !slices.Equal(got, want) {
+		t.Errorf("opened %q, want %q", got, want)
+	}
+
+	err := player.OpenURIChecked("smb://nas/a.mp3")
+	if !errors.Is(err, ErrNotSupported) || !strings.Contains(err.Error(), "file, http") {
+		t.Errorf("OpenURIChecked(smb) error = %v", err)
+	}
+	if err := player.OpenURIChecked("a.mp3"); err == nil {
+		t.Error("OpenURIChecked() without scheme succeeded")
+	}
+	if got := fake.opened(); len(got) != 2 {
+		t.Errorf("opened %q after rejected URIs", got)
+	}
+}
+
+// loadingPlayer loads opened URIs into its metadata, starting playback only
+// when autoplay is set, like Chromium does not.
+type loadingPlayer struct {
+	props    *testProperties
+	autoplay bool
+	played   atomic.Bool
+}
+
+func (p *loadingPlayer) OpenUri(uri string) *dbus.Error {
+	p.props.set(PlayerInterface, "Metadata", map[string]dbus.Variant{
+		"xesam:url": dbus.MakeVariant(uri),
+	})
+	if p.autoplay {
+		p.props.set(PlayerInterface, "PlaybackStatus", string(PlaybackPlaying))
+	}
+	return nil
+}
+
+func (p *loadingPlayer) Play() *dbus.Error {
+	p.played.Store(true)
+	p.props.set(PlayerInterface, "PlaybackStatus", string(PlaybackPlaying))
+	return nil
+}
+
+func TestPlayURI(t *testing.T) {
+	for _, autoplay := range []bool{true, false} {
+		t.Run(fmt.Sprintf("autoplay=%v", autoplay), func(t *testing.T) {
+			server, player := testBus(t)
+			props := exportTestProperties(t, server, map[string]map[string]any{
+				PlayerInterface: {
+					"PlaybackStatus": string(PlaybackPaused),
+					"Metadata":       map[string]dbus.Variant{},
+				},
+			})
+			fake := &loadingPlayer{props: props, autoplay: autoplay}
+			if err := server.Export(fake, DBusObjectPath, PlayerInterface); err != nil {
+				t.Fatal(err)
+			}
+
+			start := time.Now()
+			fallback, err := player.PlayURI("file:///a.mp3")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fallback != !autoplay || fake.played.Load() != !autoplay {
+				t.Errorf("PlayURI() fallback = %v, played = %v", fallback, fake.played.Load())
+			}
+			if time.Since(start) >= openConfirmTimeout {
+				t.Error("PlayURI() waited for the timeout despite the new metadata")
+			}
+			if playing, _ := player.IsPlaying(); !playing {
+				t.Error("player isn't playing after PlayURI()")
+			}
+		})
+	}
+}
@@ -0,0 +1,423 @@
+package mpris
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/spf13/cast"
+)
+
+const (
+	// verifyPositionWait is how long Verify lets a playing player play to
+	// see its position advance. Some players only update Position every
+	// second.
+	verifyPositionWait = 1500 * time.Millisecond
+	// verifySeekedTimeout is how long Verify waits for Seeked after Seek.
+	verifySeekedTimeout = 2 * time.Second
+	// verifySeekOffset is how far Verify seeks, forward and back again.
+	verifySeekOffset = time.Second
+)
+
+// CheckResult is the outcome of a check of Verify.
+type CheckResult string
+
+//revive:disable:exported
+
+const (
+	CheckPass CheckResult = "pass"
+	CheckFail CheckResult = "fail"
+	CheckSkip CheckResult = "skip"
+)
+
+//revive:enable:exported
+
+// Check is a check made by Verify. Detail explains failures and skips.
+type Check struct {
+	Name   string
+	Result CheckResult
+	Detail string
+}
+
+// Report is the result of Verify, with the checks in the order they were
+// made.
+type Report struct {
+	Player string
+	Checks []Check
+}
+
+// OK reports whether no check failed.
+func (r Report) OK() bool {
+	return len(r.Failures()) == 0
+}
+
+// Failures returns the checks that failed.
+func (r Report) Failures() []Check {
+	var failed []Check
+	for _, c := range r.Checks {
+		if c.Result == CheckFail {
+			failed = append(failed, c)
+		}
+	}
+	return failed
+}
+
+// specProperty is a property of the spec, as checked by Verify.
+type specProperty struct {
+	name      string
+	signature string
+	optional  bool
+}
+
+// specProperties holds the properties of the interfaces Verify checks.
+var specProperties = map[string][]specProperty{
+	BaseInterface: {
+		{"CanQuit", "b", false},
+		{"Fullscreen", "b", true},
+		{"CanSetFullscreen", "b", true},
+		{"CanRaise", "b", false},
+		{"HasTrackList", "b", false},
+		{"Identity", "s", false},
+		{"DesktopEntry", "s", true},
+		{"SupportedUriSchemes", "as", false},
+		{"SupportedMimeTypes", "as", false},
+	},
+	PlayerInterface: {
+		{"PlaybackStatus", "s", false},
+		{"LoopStatus", "s", true},
+		{"Rate", "d", false},
+		{"Shuffle", "b", true},
+		{"Metadata", "a{sv}", false},
+		{"Volume", "d", false},
+		{"Position", "x", false},
+		{"MinimumRate", "d", false},
+		{"MaximumRate", "d", false},
+		{"CanGoNext", "b", false},
+		{"CanGoPrevious", "b", false},
+		{"CanPlay", "b", false},
+		{"CanPause", "b", false},
+		{"CanSeek", "b", false},
+		{"CanControl", "b", false},
+	},
+	TrackListInterface: {
+		{"Tracks", "ao", false},
+		{"CanEditTracks", "b", false},
+	},
+}
+
+// Verify probes player for compliance with the MPRIS spec: which interfaces
+// and properties it implements, whether the properties have the types of
+// the spec, whether Position advances while it plays, whether Seek is
+// followed by Seeked and whether the methods whose Can* property is false
+// indeed have no effect. Verify controls the player: it seeks and calls the
+// methods that should have no effect, and takes a few seconds. It fails only
+// when the player can't be reached; failed checks are in the Report.
+func Verify(player *Player) (Report, error) {
+	return VerifyContext(context.Background(), player)
+}
+
+// VerifyContext is like Verify but takes a context.
+func VerifyContext(ctx context.Context, player *Player) (Report, error) {
+	v := &verifier{ctx: ctx, player: player}
+	v.report.Player = player.GetName()
+
+	base, err := player.GetAllPropertiesContext(ctx, BaseInterface)
+	if err != nil {
+		return Report{}, err
+	}
+	v.pass("interface "+BaseInterface, "")
+	v.checkProperties(BaseInterface, base)
+
+	props, err := player.GetAllPropertiesContext(ctx, PlayerInterface)
+	if err != nil {
+		if ctx.Err() != nil {
+			return Report{}, err
+		}
+		v.fail("interface "+PlayerInterface, err.Error())
+		return v.report, nil
+	}
+	v.pass("interface "+PlayerInterface, "")
+	v.checkProperties(PlayerInterface, props)
+	v.checkTrackList(base)
+	v.checkValues(props)
+	v.checkCapabilities(props)
+	v.checkPosition(props)
+	v.checkSeeked(props)
+	return v.report, ctx.Err()
+}
+
+// verifier collects the checks of Verify.
+type verifier struct {
+	ctx    context.Context
+	player *Player
+	report Report
+}
+
+func (v *verifier) add(name string, result CheckResult, detail string) {
+	v.report.Checks = append(v.report.Checks, Check{name, result, detail})
+}
+
+func (v *verifier) pass(name, detail string) { v.add(name, CheckPass, detail) }
+func (v *verifier) fail(name, detail string) { v.add(name, CheckFail, detail) }
+func (v *verifier) skip(name, detail string) { v.add(name, CheckSkip, detail) }
+
+// checkProperties checks that the properties of iface exist with the types
+// of the spec.
+func (v *verifier) checkProperties(iface string, props map[string]dbus.Variant) {
+	for _, p := range specProperties[iface] {
+		name := "property " + iface + "." + p.name
+		value, ok := props[p.name]
+		switch {
+		case !ok && p.optional:
+			v.skip(name, "optional property not implemented")
+		case !ok:
+			v.fail(name, "required property missing")
+		case value.Signature().String() != p.signature:
+			v.fail(name, fmt.Sprintf(
+				"has type %s, want %s",
+				value.Signature(),
+				p.signature,
+			))
+		default:
+			v.pass(name, "")
+		}
+	}
+}
+
+// checkTrackList checks the TrackList interface when HasTrackList says the
+// player implements it.
+func (v *verifier) checkTrackList(base map[string]dbus.Variant) {
+	name := "interface " + TrackListInterface
+	if has, _ := cast.ToBoolE(base["HasTrackList"].Value()); !has {
+		v.skip(name, "HasTrackList is false")
+		return
+	}
+	props, err := v.player.GetAllPropertiesContext(v.ctx, TrackListInterface)
+	if err != nil {
+		v.fail(name, "HasTrackList is true, but "+err.Error())
+		return
+	}
+	v.pass(name, "")
+	v.checkProperties(TrackListInterface, props)
+}
+
+// checkValues checks that the properties with a fixed set of values, or a
+// range, hold valid ones.
+func (v *verifier) checkValues(props map[string]dbus.Variant) {
+	status := PlaybackStatus(cast.ToString(props["PlaybackStatus"].Value()))
+	statuses := []PlaybackStatus{PlaybackPlaying, PlaybackPaused, PlaybackStopped}
+	if slices.Contains(statuses, status) {
+		v.pass("PlaybackStatus value", "")
+	} else {
+		v.fail("PlaybackStatus value", fmt.Sprintf("unknown status %q", status))
+	}
+
+	if loop, ok := props["LoopStatus"]; !ok {
+		v.skip("LoopStatus value", "optional property not implemented")
+	} else {
+		status := LoopStatus(cast.ToString(loop.Value()))
+		if slices.Contains([]LoopStatus{LoopNone, LoopTrack, LoopPlaylist}, status) {
+			v.pass("LoopStatus value", "")
+		} else {
+			v.fail("LoopStatus value", fmt.Sprintf("unknown status %q", status))
+		}
+	}
+
+	minimum := cast.ToFloat64(props["MinimumRate"].Value())
+	maximum := cast.ToFloat64(props["MaximumRate"].Value())
+	rate := cast.ToFloat64(props["Rate"].Value())
+	switch {
+	case minimum > 1 || maximum < 1:
+		v.fail("Rate range", fmt.Sprintf(
+			"MinimumRate %v and MaximumRate %v must include 1",
+			minimum,
+			maximum,
+		))
+	case rate < minimum || rate > maximum:
+		v.fail("Rate range", fmt.Sprintf(
+			"Rate %v is outside [%v, %v]",
+			rate,
+			minimum,
+			maximum,
+		))
+	default:
+		v.pass("Rate range", "")
+	}
+}
+
+// canMethods maps the Can* properties to the method they allow.
+var canMethods = []struct {
+	property string
+	method   string
+	call     func(*Player, context.Context) error
+}{
+	{"CanGoNext", "Next", (*Player).NextContext},
+	{"CanGoPrevious", "Previous", (*Player).PreviousContext},
+	{"CanPlay", "Play", (*Player).PlayContext},
+	{"CanPause", "Pause", (*Player).PauseContext},
+	{"CanSeek", "Seek", func(p *Player, ctx context.Context) error {
+		return p.SeekContext(ctx, verifySeekOffset)
+	}},
+}
+
+// checkCapabilities checks that the Can* properties agree with CanControl,
+// and that the methods they forbid have no effect, as the spec asks.
+func (v *verifier) checkCapabilities(props map[string]dbus.Variant) {
+	control, _ := cast.ToBoolE(props["CanControl"].Value())
+	if !control {
+		var set []string
+		for _, c := range canMethods {
+			if can, _ := cast.ToBoolE(props[c.property].Value()); can {
+				set = append(set, c.property)
+			}
+		}
+		if len(set) > 0 {
+			v.fail("CanControl", fmt.Sprintf(
+				"CanControl is false, but %v are true",
+				set,
+			))
+		} else {
+			v.pass("CanControl", "")
+		}
+	}
+
+	for _, c := range canMethods {
+		name := c.property + " consistent with " + c.method
+		can, _ := cast.ToBoolE(props[c.property].Value())
+		switch {
+		case !control:
+			v.skip(name, "CanControl is false")
+			continue
+		case can:
+			v.skip(name, c.property+" is true")
+			continue
+		}
+		before, err := v.state()
+		if err != nil {
+			v.fail(name, err.Error())
+			continue
+		}
+		if err := c.call(v.player, v.ctx); err != nil {
+			v.fail(name, fmt.Sprintf(
+				"%s is false, so %s must have no effect, but it failed: %v",
+				c.property,
+				c.method,
+				err,
+			))
+			continue
+		}
+		after, err := v.state()
+		if err != nil {
+			v.fail(name, err.Error())
+			continue
+		}
+		if after != before {
+			v.fail(name, fmt.Sprintf(
+				"%s is false, but %s changed the player from %v to %v",
+				c.property,
+				c.method,
+				before,
+				after,
+			))
+			continue
+		}
+		v.pass(name, "")
+	}
+}
+
+// playerState is what the methods forbidden by Can* properties must not
+// change.
+type playerState struct {
+	Status  PlaybackStatus
+	TrackID string
+}
+
+func (v *verifier) state() (playerState, error) {
+	props, err := v.player.GetAllPropertiesContext(v.ctx, PlayerInterface)
+	if err != nil {
+		return playerState{}, err
+	}
+	m, _ := props["Metadata"].Value().(map[string]dbus.Variant)
+	return playerState{
+		Status:  PlaybackStatus(cast.ToString(props["PlaybackStatus"].Value())),
+		TrackID: metadataString(Metadata(m), "mpris:trackid"),
+	}, nil
+}
+
+// checkPosition checks that Position advances while the player plays.
+func (v *verifier) checkPosition(props map[string]dbus.Variant) {
+	const name = "Position advances while Playing"
+	status := cast.ToString(props["PlaybackStatus"].Value())
+	if status != string(PlaybackPlaying) {
+		v.skip(name, "the player isn't playing")
+		return
+	}
+	before, err := v.player.GetPositionContext(v.ctx)
+	if err != nil {
+		v.fail(name, err.Error())
+		return
+	}
+	select {
+	case <-time.After(verifyPositionWait):
+	case <-v.ctx.Done():
+		return
+	}
+	after, err := v.player.GetPositionContext(v.ctx)
+	if err != nil {
+		v.fail(name, err.Error())
+		return
+	}
+	if after <= before {
+		v.fail(name, fmt.Sprintf(
+			"Position went from %v to %v in %v",
+			before,
+			after,
+			verifyPositionWait,
+		))
+		return
+	}
+	v.pass(name, "")
+}
+
+// checkSeeked checks that Seek is followed by Seeked. The player seeks back
+// afterwards.
+func (v *verifier) checkSeeked(props map[string]dbus.Variant) {
+	const name = "Seeked after Seek"
+	if can, _ := cast.ToBoolE(props["CanSeek"].Value()); !can {
+		v.skip(name, "CanSeek is false")
+		return
+	}
+	status := cast.ToString(props["PlaybackStatus"].Value())
+	if status == string(PlaybackStopped) {
+		v.skip(name, "the player is stopped")
+		return
+	}
+
+	seeked := make(chan time.Duration, 1)
+	sub, err := v.player.Subscribe(v.ctx, Seeked(func(p time.Duration) {
+		select {
+		case seeked <- p:
+		default:
+		}
+	}))
+	if err != nil {
+		v.fail(name, err.Error())
+		return
+	}
+	defer sub.Close()
+	if err := v.player.SeekContext(v.ctx, verifySeekOffset); err != nil {
+		v.fail(name, err.Error())
+		return
+	}
+	defer v.player.SeekContext(v.ctx, -verifySeekOffset)
+
+	select {
+	case <-seeked:
+		v.pass(name, "")
+	case <-time.After(verifySeekedTimeout):
+		v.fail(name, fmt.Sprintf("no Seeked within %v", verifySeekedTimeout))
+	case <-v.ctx.Done():
+	}
+}
@@ -0,0 +1,111 @@
+package mpris_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Nadim147c/go-mpris"
+	"github.com/Nadim147c/go-mpris/mpristest"
+)
+
+// results returns the result of every check of r by name.
+func results(r mpris.Report) map[string]mpris.CheckResult {
+	m := map[string]mpris.CheckResult{}
+	for _, c := range r.Checks {
+		m[c.Name] = c.Result
+	}
+	return m
+}
+
+func TestVerifyCompliant(t *testing.T) {
+	fake, player := mpristest.NewFakePlayer(t)
+	fake.SetPlayer("PlaybackStatus", "Playing")
+	fake.Handle("Seek", func(f *mpristest.FakePlayer, args []any) error {
+		f.EmitSeeked(time.Duration(args[0].(int64)) * time.Microsecond)
+		return nil
+	})
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	go func() {
+		start := time.Now()
+		for ctx.Err() == nil {
+			fake.SetPosition(time.Since(start))
+			time.Sleep(50 * time.Millisecond)
+		}
+	}()
+
+	report, err := mpris.Verify(player)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.OK() {
+		t.Errorf("Verify() failures = %+v", report.Failures())
+	}
+	got := results(report)
+	for name, want := range map[string]mpris.CheckResult{
+		"property org.mpris.MediaPlayer2.Player.Volume": mpris.CheckPass,
+		"interface org.mpris.MediaPlayer2.TrackList":    mpris.CheckSkip,
+		"Position advances while Playing":               mpris.CheckPass,
+		"Seeked after Seek":                             mpris.CheckPass,
+		"CanGoNext consistent with Next":                mpris.CheckSkip,
+	} {
+		if got[name] != want {
+			t.Errorf("%s = %q, want %q", name, got[name], want)
+		}
+	}
+}
+
+func TestVerifyViolations(t *testing.T) {
+	fake, player := mpristest.NewFakePlayer(t)
+	fake.SetPlayer("PlaybackStatus", "Playing")
+	fake.SetPlayer("Volume", "loud")
+	fake.SetPlayer("CanGoNext", false)
+	fake.DropProperty("SupportedMimeTypes")
+	fake.DropProperty("Shuffle")
+	fake.Handle("Next", func(f *mpristest.FakePlayer, _ []any) error {
+		f.SetPlayer("PlaybackStatus", "Paused")
+		return nil
+	})
+
+	report, err := mpris.Verify(player)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.OK() {
+		t.Error("OK() = true for a player violating the spec")
+	}
+	got := results(report)
+	for name, want := range map[string]mpris.CheckResult{
+		"property org.mpris.MediaPlayer2.Player.Volume":         mpris.CheckFail,
+		"property org.mpris.MediaPlayer2.SupportedMimeTypes":    mpris.CheckFail,
+		"property org.mpris.MediaPlayer2.Player.Shuffle":        mpris.CheckSkip,
+		"property org.mpris.MediaPlayer2.Player.PlaybackStatus": mpris.CheckPass,
+		"CanGoNext consistent with Next":                        mpris.CheckFail,
+		"Position advances while Playing":                       mpris.CheckFail,
+		"Seeked after Seek":                                     mpris.CheckFail,
+	} {
+		if got[name] != want {
+			t.Errorf("%s = %q, want %q", name, got[name], want)
+		}
+	}
+	for _, c := range report.Failures() {
+		if c.Detail == "" {
+			t.Errorf("failed check %s has no detail", c.Name)
+		}
+	}
+	if detail := report.Failures()[0].Detail; !strings.Contains(detail, "missing") {
+		t.Errorf("first failure detail = %q", detail)
+	}
+}
+
+func TestVerifyGone(t *testing.T) {
+	fake, _ := mpristest.NewFakePlayer(t)
+	player := mpris.New(fake.Connect(), mpris.BaseInterface+".gone")
+
+	if _, err := mpris.Verify(player); !errors.Is(err, mpris.ErrPlayerGone) {
+		t.Errorf("Verify() error = %v, want ErrPlayerGone", err)
+	}
+}
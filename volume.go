@@ -0,0 +1,229 @@
+package mpris
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// WithVolumeLimit sets the highest volume AdjustVolume sets to limit instead
+// of 1, raising it for players that allow amplification or lowering it to
+// protect the listener, and makes SetVolume clamp the volume to [0, limit].
+// The spec asks players to treat negative volumes as 0, which not all of them
+// do.
+func WithVolumeLimit(limit float64) Option {
+	return func(p *Player) {
+		p.maxVolume = limit
+		p.clampVolume = true
+	}
+}
+
+// clamp returns volume limited to [0, maxVolume].
+func (i *Player) clamp(volume float64) float64 {
+	return min(max(volume, 0), i.maxVolume)
+}
+
+// AdjustVolume adds delta to the volume, clamped to [0, 1] or to the limit
+// set by WithVolumeLimit, and returns the new volume.
+//
+// The volume is read and written in two separate calls, so a change made by
+// someone else in between is overwritten. This is best-effort: fine for
+// volume keys, but not a compare-and-swap.
+func (i *Player) AdjustVolume(delta float64) (float64, error) {
+	return i.AdjustVolumeContext(context.Background(), delta)
+}
+
+// AdjustVolumeContext is like AdjustVolume but takes a context.
+func (i *Player) AdjustVolumeContext(
+	ctx context.Context,
+	delta float64,
+) (float64, error) {
+	volume, err := i.GetVolumeContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	volume = i.clamp(volume + delta)
+	if err := i.SetVolumeContext(ctx, volume); err != nil {
+		return 0, err
+	}
+	return volume, nil
+}
+
+// mutedVolume is the highest volume IsMuted treats as muted, as players
+// don't always report exactly 0 after being set to it.
+const mutedVolume = 1e-3
+
+// IsMuted returns whether the volume is 0, give or take a rounding error.
+func (i *Player) IsMuted() (bool, error) {
+	return i.IsMutedContext(context.Background())
+}
+
+// IsMutedContext is like IsMuted but takes a context.
+func (i *Player) IsMutedContext(ctx context.Context) (bool, error) {
+	volume, err := i.GetVolumeContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	return volume <= mutedVolume, nil
+}
+
+// Mute sets the volume to 0, remembering the current volume for Unmute. Muting
+// a muted player does nothing.
+func (i *Player) Mute() error {
+	return i.MuteContext(context.Background())
+}
+
+// MuteContext is like Mute but takes a context.
+func (i *Player) MuteContext(ctx context.Context) error {
+	volume, err := i.GetVolumeContext(ctx)
+	if err != nil {
+		return err
+	}
+	if volume <= mutedVolume {
+		return nil
+	}
+	if err := i.SetVolumeContext(ctx, 0); err != nil {
+		return err
+	}
+	i.mu.Lock()
+	i.unmuteVolume, i.hasUnmuteVolume = volume, true
+	i.mu.Unlock()
+	return nil
+}
+
+// Unmute restores the volume remembered by Mute, or sets it to 1 when the
+// player was muted some other way. When the volume was changed by someone
+// else since Mute, it is left alone.
+func (i *Player) Unmute() error {
+	return i.UnmuteContext(context.Background())
+}
+
+// UnmuteContext is like Unmute but takes a context.
+func (i *Player) UnmuteContext(ctx context.Context) error {
+	volume, err := i.GetVolumeContext(ctx)
+	if err != nil {
+		return err
+	}
+	i.mu.Lock()
+	restore, ok := i.unmuteVolume, i.hasUnmuteVolume
+	i.hasUnmuteVolume = false
+	i.mu.Unlock()
+	if volume > mutedVolume {
+		return nil
+	}
+	if !ok {
+		restore = 1
+	}
+	return i.SetVolumeContext(ctx, restore)
+}
+
+// ToggleMute mutes the player when it isn't muted and unmutes it otherwise,
+// then returns whether it is muted now. See Mute and Unmute.
+func (i *Player) ToggleMute() (bool, error) {
+	return i.ToggleMuteContext(context.Background())
+}
+
+// ToggleMuteContext is like ToggleMute but takes a context.
+func (i *Player) ToggleMuteContext(ctx context.Context) (bool, error) {
+	muted, err := i.IsMutedContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	toggle := i.MuteContext
+	if muted {
+		toggle = i.UnmuteContext
+	}
+	if err := toggle(ctx); err != nil {
+		return muted, err
+	}
+	return !muted, nil
+}
+
+const (
+	// fadeStep is the interval between volume changes of FadePause.
+	fadeStep = 100 * time.Millisecond
+	// fadeTolerance is how far the volume read back during a fade may be from
+	// the one written before the player counts as clamping or ignoring volume
+	// writes. Players commonly round to whole percents.
+	fadeTolerance = 0.02
+	// fadeRestoreTimeout bounds restoring the volume after a cancelled fade,
+	// whose context can't be used for that anymore.
+	fadeRestoreTimeout = time.Second
+)
+
+// FadePause fades the volume out over the given duration, pauses the player
+// and restores the original volume, so the next Play isn't silent. When the
+// player clamps or ignores the volume writes, the fade is abandoned and the
+// player is just paused. When ctx is done, the fade stops right away, the
+// volume is restored and ctx.Err() is returned.
+func (i *Player) FadePause(ctx context.Context, over time.Duration) error {
+	return i.FadePauseEvery(ctx, over, fadeStep)
+}
+
+// FadePauseEvery is like FadePause but changes the volume every step instead
+// of every 100 milliseconds.
+func (i *Player) FadePauseEvery(
+	ctx context.Context,
+	over, step time.Duration,
+) error {
+	if step <= 0 {
+		return fmt.Errorf("%w: fade step %v", ErrOutOfRange, step)
+	}
+	original, err := i.GetVolumeContext(ctx)
+	if err != nil {
+		return err
+	}
+	if err := i.fade(ctx, original, over, step); err != nil {
+		if ctx.Err() == nil {
+			return err
+		}
+		restoreCtx, cancel := context.WithTimeout(
+			context.WithoutCancel(ctx),
+			fadeRestoreTimeout,
+		)
+		defer cancel()
+		return errors.Join(ctx.Err(), i.SetVolumeContext(restoreCtx, original))
+	}
+	if err := i.PauseContext(ctx); err != nil {
+		return errors.Join(err, i.SetVolumeContext(ctx, original))
+	}
+	return i.SetVolumeContext(ctx, original)
+}
+
+// fade lowers the volume from original to 0 over the given duration in steps.
+// When the player fails, clamps or ignores a volume write, fade gives up and
+// returns nil, so the player is just paused. It only fails when ctx is done or
+// the player is gone.
+func (i *Player) fade(
+	ctx context.Context,
+	original float64,
+	over, step time.Duration,
+) error {
+	steps := max(int(over/step), 1)
+	ticker := time.NewTicker(step)
+	defer ticker.Stop()
+	for n := 1; n <= steps; n++ {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		want := original * float64(steps-n) / float64(steps)
+		// A write cut off by ctx may still be applied after the restore, so
+		// every write is waited for.
+		err := i.SetVolumeContext(context.WithoutCancel(ctx), want)
+		got, getErr := i.GetVolumeContext(ctx)
+		if err = errors.Join(err, getErr); err != nil {
+			if ctx.Err() != nil || errors.Is(err, ErrPlayerGone) {
+				return err
+			}
+			return nil
+		}
+		if math.Abs(got-want) > fadeTolerance {
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package mpris
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestAdjustVolume(t *testing.T) {
+	server, client := testBus(t)
+	exportTestProperties(t, server, map[string]map[string]any{
+		PlayerInterface: {"Volume": 0.5},
+	})
+
+	tests := []struct {
+		opts  []Option
+		delta float64
+		want  float64
+	}{
+		{nil, 0.25, 0.75},
+		{nil, 0.5, 1},
+		{nil, -2, 0},
+		{nil, 0.1, 0.1},
+		{[]Option{WithVolumeLimit(1.5)}, 2, 1.5},
+		{[]Option{WithVolumeLimit(1.5)}, -0.5, 1},
+		{[]Option{WithVolumeLimit(0.8)}, 0.5, 0.8},
+	}
+	for _, tt := range tests {
+		player := New(client.conn, testPlayerName, tt.opts...)
+		got, err := player.AdjustVolume(tt.delta)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("AdjustVolume(%v) = %v, want %v", tt.delta, got, tt.want)
+		}
+		if volume, _ := player.GetVolume(); volume != tt.want {
+			t.Errorf("volume after AdjustVolume(%v) = %v, want %v",
+				tt.delta, volume, tt.want)
+		}
+	}
+}
+
+func TestSetVolumeClamping(t *testing.T) {
+	server, client := testBus(t)
+	exportTestProperties(t, server, map[string]map[string]any{
+		PlayerInterface: {"Volume": 0.5},
+	})
+
+	unclamped := New(client.conn, testPlayerName)
+	if err := unclamped.SetVolume(-1); err != nil {
+		t.Fatal(err)
+	}
+	if volume, _ := unclamped.GetVolume(); volume != -1 {
+		t.Errorf("volume = %v, want -1 without WithVolumeLimit", volume)
+	}
+
+	clamped := New(client.conn, testPlayerName, WithVolumeLimit(1))
+	for in, want := range map[float64]float64{-1: 0, 2: 1, 0.3: 0.3} {
+		if err := clamped.SetVolume(in); err != nil {
+			t.Fatal(err)
+		}
+		if volume, _ := clamped.GetVolume(); volume != want {
+			t.Errorf("volume after SetVolume(%v) = %v, want %v", in, volume, want)
+		}
+	}
+}
+
+func TestMute(t *testing.T) {
+	server, player := testBus(t)
+	props := exportTestProperties(t, server, map[string]map[string]any{
+		PlayerInterface: {"Volume": 0.6},
+	})
+	volume := func() float64 {
+		t.Helper()
+		v, err := player.GetVolume()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	if err := player.Mute(); err != nil {
+		t.Fatal(err)
+	}
+	if muted, _ := player.IsMuted(); !muted || volume() != 0 {
+		t.Errorf("after Mute: muted = %v, volume = %v", muted, volume())
+	}
+	// Muting twice must not forget the volume to restore.
+	if err := player.Mute(); err != nil {
+		t.Fatal(err)
+	}
+	if err := player.Unmute(); err != nil {
+		t.Fatal(err)
+	}
+	if volume() != 0.6 {
+		t.Errorf("volume after Unmute = %v, want 0.6", volume())
+	}
+
+	muted, err := player.ToggleMute()
+	if err != nil || !muted {
+		t.Errorf("ToggleMute() = %v, %v, want muted", muted, err)
+	}
+	muted, err = player.ToggleMute()
+	if err != nil || muted {
+		t.Errorf("ToggleMute() = %v, %v, want unmuted", muted, err)
+	}
+	if volume() != 0.6 {
+		t.Errorf("volume after toggling twice = %v, want 0.6", volume())
+	}
+
+	// The user turns the volume up while muted; Unmute keeps it.
+	if err := player.Mute(); err != nil {
+		t.Fatal(err)
+	}
+	props.set(PlayerInterface, "Volume", 0.3)
+	if err := player.Unmute(); err != nil {
+		t.Fatal(err)
+	}
+	if volume() != 0.3 {
+		t.Errorf("volume after external change = %v, want 0.3", volume())
+	}
+
+	// Muted by someone else, nothing to restore.
+	props.set(PlayerInterface, "Volume", 0.0001)
+	if muted, _ := player.IsMuted(); !muted {
+		t.Error("IsMuted() = false for a volume close to 0")
+	}
+	if err := player.Unmute(); err != nil {
+		t.Fatal(err)
+	}
+	if volume() != 1 {
+		t.Errorf("volume after Unmute without Mute = %v, want 1", volume())
+	}
+}
+
+// fadingPlayer records the volume writes and the volume the player was paused
+// at. With ignore set, volume writes are accepted but have no effect.
+type fadingPlayer struct {
+	*testProperties
+	ignore bool
+
+	mu       sync.Mutex
+	written  []float64
+	paused   bool
+	pausedAt float64
+}
+
+func (p *fadingPlayer) Set(iface, name string, v dbus.Variant) *dbus.Error {
+	p.mu.Lock()
+	p.written = append(p.written, v.Value().(float64))
+	p.mu.Unlock()
+	if p.ignore {
+		return nil
+	}
+	return p.testProperties.Set(iface, name, v)
+}
+
+func (p *fadingPlayer) Pause() *dbus.Error {
+	v, _ := p.Get(PlayerInterface, "Volume")
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paused, p.pausedAt = true, v.Value().(float64)
+	return nil
+}
+
+func exportFadingPlayer(t *testing.T, server *dbus.Conn, ignore bool) *fadingPlayer {
+	t.Helper()
+	props := exportTestProperties(t, server, map[string]map[string]any{
+		PlayerInterface: {"Volume": 0.8},
+	})
+	p := &fadingPlayer{testProperties: props, ignore: ignore}
+	if err := server.Export(p, DBusObjectPath, "org.freedesktop.DBus.Properties"); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.Export(p, DBusObjectPath, PlayerInterface); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestFadePause(t *testing.T) {
+	server, player := testBus(t)
+	fake := exportFadingPlayer(t, server, false)
+
+	err := player.FadePauseEvery(context.Background(), 50*time.Millisecond, 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake.mu.Lock()
+	written, paused, pausedAt := fake.written, fake.paused, fake.pausedAt
+	fake.mu.Unlock()
+	if !paused || pausedAt != 0 {
+		t.Errorf("paused = %v at volume %v, want paused at 0", paused, pausedAt)
+	}
+	if len(written) != 6 || !slices.IsSortedFunc(written[:5], func(a, b float64) int {
+		return cmp.Compare(b, a)
+	}) {
+		t.Errorf("volume writes = %v, want a fade to 0 and a restore", written)
+	}
+	if volume, _ := player.GetVolume(); volume != 0.8 {
+		t.Errorf("volume after FadePause() = %v, want 0.8", volume)
+	}
+}
+
+func TestFadePauseIgnoredVolume(t *testing.T) {
+	server, player := testBus(t)
+	fake := exportFadingPlayer(t, server, true)
+
+	if err := player.FadePause(context.Background(), time.Second); err != nil {
+		t.Fatal(err)
+	}
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if !fake.paused {
+		t.Error("player isn't paused after FadePause()")
+	}
+	// The fade is abandoned after the first write, which is then restored.
+	if len(fake.written) != 2 {
+		t.Errorf("volume writes = %v, want the fade abandoned", fake.written)
+	}
+}
+
+func TestFadePauseCancel(t *testing.T) {
+	server, player := testBus(t)
+	fake := exportFadingPlayer(t, server, false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := player.FadePauseEvery(ctx, time.Second, 10*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("FadePause() error = %v, want DeadlineExceeded", err)
+	}
+	fake.mu.Lock()
+	paused := fake.paused
+	fake.mu.Unlock()
+	if paused {
+		t.Error("player paused after cancelled FadePause()")
+	}
+	if volume, _ := player.GetVolume(); volume != 0.8 {
+		t.Errorf("volume after cancelled FadePause() = %v, want 0.8", volume)
+	}
+}
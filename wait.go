@@ -0,0 +1,85 @@
+package mpris
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// NamePrefix returns a matcher for WaitForPlayer accepting the players whose
+// bus name, without the org.mpris.MediaPlayer2 prefix, starts with prefix.
+// NamePrefix("chromium") matches every Chromium instance.
+func NamePrefix(prefix string) func(busName string) bool {
+	return func(busName string) bool {
+		return strings.HasPrefix(
+			strings.TrimPrefix(busName, BaseInterface+"."),
+			prefix,
+		)
+	}
+}
+
+// WaitForPlayer blocks until a player whose bus name is accepted by match is
+// on the bus and returns it. It returns right away when such a player is
+// already running. When ctx expires first, the error wraps both
+// ErrPlayerNotFound and the context error.
+func WaitForPlayer(
+	ctx context.Context,
+	conn *dbus.Conn,
+	match func(busName string) bool,
+) (*Player, error) {
+	found := make(chan string, 1)
+	rule := []dbus.MatchOption{
+		dbus.WithMatchSender(busName),
+		dbus.WithMatchInterface(busName),
+		dbus.WithMatchMember("NameOwnerChanged"),
+		dbus.WithMatchArg0Namespace(BaseInterface),
+	}
+	filter := func(sig *dbus.Signal) bool {
+		return sig.Sender == busName && sig.Name == nameOwnerChangedSignal
+	}
+	handle := func(_ context.Context, sig *dbus.Signal) {
+		var name, oldOwner, newOwner string
+		if err := dbus.Store(sig.Body, &name, &oldOwner, &newOwner); err != nil {
+			return
+		}
+		if newOwner == "" || !isPlayerName(name) || !match(name) {
+			return
+		}
+		select {
+		case found <- name:
+		default:
+		}
+	}
+	// Subscribe before listing the names, so a player appearing in between
+	// is not missed.
+	sub, err := subscribe(connTransport{conn}, [][]dbus.MatchOption{rule}, filter, handle)
+	if err != nil {
+		return nil, err
+	}
+	defer sub.Close()
+
+	names, err := List(conn)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if match(name) {
+			return New(conn, name), nil
+		}
+	}
+
+	select {
+	case name := <-found:
+		return New(conn, name), nil
+	case <-sub.Done():
+		return nil, sub.Err()
+	case <-ctx.Done():
+		return nil, fmt.Errorf(
+			"%w: gave up waiting: %w",
+			ErrPlayerNotFound,
+			ctx.Err(),
+		)
+	}
+}
@@ -0,0 +1,68 @@
+package mpris
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNamePrefix(t *testing.T) {
+	match := NamePrefix("chromium")
+	for name, want := range map[string]bool{
+		BaseInterface + ".chromium":           true,
+		BaseInterface + ".chromium.instance2": true,
+		BaseInterface + ".spotify":            false,
+		"chromium":                            true,
+	} {
+		if got := match(name); got != want {
+			t.Errorf("NamePrefix(chromium)(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestWaitForPlayer(t *testing.T) {
+	addr := testBusAddress(t)
+	conn := testConn(t, addr)
+
+	// Already running.
+	claimName(t, addr, BaseInterface+".vlc")
+	player, err := WaitForPlayer(context.Background(), conn, NamePrefix("vlc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if player.GetName() != BaseInterface+".vlc" {
+		t.Errorf("player = %s, want vlc", player.GetName())
+	}
+
+	// Started later.
+	type result struct {
+		player *Player
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		player, err := WaitForPlayer(ctx, conn, NamePrefix("spotify"))
+		done <- result{player, err}
+	}()
+	time.Sleep(50 * time.Millisecond)
+	claimName(t, addr, BaseInterface+".other")
+	claimName(t, addr, BaseInterface+".spotify")
+	res := receive(t, done)
+	if res.err != nil {
+		t.Fatal(res.err)
+	}
+	if res.player.GetName() != BaseInterface+".spotify" {
+		t.Errorf("player = %s, want spotify", res.player.GetName())
+	}
+
+	// Never started.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = WaitForPlayer(ctx, conn, NamePrefix("mpd"))
+	if !errors.Is(err, ErrPlayerNotFound) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WaitForPlayer() error = %v", err)
+	}
+}